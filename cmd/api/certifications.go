@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/server/handlers"
+)
+
+// expireCertifications marks approved certifications past their expiry
+// date as expired, notifying their owners.
+func expireCertifications(ctx context.Context, app *util.Application) error {
+	certs, err := app.Repositories.User.ExpireCertifications()
+	if err != nil {
+		return err
+	}
+
+	for _, cert := range certs {
+		notification := models.Notification{
+			Type:    models.NOTIFICATION_TYPE_CERTIFICATION,
+			UserID:  cert.UserID,
+			Message: handlers.CertificationMessage(&cert),
+		}
+		err := app.Notifier.Dispatch(&notification)
+		if err != nil {
+			log.Printf("certification expiry %s: %v\n", cert.ID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/repository"
+	"github.com/lokatalent/backend_go/internal/throttle"
+)
+
+const (
+	// number of accounts deleted per run.
+	accountDeletionBatchSize = 50
+	// delay before an account failing to be deleted is retried.
+	accountDeletionRetryDelay = 24 * time.Hour
+)
+
+// deleteDueAccounts anonymizes the accounts whose deletion grace period
+// has ended. Accounts failing to be deleted are rescheduled, so that they
+// are retried later without holding back the accounts due after them.
+func deleteDueAccounts(ctx context.Context, app *util.Application) error {
+	deletions, err := app.Repositories.User.GetDueDeletions(accountDeletionBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, deletion := range deletions {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		err := deleteAccount(app.Repositories, deletion.UserID)
+		if err == nil {
+			continue
+		}
+		log.Printf("account deletion %s: %v\n", deletion.UserID, err)
+		err = app.Repositories.User.RescheduleDeletion(
+			deletion.UserID,
+			time.Now().Add(accountDeletionRetryDelay),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteAccount removes the user's uploaded files, then anonymizes their
+// account. Files are removed first so that a failure leaves the account
+// due for deletion, to be retried.
+func deleteAccount(repos *repository.Repositories, userID string) error {
+	user, err := repos.User.GetByID(userID)
+	if err != nil {
+		return err
+	}
+	active, err := repos.User.HasActiveBookings(userID)
+	if err != nil {
+		return err
+	}
+	if active {
+		return repository.ErrActiveBookings
+	}
+
+	keys := []string{}
+	if user.Avatar != "" {
+		keys = append(keys, user.AvatarPath())
+	}
+	certs, err := repos.User.GetCertifications(userID)
+	if err != nil {
+		return err
+	}
+	for _, cert := range certs {
+		keys = append(keys, cert.CertificationPath())
+	}
+	services, err := repos.User.GetAllServices(userID)
+	if err != nil {
+		return err
+	}
+	for _, service := range services {
+		images, err := repos.User.GetServiceImages(userID, service.ServiceType)
+		if err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
+			return err
+		}
+		for _, image := range images {
+			keys = append(keys, image.ServiceImagePath())
+		}
+	}
+
+	filter := models.KYCFilter{
+		UserID: userID,
+		Page:   1,
+		Limit:  models.DefaultPageLimit,
+	}
+	for {
+		submissions, err := repos.KYC.GetAll(filter)
+		if err != nil {
+			return err
+		}
+		for _, submission := range submissions {
+			keys = append(keys, submission.DocumentKey, submission.SelfieKey)
+		}
+		if len(submissions) < filter.Limit {
+			break
+		}
+		filter.Page++
+	}
+
+	for _, key := range keys {
+		if err := repos.Storage.DeleteFile(key); err != nil {
+			return err
+		}
+	}
+
+	if err := repos.User.Anonymize(userID); err != nil {
+		return err
+	}
+	return repos.Throttle.Reset(throttle.AccountKey(userID))
+}
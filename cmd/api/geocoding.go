@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/geo"
+	"github.com/lokatalent/backend_go/internal/repository"
+)
+
+// number of services loaded at once for geocoding.
+const geocodingBatchSize = 100
+
+// geocodeServices fills in the coordinates of services saved without
+// them, which are left out of provider matching. Addresses that can't be
+// located are counted and retried on the next run.
+func geocodeServices(ctx context.Context, app *util.Application) error {
+	geocoded, unlocated := 0, 0
+	defer func() {
+		if geocoded > 0 || unlocated > 0 {
+			log.Printf(
+				"geocoded %d services, %d services can't be located and won't be matched\n",
+				geocoded,
+				unlocated,
+			)
+		}
+	}()
+
+	afterID := ""
+	for {
+		services, err := app.Repositories.User.GetUngeocodedServices(afterID, geocodingBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(services) == 0 {
+			return nil
+		}
+
+		for _, service := range services {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			afterID = service.ID
+
+			location, err := app.Geocoder.Geocode(service.Address)
+			if err != nil {
+				if errors.Is(err, geo.ErrAddressNotFound) {
+					unlocated++
+					continue
+				}
+				return err
+			}
+			err = app.Repositories.User.UpdateServiceLocation(service.ID, location)
+			if err != nil {
+				// the service was deleted meanwhile.
+				if errors.Is(err, repository.ErrRecordNotFound) {
+					continue
+				}
+				return err
+			}
+			geocoded++
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/jobs"
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/server/handlers"
+)
+
+// job kinds
+const (
+	JOB_ACCOUNT_DELETIONS    = "account_deletions"
+	JOB_CERTIFICATION_EXPIRY = "certification_expiry"
+	JOB_VERIFICATION_CLEANUP = "verification_cleanup"
+	JOB_BOOKING_EXPIRY       = "booking_expiry"
+	JOB_COMPLETED_JOBS_PRUNE = "completed_jobs_prune"
+	JOB_SERVICE_GEOCODING    = "service_geocoding"
+)
+
+const (
+	// number of jobs run at once by an instance.
+	jobWorkers = 4
+	// number of bookings expired per run.
+	bookingExpiryBatchSize = 50
+	// completed jobs are kept this many days.
+	completedJobRetentionDays = 7
+)
+
+// newJobRunner returns the runner of the API's background jobs, with
+// their schedules.
+func newJobRunner(app *util.Application) *jobs.Runner {
+	runner := jobs.NewRunner(app.Repositories.Job, jobWorkers)
+
+	runner.Register(JOB_ACCOUNT_DELETIONS, 3, func(ctx context.Context, job models.Job) error {
+		return deleteDueAccounts(ctx, app)
+	})
+	runner.Schedule(JOB_ACCOUNT_DELETIONS, jobs.Every(time.Hour))
+
+	runner.Register(JOB_CERTIFICATION_EXPIRY, 3, func(ctx context.Context, job models.Job) error {
+		return expireCertifications(ctx, app)
+	})
+	runner.Schedule(JOB_CERTIFICATION_EXPIRY, jobs.Every(time.Hour))
+
+	runner.Register(JOB_VERIFICATION_CLEANUP, 3, func(ctx context.Context, job models.Job) error {
+		deleted, err := app.Repositories.User.DeleteExpiredVerificationCodes()
+		if err != nil {
+			return err
+		}
+		if deleted > 0 {
+			log.Printf("deleted %d expired verification codes\n", deleted)
+		}
+		return nil
+	})
+	runner.Schedule(JOB_VERIFICATION_CLEANUP, jobs.Every(time.Hour))
+
+	runner.Register(JOB_BOOKING_EXPIRY, 5, func(ctx context.Context, job models.Job) error {
+		return expireBookings(ctx, app)
+	})
+	runner.Schedule(JOB_BOOKING_EXPIRY, jobs.Every(5*time.Minute))
+
+	runner.Register(JOB_COMPLETED_JOBS_PRUNE, 3, func(ctx context.Context, job models.Job) error {
+		_, err := app.Repositories.Job.Prune(
+			time.Now().AddDate(0, 0, -completedJobRetentionDays),
+		)
+		return err
+	})
+	runner.Schedule(JOB_COMPLETED_JOBS_PRUNE, jobs.Daily(3, 0))
+
+	runner.Register(JOB_SERVICE_GEOCODING, 3, func(ctx context.Context, job models.Job) error {
+		return geocodeServices(ctx, app)
+	})
+	runner.Schedule(JOB_SERVICE_GEOCODING, jobs.Daily(2, 0))
+
+	return runner
+}
+
+// expireBookings cancels the bookings nobody accepted before their start
+// time, refunding their requesters.
+func expireBookings(ctx context.Context, app *util.Application) error {
+	bookings, err := app.Repositories.Booking.GetExpired(bookingExpiryBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, booking := range bookings {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := handlers.ExpireBooking(app, &booking); err != nil {
+			// the job is retried, and picks up the remaining bookings.
+			return err
+		}
+	}
+	return nil
+}
@@ -1,14 +1,15 @@
 package response
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/lokatalent/backend_go/internal/models"
 )
 
 type DistanceEstimation struct {
-	Distance string `json:"distance"`
-	Duration string `json:"duration"`
+	Distance   string  `json:"distance"`
+	DistanceKm float64 `json:"distance_km"`
 }
 
 type ServiceDistanceResponse struct {
@@ -17,41 +18,45 @@ type ServiceDistanceResponse struct {
 	ServiceType     string
 	ServiceDesc     string
 	ExperienceYears int
+	Rating          float64
+	ReviewCount     int64
 	DistanceEstimation
 }
 
 type BookingResponse struct {
-	ID            string    `json:"id"`
-	RequesterID   string    `json:"requester_id"`
-	ProviderID    string    `json:"provider_id"`
-	RequesterAddr string    `json:"requester_addr"`
-	ServiceType   string    `json:"service_type"`
-	BookingType   string    `json:"booking_type"`
-	ServiceDesc   string    `json:"service_desc"`
-	StartTime     string    `json:"start_time"`
-	EndTime       string    `json:"end_time"`
-	StartDate     string    `json:"start_date"`
-	EndDate       string    `json:"end_date"`
-	TotalPrice    float64   `json:"total_price"`
-	ActualPrice   float64   `json:"actual_price"`
-	Status        string    `json:"status"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID                string          `json:"id"`
+	RequesterID       string          `json:"requester_id"`
+	ProviderID        string          `json:"provider_id"`
+	RequesterAddr     string          `json:"requester_addr"`
+	RequesterLocation models.Location `json:"requester_location"`
+	ServiceType       string          `json:"service_type"`
+	BookingType       string          `json:"booking_type"`
+	ServiceDesc       string          `json:"service_desc"`
+	StartTime         string          `json:"start_time"`
+	EndTime           string          `json:"end_time"`
+	StartDate         string          `json:"start_date"`
+	EndDate           string          `json:"end_date"`
+	TotalPrice        models.Money    `json:"total_price"`
+	ActualPrice       models.Money    `json:"actual_price"`
+	Status            string          `json:"status"`
+	CreatedAt         time.Time       `json:"created_at"`
+	UpdatedAt         time.Time       `json:"updated_at"`
 }
 
 func BookingResponseFromModel(booking models.Booking) BookingResponse {
 	response := BookingResponse{
-		ID:            booking.ID,
-		RequesterID:   booking.RequesterID,
-		RequesterAddr: booking.RequesterAddr,
-		ServiceType:   booking.ServiceType,
-		BookingType:   booking.BookingType,
-		ServiceDesc:   booking.ServiceDesc,
-		TotalPrice:    booking.TotalPrice,
-		ActualPrice:   booking.ActualPrice,
-		Status:        booking.Status,
-		CreatedAt:     booking.CreatedAt,
-		UpdatedAt:     booking.UpdatedAt,
+		ID:                booking.ID,
+		RequesterID:       booking.RequesterID,
+		RequesterAddr:     booking.RequesterAddr,
+		RequesterLocation: booking.RequesterLocation,
+		ServiceType:       booking.ServiceType,
+		BookingType:       booking.BookingType,
+		ServiceDesc:       booking.ServiceDesc,
+		TotalPrice:        booking.TotalPrice,
+		ActualPrice:       booking.ActualPrice,
+		Status:            booking.Status,
+		CreatedAt:         booking.CreatedAt,
+		UpdatedAt:         booking.UpdatedAt,
 	}
 
 	if booking.ProviderID.Valid {
@@ -66,18 +71,53 @@ func BookingResponseFromModel(booking models.Booking) BookingResponse {
 	return response
 }
 
-func BookingServiceProviderResponse(service models.UserService, distance DistanceEstimation) ServiceDistanceResponse {
+func BookingServiceProviderResponse(match models.ServiceMatch, rating models.RatingSummary) ServiceDistanceResponse {
 	response := ServiceDistanceResponse{
-		ServiceID:       service.ID,
-		ProviderID:      service.UserID,
-		ServiceType:     service.ServiceType,
-		ServiceDesc:     service.ServiceDesc,
-		ExperienceYears: service.ExperienceYears,
+		ServiceID:       match.ID,
+		ProviderID:      match.UserID,
+		ServiceType:     match.ServiceType,
+		ServiceDesc:     match.ServiceDesc,
+		ExperienceYears: match.ExperienceYears,
+		Rating:          rating.Rating,
+		ReviewCount:     rating.ReviewCount,
 		DistanceEstimation: DistanceEstimation{
-			Distance: distance.Distance,
-			Duration: distance.Duration,
+			Distance:   fmt.Sprintf("%.1f km", match.DistanceKm),
+			DistanceKm: match.DistanceKm,
 		},
 	}
 
 	return response
 }
+
+type BookingEventResponse struct {
+	ID         string    `json:"id"`
+	BookingID  string    `json:"booking_id"`
+	Action     string    `json:"action"`
+	Actor      string    `json:"actor"`
+	ActorID    string    `json:"actor_id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	ProviderID string    `json:"provider_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func BookingEventResponseFromModel(event models.BookingEvent) BookingEventResponse {
+	response := BookingEventResponse{
+		ID:         event.ID,
+		BookingID:  event.BookingID,
+		Action:     event.Action,
+		Actor:      event.Actor,
+		FromStatus: event.FromStatus,
+		ToStatus:   event.ToStatus,
+		CreatedAt:  event.CreatedAt,
+	}
+
+	if event.ActorID.Valid {
+		response.ActorID = event.ActorID.String
+	}
+	if event.ProviderID.Valid {
+		response.ProviderID = event.ProviderID.String
+	}
+
+	return response
+}
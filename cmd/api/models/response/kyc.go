@@ -0,0 +1,11 @@
+package response
+
+import "github.com/lokatalent/backend_go/internal/models"
+
+// KYCSubmissionResponse is a submission as seen by its reviewers, with
+// temporary links to its files.
+type KYCSubmissionResponse struct {
+	models.KYCSubmission
+	DocumentURL string `json:"document_url"`
+	SelfieURL   string `json:"selfie_url"`
+}
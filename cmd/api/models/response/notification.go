@@ -32,3 +32,10 @@ func NotificationResponseFromModel(notification models.Notification) Notificatio
 
 	return response
 }
+
+// NotificationSettingsResponse is a user's channels for every type of
+// notification, and their quiet hours, if any.
+type NotificationSettingsResponse struct {
+	Preferences []models.NotificationPreference `json:"preferences"`
+	QuietHours  *models.QuietHours              `json:"quiet_hours"`
+}
@@ -5,3 +5,30 @@ type TokensResponse struct {
 	RefreshToken string `json:"refresh_token,omitempty"`
 	ExpiresAt    int64  `json:"expires_at,omitempty"`
 }
+
+// TwoFactorChallengeResponse is returned by sign-in when a second factor
+// is required. EnrollmentRequired is set for users who must set up
+// two-factor authentication before signing in.
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired  bool   `json:"two_factor_required"`
+	EnrollmentRequired bool   `json:"enrollment_required,omitempty"`
+	ChallengeToken     string `json:"challenge_token"`
+	ExpiresAt          int64  `json:"expires_at"`
+}
+
+type TwoFactorEnrollmentResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string        `json:"recovery_codes"`
+	Tokens        *TokensResponse `json:"tokens,omitempty"`
+}
+
+// LinkIdentityResponse is returned when a user starts linking their
+// account with a provider. The user is sent to LinkURL to sign in there.
+type LinkIdentityResponse struct {
+	LinkURL   string `json:"link_url"`
+	ExpiresAt int64  `json:"expires_at"`
+}
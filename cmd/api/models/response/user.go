@@ -23,6 +23,9 @@ type UserResponse struct {
 	EmailVerified bool      `json:"email_verified"`
 	PhoneVerified bool      `json:"phone_verified"`
 
+	// set for service providers only.
+	*models.RatingSummary
+
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -39,6 +42,9 @@ type PublicUserResponse struct {
 	ServiceRole string    `json:"service_role"`
 	IsVerified  bool      `json:"is_verified"`
 
+	// set for service providers only.
+	*models.RatingSummary
+
 	CreatedAt time.Time `json:"created_at"`
 }
 
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/gateway"
+	"github.com/lokatalent/backend_go/internal/models"
+)
+
+const (
+	// interval between polls for outbox messages due for delivery.
+	outboxRelayInterval = 5 * time.Second
+	outboxBatchSize     = 20
+	// time a claimed message is hidden from other instances while being
+	// delivered, after which it is retried.
+	outboxLease = 5 * time.Minute
+	// messages failing this many times are dead-lettered.
+	outboxMaxAttempts = 10
+	outboxMinBackoff  = 30 * time.Second
+	outboxMaxBackoff  = 6 * time.Hour
+)
+
+// runOutboxRelay delivers the messages of the outbox, retrying failed
+// deliveries with exponential backoff until they are dead-lettered. It
+// returns once ctx is done.
+func runOutboxRelay(ctx context.Context, app *util.Application) {
+	ticker := time.NewTicker(outboxRelayInterval)
+	defer ticker.Stop()
+
+	for {
+		relayOutbox(app)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func relayOutbox(app *util.Application) {
+	for {
+		messages, err := app.Repositories.Outbox.Claim(outboxBatchSize, outboxLease)
+		if err != nil {
+			log.Printf("outbox relay: %v\n", err)
+			return
+		}
+
+		for _, message := range messages {
+			err := deliverOutboxMessage(app, message)
+			if err == nil {
+				err = app.Repositories.Outbox.MarkDelivered(message.ID)
+				if err != nil {
+					log.Printf("outbox relay %s: %v\n", message.ID, err)
+				}
+				continue
+			}
+
+			dead := message.Attempts >= outboxMaxAttempts
+			log.Printf(
+				"outbox relay %s: %s attempt %d: %v\n",
+				message.ID, message.Kind, message.Attempts, err,
+			)
+			err = app.Repositories.Outbox.MarkFailed(
+				message.ID,
+				err.Error(),
+				time.Now().Add(outboxBackoff(message.Attempts)),
+				dead,
+			)
+			if err != nil {
+				log.Printf("outbox relay %s: %v\n", message.ID, err)
+			}
+		}
+
+		// keep draining while full batches are due.
+		if len(messages) < outboxBatchSize {
+			return
+		}
+	}
+}
+
+func deliverOutboxMessage(app *util.Application, message models.OutboxMessage) error {
+	switch message.Kind {
+	case models.OUTBOX_KIND_NOTIFICATION:
+		notification := models.Notification{}
+		if err := json.Unmarshal(message.Payload, &notification); err != nil {
+			return err
+		}
+		return app.Notifier.Dispatch(&notification)
+	case models.OUTBOX_KIND_EMAIL, models.OUTBOX_KIND_SMS, models.OUTBOX_KIND_PUSH:
+		return app.Notifier.Deliver(message)
+	case models.OUTBOX_KIND_TRANSFER:
+		return deliverTransfer(app, message)
+	default:
+		return fmt.Errorf("unknown outbox message kind %q", message.Kind)
+	}
+}
+
+// deliverTransfer starts a payout to a service provider. A retried
+// transfer is only started again if the gateway has no record of it, so
+// that a provider is never paid twice. Failing to check is retried.
+func deliverTransfer(app *util.Application, message models.OutboxMessage) error {
+	transfer := models.OutboxTransfer{}
+	if err := json.Unmarshal(message.Payload, &transfer); err != nil {
+		return err
+	}
+
+	if message.Attempts > 1 {
+		_, err := app.PaymentGateway.VerifyTransfer(transfer.Reference)
+		if err == nil {
+			// the outcome of the transfer is settled by webhook.
+			return nil
+		}
+		if !errors.Is(err, gateway.ErrNotFound) {
+			return err
+		}
+	}
+
+	_, err := app.PaymentGateway.Transfer(gateway.Transfer{
+		Reference:     transfer.Reference,
+		RecipientCode: transfer.RecipientCode,
+		Reason:        transfer.Reason,
+		Amount:        transfer.Amount,
+	})
+	return err
+}
+
+// outboxBackoff returns the delay before retrying a message after the
+// given number of attempts, doubling from outboxMinBackoff.
+func outboxBackoff(attempts int) time.Duration {
+	backoff := outboxMinBackoff
+	for i := 1; i < attempts && backoff < outboxMaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, outboxMaxBackoff)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/gateway"
+	"github.com/lokatalent/backend_go/internal/models"
+)
+
+func TestOutboxBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 0, want: 30 * time.Second},
+		{attempts: 1, want: 30 * time.Second},
+		{attempts: 2, want: time.Minute},
+		{attempts: 3, want: 2 * time.Minute},
+		{attempts: 6, want: 16 * time.Minute},
+		{attempts: 10, want: 256 * time.Minute},
+		{attempts: 11, want: outboxMaxBackoff},
+		{attempts: 1000, want: outboxMaxBackoff},
+	}
+
+	for _, tt := range tests {
+		if got := outboxBackoff(tt.attempts); got != tt.want {
+			t.Errorf("outboxBackoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+// testGateway counts the transfers started, and fails to verify them
+// with verifyErr when set, as when Paystack is unreachable.
+type testGateway struct {
+	*gateway.Fake
+	verifyErr error
+	transfers int
+}
+
+func (g *testGateway) Transfer(transfer gateway.Transfer) (string, error) {
+	g.transfers++
+	return g.Fake.Transfer(transfer)
+}
+
+func (g *testGateway) VerifyTransfer(reference string) (string, error) {
+	if g.verifyErr != nil {
+		return "", g.verifyErr
+	}
+	return g.Fake.VerifyTransfer(reference)
+}
+
+func TestDeliverTransfer(t *testing.T) {
+	const reference = "transfer_ref"
+
+	tests := []struct {
+		name      string
+		attempts  int
+		started   bool
+		verifyErr error
+		fails     bool
+		transfers int
+	}{
+		{name: "first attempt", attempts: 1, transfers: 1},
+		{name: "retry of unknown transfer", attempts: 2, transfers: 1},
+		{name: "retry of started transfer", attempts: 2, started: true},
+		{name: "retry while unreachable", attempts: 2, verifyErr: errors.New("connection refused"), fails: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := gateway.NewFake("secret", gateway.STATUS_SUCCESS)
+			if tt.started {
+				if _, err := fake.Transfer(gateway.Transfer{Reference: reference}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			paymentGateway := &testGateway{Fake: fake, verifyErr: tt.verifyErr}
+			app := &util.Application{PaymentGateway: paymentGateway}
+
+			payload, err := json.Marshal(models.OutboxTransfer{
+				Reference:     reference,
+				RecipientCode: "RCP_test",
+				Amount:        models.NGN(500000),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = deliverTransfer(app, models.OutboxMessage{
+				Kind:     models.OUTBOX_KIND_TRANSFER,
+				Payload:  payload,
+				Attempts: tt.attempts,
+			})
+			if (err != nil) != tt.fails {
+				t.Fatalf("deliverTransfer error = %v, want failure %v", err, tt.fails)
+			}
+			if paymentGateway.transfers != tt.transfers {
+				t.Errorf("%d transfers started, want %d", paymentGateway.transfers, tt.transfers)
+			}
+		})
+	}
+}
@@ -1,20 +1,34 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
-	// "os"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/lokatalent/backend_go/cmd/api/util"
 	"github.com/lokatalent/backend_go/internal/database/postgres"
+	"github.com/lokatalent/backend_go/internal/gateway"
+	"github.com/lokatalent/backend_go/internal/geo"
 	"github.com/lokatalent/backend_go/internal/mailer"
+	"github.com/lokatalent/backend_go/internal/notify"
+	"github.com/lokatalent/backend_go/internal/push"
+	"github.com/lokatalent/backend_go/internal/realtime"
 	"github.com/lokatalent/backend_go/internal/repository"
 	"github.com/lokatalent/backend_go/internal/s3"
 	"github.com/lokatalent/backend_go/internal/server/routes"
 	"github.com/lokatalent/backend_go/internal/sms"
 )
 
+// time allowed for requests in flight to finish when the server stops.
+const shutdownTimeout = 10 * time.Second
+
 func serveApp(config *util.Config, db *sql.DB) error {
 	repos := &repository.Repositories{
 		User:           postgres.NewUserImplementation(db),
@@ -24,6 +38,17 @@ func serveApp(config *util.Config, db *sql.DB) error {
 		Booking:        postgres.NewBookingImplementation(db),
 		Notification:   postgres.NewNotificationImplementation(db),
 		Payment:        postgres.NewPaymentImplementation(db),
+		Ledger:         postgres.NewLedgerImplementation(db),
+		Review:         postgres.NewReviewImplementation(db),
+		Session:        postgres.NewSessionImplementation(db),
+		Throttle:       postgres.NewThrottleImplementation(db),
+		TwoFactor:      postgres.NewTwoFactorImplementation(db),
+		Role:           postgres.NewRoleImplementation(db),
+		Audit:          postgres.NewAuditImplementation(db),
+		Identity:       postgres.NewIdentityImplementation(db),
+		KYC:            postgres.NewKYCImplementation(db),
+		Outbox:         postgres.NewOutboxImplementation(db),
+		Job:            postgres.NewJobImplementation(db),
 	}
 
 	app := util.Application{
@@ -43,10 +68,54 @@ func serveApp(config *util.Config, db *sql.DB) error {
 				Sender:     config.Twilio.Sender,
 			},
 		),
+		Geocoder: geo.NewGoogle(config.Google.MapSecret),
+		Realtime: realtime.NewHub(),
+	}
+
+	var pushSender *push.FCM
+	if config.Firebase.Credentials != nil {
+		pushSender = push.NewFCM(*config.Firebase.Credentials)
+	} else {
+		log.Println("push notifications disabled")
+	}
+	app.Notifier = notify.New(repos, app.Mailer, app.SMSSender, pushSender)
+
+	switch config.PaymentGateway.Name {
+	case gateway.FAKE:
+		log.Println("using fake payment gateway")
+		app.PaymentGateway = gateway.NewFake(
+			config.PaymentGateway.FakeSecret,
+			config.PaymentGateway.FakeStatus,
+		)
+	default:
+		app.PaymentGateway = gateway.NewPaystack(config.Paystack.APIKey)
 	}
 
 	engine := routes.Engine(&app)
 
+	// background work runs until the server is interrupted.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	runner := newJobRunner(&app)
+	runner.Start()
+	defer runner.Stop()
+	go runOutboxRelay(ctx, &app)
+	go func() {
+		err := postgres.ListenNotifications(ctx, config.DB.DSN, app.Realtime)
+		if err != nil {
+			log.Printf("notification listener: %v\n", err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := engine.Shutdown(shutdownCtx); err != nil {
+			log.Printf("server shutdown: %v\n", err)
+		}
+	}()
+
 	/*
 		switch app.Config.Env {
 		case util.ENVIRONMENT_PRODUCTION:
@@ -63,10 +132,12 @@ func serveApp(config *util.Config, db *sql.DB) error {
 			}
 		}
 	*/
-	if err := engine.Start(fmt.Sprintf(":%d", app.Config.Port)); err != nil {
+	err := engine.Start(fmt.Sprintf(":%d", app.Config.Port))
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		return err
 	}
 
+	runner.Stop()
 	log.Println("server stopped")
 
 	return nil
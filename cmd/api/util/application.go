@@ -1,7 +1,11 @@
 package util
 
 import (
+	"github.com/lokatalent/backend_go/internal/gateway"
+	"github.com/lokatalent/backend_go/internal/geo"
 	"github.com/lokatalent/backend_go/internal/mailer"
+	"github.com/lokatalent/backend_go/internal/notify"
+	"github.com/lokatalent/backend_go/internal/realtime"
 	"github.com/lokatalent/backend_go/internal/repository"
 	"github.com/lokatalent/backend_go/internal/sms"
 )
@@ -11,4 +15,12 @@ type Application struct {
 	Repositories *repository.Repositories
 	Mailer       *mailer.Mailer
 	SMSSender    *sms.SMSSender
+	// Notifier sends notifications on the channels users chose.
+	Notifier *notify.Dispatcher
+
+	PaymentGateway gateway.PaymentGateway
+	Geocoder       geo.Geocoder
+
+	// Realtime delivers events to the users connected to this instance.
+	Realtime *realtime.Hub
 }
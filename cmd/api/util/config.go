@@ -1,17 +1,31 @@
 package util
 
 import (
+	"crypto/ecdsa"
 	// "errors"
 	"fmt"
 	"os"
 	"strconv"
-	// "strings"
+	"strings"
+
+	"github.com/lokatalent/backend_go/internal/gateway"
+	"github.com/lokatalent/backend_go/internal/keyset"
+	"github.com/lokatalent/backend_go/internal/push"
+	"github.com/lokatalent/backend_go/internal/server/egothic/apple"
 )
 
 type PaystackSecret struct {
 	APIKey string
 }
 
+type PaymentGatewayConfig struct {
+	Name string
+
+	// fake gateway only
+	FakeStatus string
+	FakeSecret string
+}
+
 type SendGridSecret struct {
 	APIKey string
 	Sender string
@@ -30,10 +44,32 @@ type GoogleSecret struct {
 	MapSecret    string
 }
 
+// Facebook sign-in is disabled without a client ID.
+type FacebookSecret struct {
+	ClientID     string
+	ClientSecret string
+}
+
+// Sign in with Apple is disabled without a client (services) ID.
+type AppleSecret struct {
+	ClientID   string
+	TeamID     string
+	KeyID      string
+	PrivateKey *ecdsa.PrivateKey
+}
+
+// Push notifications are disabled without Firebase credentials.
+type FirebaseSecret struct {
+	Credentials *push.Credentials
+}
+
 // JSON Web Token
 type JWTSecret struct {
 	Access  string
 	Refresh string
+
+	// Keys signs and verifies access tokens.
+	Keys *keyset.KeySet
 }
 
 type TwilioSecret struct {
@@ -51,7 +87,14 @@ type Config struct {
 	Port   int
 	Origin string
 
+	// radius, in kilometres, around a booking to match providers in.
+	MatchRadiusKm float64
+
 	JWT JWTSecret
+	// TokenHashKey keys the hashes under which tokens such as refresh
+	// tokens are stored. Changing it invalidates all of them, so it is
+	// kept apart from the rotatable JWT secrets.
+	TokenHashKey string
 
 	DB struct {
 		DSN string
@@ -59,9 +102,14 @@ type Config struct {
 
 	AWS      AWSSecret
 	Google   GoogleSecret
+	Facebook FacebookSecret
+	Apple    AppleSecret
+	Firebase FirebaseSecret
 	SendGrid SendGridSecret
 	Twilio   TwilioSecret
 	Paystack PaystackSecret
+
+	PaymentGateway PaymentGatewayConfig
 }
 
 // Load reads in all required environment variable to start the
@@ -88,14 +136,34 @@ func (c *Config) Load() error {
 		return err
 	}
 
+	if c.MatchRadiusKm, err = loadMatchRadius(); err != nil {
+		return err
+	}
+
 	if err := loadJWTSecrets(&c.JWT); err != nil {
 		return err
 	}
 
+	if c.TokenHashKey, err = loadTokenHashKey(); err != nil {
+		return err
+	}
+
 	if err := loadGoogleSecrets(&c.Google); err != nil {
 		return err
 	}
 
+	if err := loadFacebookSecrets(&c.Facebook); err != nil {
+		return err
+	}
+
+	if err := loadAppleSecrets(&c.Apple); err != nil {
+		return err
+	}
+
+	if err := loadFirebaseSecrets(&c.Firebase); err != nil {
+		return err
+	}
+
 	if c.DB.DSN, err = loadDB(); err != nil {
 		return err
 	}
@@ -112,10 +180,16 @@ func (c *Config) Load() error {
 		return err
 	}
 
-	if err := loadPaystackSecrets(&c.Paystack); err != nil {
+	if err := loadPaymentGateway(c.Env, &c.PaymentGateway); err != nil {
 		return err
 	}
 
+	if c.PaymentGateway.Name == gateway.PAYSTACK {
+		if err := loadPaystackSecrets(&c.Paystack); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -180,6 +254,34 @@ func loadAppPort() (int, error) {
 	return port, nil
 }
 
+// loadMatchRadius loads the provider matching radius, which defaults to
+// DefaultMatchRadiusKm.
+func loadMatchRadius() (float64, error) {
+	radiusEnv, ok := os.LookupEnv("MATCH_RADIUS_KM")
+	if !ok || radiusEnv == "" {
+		return DefaultMatchRadiusKm, nil
+	}
+
+	radius, err := strconv.ParseFloat(radiusEnv, 64)
+	if err != nil || radius <= 0 {
+		return 0, invalidEnvVar("MATCH_RADIUS_KM", "positive number", radiusEnv)
+	}
+
+	return radius, nil
+}
+
+// loadTokenHashKey loads the key for hashing stored tokens.
+func loadTokenHashKey() (string, error) {
+	key, ok := os.LookupEnv("TOKEN_HASH_KEY")
+	if !ok {
+		return "", missingEnvVar("TOKEN_HASH_KEY")
+	}
+	if len(key) != 64 {
+		return "", invalidEnvVar("TOKEN_HASH_KEY", "string of length 64", key)
+	}
+	return key, nil
+}
+
 // loadDB loads database connection parameters.
 func loadDB() (string, error) {
 	dbName, ok := os.LookupEnv("DB_NAME")
@@ -238,9 +340,57 @@ func loadJWTSecrets(jwt *JWTSecret) error {
 	jwt.Access = jwtAccess
 	jwt.Refresh = jwtRefresh
 
+	return loadJWTKeys(jwt)
+}
+
+// loadJWTKeys loads the keys for signing access tokens. Without a signing
+// key, tokens are signed with the JWT_ACCESS secret. Previous signing keys
+// are kept for verification while rotating keys.
+func loadJWTKeys(jwt *JWTSecret) error {
+	var signing *keyset.Key
+	if path, ok := os.LookupEnv("JWT_SIGNING_KEY_FILE"); ok && path != "" {
+		key, err := loadJWTKey("JWT_SIGNING_KEY_FILE", path)
+		if err != nil {
+			return err
+		}
+		if key.Private == nil {
+			return invalidEnvVar("JWT_SIGNING_KEY_FILE", "private key", path)
+		}
+		signing = key
+	}
+
+	previous := []*keyset.Key{}
+	if paths, ok := os.LookupEnv("JWT_VERIFICATION_KEY_FILES"); ok && paths != "" {
+		for _, path := range strings.Split(paths, ",") {
+			key, err := loadJWTKey("JWT_VERIFICATION_KEY_FILES", strings.TrimSpace(path))
+			if err != nil {
+				return err
+			}
+			previous = append(previous, key)
+		}
+	}
+
+	keys, err := keyset.New(signing, previous, jwt.Access)
+	if err != nil {
+		return err
+	}
+	jwt.Keys = keys
+
 	return nil
 }
 
+func loadJWTKey(envVar, path string) (*keyset.Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", envVar, err)
+	}
+	key, err := keyset.ParsePEM(data)
+	if err != nil {
+		return nil, invalidEnvVar(envVar, "PEM encoded RSA or Ed25519 key", path)
+	}
+	return key, nil
+}
+
 // loadGoogleSecrets loads secrets for Google API
 func loadGoogleSecrets(google *GoogleSecret) error {
 	googleClientID, ok := os.LookupEnv("GOOGLE_CLIENT_ID")
@@ -277,6 +427,83 @@ func loadGoogleSecrets(google *GoogleSecret) error {
 	return nil
 }
 
+// loadFacebookSecrets loads secrets for Facebook sign-in, if enabled.
+func loadFacebookSecrets(facebook *FacebookSecret) error {
+	clientID := os.Getenv("FACEBOOK_CLIENT_ID")
+	if clientID == "" {
+		return nil
+	}
+	clientSecret, ok := os.LookupEnv("FACEBOOK_CLIENT_SECRET")
+	if !ok {
+		return missingEnvVar("FACEBOOK_CLIENT_SECRET")
+	}
+	if len(clientSecret) < 1 {
+		return invalidEnvVar(
+			"FACEBOOK_CLIENT_SECRET", "string of length > 1", clientSecret)
+	}
+
+	facebook.ClientID = clientID
+	facebook.ClientSecret = clientSecret
+
+	return nil
+}
+
+// loadAppleSecrets loads secrets for Sign in with Apple, if enabled.
+func loadAppleSecrets(appleSecret *AppleSecret) error {
+	clientID := os.Getenv("APPLE_CLIENT_ID")
+	if clientID == "" {
+		return nil
+	}
+	values := map[string]string{}
+	for _, envVar := range []string{"APPLE_TEAM_ID", "APPLE_KEY_ID", "APPLE_PRIVATE_KEY_FILE"} {
+		value, ok := os.LookupEnv(envVar)
+		if !ok {
+			return missingEnvVar(envVar)
+		}
+		if len(value) < 1 {
+			return invalidEnvVar(envVar, "string of length > 1", value)
+		}
+		values[envVar] = value
+	}
+
+	path := values["APPLE_PRIVATE_KEY_FILE"]
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading APPLE_PRIVATE_KEY_FILE: %w", err)
+	}
+	privateKey, err := apple.ParsePrivateKey(data)
+	if err != nil {
+		return invalidEnvVar("APPLE_PRIVATE_KEY_FILE", "PEM encoded ECDSA key (.p8)", path)
+	}
+
+	appleSecret.ClientID = clientID
+	appleSecret.TeamID = values["APPLE_TEAM_ID"]
+	appleSecret.KeyID = values["APPLE_KEY_ID"]
+	appleSecret.PrivateKey = privateKey
+
+	return nil
+}
+
+// loadFirebaseSecrets loads the service account key push notifications
+// are sent with, if one is configured.
+func loadFirebaseSecrets(firebase *FirebaseSecret) error {
+	path := os.Getenv("FIREBASE_CREDENTIALS_FILE")
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading FIREBASE_CREDENTIALS_FILE: %w", err)
+	}
+	credentials, err := push.ParseCredentials(data)
+	if err != nil {
+		return invalidEnvVar("FIREBASE_CREDENTIALS_FILE", "service account key (.json)", path)
+	}
+
+	firebase.Credentials = &credentials
+	return nil
+}
+
 // loadAWSSecrets loads secrets for AWS API.
 func loadAWSSecrets(aws *AWSSecret) error {
 	awsRegion, ok := os.LookupEnv("AWS_REGION")
@@ -418,6 +645,48 @@ func loadPaystackSecrets(paystack *PaystackSecret) error {
 	return nil
 }
 
+// loadPaymentGateway loads the payment gateway to use, defaulting to
+// Paystack. The fake gateway is not allowed in production.
+func loadPaymentGateway(env string, paymentGateway *PaymentGatewayConfig) error {
+	name, ok := os.LookupEnv("PAYMENT_GATEWAY")
+	if !ok || name == "" {
+		name = gateway.PAYSTACK
+	}
+
+	switch name {
+	case gateway.PAYSTACK:
+	case gateway.FAKE:
+		if env == ENVIRONMENT_PRODUCTION {
+			return invalidEnvVar("PAYMENT_GATEWAY", gateway.PAYSTACK, name)
+		}
+
+		status := os.Getenv("FAKE_GATEWAY_STATUS")
+		switch status {
+		case "":
+			status = gateway.STATUS_SUCCESS
+		case gateway.STATUS_SUCCESS, gateway.STATUS_PENDING, gateway.STATUS_FAILED:
+		default:
+			return invalidEnvVar(
+				"FAKE_GATEWAY_STATUS", "success|pending|failed", status,
+			)
+		}
+
+		secret := os.Getenv("FAKE_GATEWAY_SECRET")
+		if secret == "" {
+			secret = "fake_gateway_secret"
+		}
+
+		paymentGateway.FakeStatus = status
+		paymentGateway.FakeSecret = secret
+	default:
+		return invalidEnvVar("PAYMENT_GATEWAY", "paystack|fake", name)
+	}
+
+	paymentGateway.Name = name
+
+	return nil
+}
+
 // missingEnvVar reports missing environment variable.
 func missingEnvVar(envVar string) error {
 	return fmt.Errorf("missing environment var: %s\n", envVar)
@@ -13,21 +13,48 @@ const (
 const DB_CONN_FMT = "postgres://%s:%s@%s:%s/%s"
 const DB_CONN_FMT_TEST = "postgres://%s:%s@%s:%s/%s?sslmode=disable"
 
-const ContextKeyUser = "user"
+const (
+	ContextKeyUser      = "user"
+	ContextKeyPrincipal = "principal"
+)
+
+// default radius, in kilometres, around a booking to match providers in.
+const DefaultMatchRadiusKm = 25.0
 
 // tokens configuration
 const (
-	AccessTokenDuration  = 15 * time.Minute
-	RefreshTokenDuration = 24 * time.Hour
+	AccessTokenDuration    = 15 * time.Minute
+	RefreshTokenDuration   = 24 * time.Hour
+	ChallengeTokenDuration = 5 * time.Minute
+	LinkTokenDuration      = 5 * time.Minute
 )
 
+// audience of the token issued between the password and the two-factor
+// sign-in steps.
+const ChallengeTokenAudience = "two_factor"
+
+// audience of the token carrying a signed-in user through a third-party
+// provider's sign-in to link their account there.
+const LinkTokenAudience = "link_identity"
+
+// time a user has to cancel the deletion of their account before it is
+// anonymized.
+const AccountDeletionGracePeriod = 30 * 24 * time.Hour
+
+// issuer shown by authenticator apps
+const TOTPIssuer = "LOKATALENT"
+
 // Content-Type configurations
 const (
 	ContentTypeJPEG = "image/jpeg"
 	ContentTypePNG  = "image/png"
+	ContentTypePDF  = "application/pdf"
 
 	// Errors
 	ErrInvalidContentType = "Unexpected content type."
 )
 
 const PhoneNumPattern = `^\+234[789]\d{9}$`
+
+// role names are lowercase identifiers, e.g. finance or support_lead.
+const RoleNamePattern = `^[a-z][a-z0-9_]{1,31}$`
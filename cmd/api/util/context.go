@@ -1,10 +1,13 @@
 package util
 
 import (
+	"time"
+
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 
 	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/rbac"
 )
 
 type ContextKey string
@@ -21,3 +24,57 @@ func ContextGetUser(ctx echo.Context) models.User {
 		IsVerified: claims.Verified,
 	}
 }
+
+// ContextGetSessionID returns the session the request's access token was
+// issued for.
+func ContextGetSessionID(ctx echo.Context) string {
+	claims, ok := ctx.Get(ContextKeyUser).(*jwt.Token).Claims.(*CustomAccessJWTClaims)
+	if !ok {
+		return ""
+	}
+	return claims.SessionID
+}
+
+// ContextGetTokenExpiry returns when the request's access token expires.
+func ContextGetTokenExpiry(ctx echo.Context) time.Time {
+	claims, ok := ctx.Get(ContextKeyUser).(*jwt.Token).Claims.(*CustomAccessJWTClaims)
+	if !ok || claims.ExpiresAt == nil {
+		return time.Time{}
+	}
+	return claims.ExpiresAt.Time
+}
+
+// ContextGetPrincipal returns the current user, along with the permissions
+// their role grants. It is loaded once per request.
+func ContextGetPrincipal(ctx echo.Context, app *Application) (*rbac.Principal, error) {
+	if principal, ok := ctx.Get(ContextKeyPrincipal).(*rbac.Principal); ok {
+		return principal, nil
+	}
+
+	user, err := app.Repositories.User.GetByID(ContextGetUser(ctx).ID)
+	if err != nil {
+		return nil, err
+	}
+	principal, err := LoadPrincipal(app, user)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.Set(ContextKeyPrincipal, principal)
+	return principal, nil
+}
+
+// LoadPrincipal returns the user along with the permissions their role
+// grants.
+func LoadPrincipal(app *Application, user models.User) (*rbac.Principal, error) {
+	role, err := app.Repositories.Role.Get(user.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rbac.Principal{
+		User:              user,
+		Permissions:       role.Permissions,
+		RequiresTwoFactor: role.RequiresTwoFactor,
+	}, nil
+}
@@ -1,9 +1,10 @@
 package util
 
 import (
+	"crypto/rand"
 	"errors"
 	"fmt"
-	"math/rand"
+	"math/big"
 	"net/mail"
 	"net/textproto"
 	"regexp"
@@ -14,6 +15,7 @@ import (
 	"github.com/labstack/echo/v4"
 
 	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/rbac"
 )
 
 // ValidateContentType verifies that request header content-type is supported
@@ -53,13 +55,24 @@ func IsValidPhoneNumber(num string) bool {
 	return matched
 }
 
-// IsAdmin checks if a user has admin priviledge
-func IsAdmin(userRole string) bool {
-	switch userRole {
-	case models.USER_ADMIN, models.USER_ADMIN_SUPER:
+// IsValidRoleName validates the structure of a role name
+func IsValidRoleName(name string) bool {
+	matched, err := regexp.MatchString(RoleNamePattern, name)
+	if err != nil {
+		return false
+	}
+
+	return matched
+}
+
+// CanEnrollTwoFactor checks if a user may set up two-factor
+// authentication: staff, and providers who receive payouts.
+func CanEnrollTwoFactor(principal *rbac.Principal) bool {
+	switch principal.ServiceRole {
+	case models.SERVICE_PROVIDER, models.SERVICE_BOTH:
 		return true
 	default:
-		return false
+		return principal.RequiresTwoFactor || principal.IsStaff()
 	}
 }
 
@@ -94,8 +107,10 @@ func IsValidBookingType(bookingType string) bool {
 
 func IsValidBookingStatus(status string) bool {
 	switch status {
-	case models.BOOKING_OPEN, models.BOOKING_CANCELED, models.BOOKING_IN_PROGRESS,
-		models.BOOKING_COMPLETED, models.BOOKING_ACCEPT, models.BOOKING_REJECT:
+	case models.BOOKING_OPEN, models.BOOKING_PROVIDER_SELECTED,
+		models.BOOKING_ACCEPTED, models.BOOKING_IN_PROGRESS,
+		models.BOOKING_COMPLETED, models.BOOKING_CANCELED,
+		models.BOOKING_DISPUTED:
 		return true
 	default:
 		return false
@@ -123,9 +138,14 @@ func ParseBool(value string) (bool, error) {
 	}
 }
 
-// RandomInt generates a radom integer between min and max
-func RandomInt(min, max int64) int64 {
-	return min + rand.Int63n(max-min+1)
+// RandomInt generates a random integer between min and max, from a
+// cryptographically secure source as it is used for one-time codes.
+func RandomInt(min, max int64) (int64, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(max-min+1))
+	if err != nil {
+		return 0, err
+	}
+	return min + n.Int64(), nil
 }
 
 func ValidVerificationType(value string) bool {
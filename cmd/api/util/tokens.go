@@ -1,7 +1,13 @@
 package util
 
 import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"slices"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -10,78 +16,154 @@ import (
 )
 
 type CustomAccessJWTClaims struct {
-	Email    string `json:"email,omitempty"`
-	Verified bool   `json:"verified,omitempty"`
+	Email     string `json:"email,omitempty"`
+	Verified  bool   `json:"verified,omitempty"`
+	SessionID string `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
-type CustomRefreshJWTClaims struct {
-	Email string `json:"email,omitempty"`
+// CustomChallengeJWTClaims identify a user who has passed the password
+// step of sign-in, but not yet the two-factor one.
+type CustomChallengeJWTClaims struct {
+	Device string `json:"device,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// GenerateTokens generates the signed access and refresh tokens, with
-// expiration time.
-func GenerateTokens(app *Application, user *models.User) (string, string, int64, error) {
+// GenerateAccessToken generates a signed access token for the user's
+// session, with its expiration time.
+func GenerateAccessToken(app *Application, user *models.User, sessionID string) (string, int64, error) {
 	accessTokenExpiration := time.Now().Add(AccessTokenDuration)
 	accessClaims := CustomAccessJWTClaims{
-		Email:    user.Email,
-		Verified: user.IsVerified,
+		Email:     user.Email,
+		Verified:  user.IsVerified,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ID:        user.ID,
 			ExpiresAt: jwt.NewNumericDate(accessTokenExpiration),
 		},
 	}
 
-	// generate refresh token with lifetime of 1 day
-	refreshClaims := CustomAccessJWTClaims{
-		Email: user.Email,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ID:        user.ID,
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(RefreshTokenDuration)),
-		},
+	accessToken, err := app.Config.JWT.Keys.Sign(accessClaims)
+	if err != nil {
+		return "", 0, err
 	}
 
-	accessToken, err := jwt.NewWithClaims(
-		jwt.SigningMethodHS256,
-		accessClaims,
-	).SignedString(
-		[]byte(app.Config.JWT.Access))
+	return accessToken, accessTokenExpiration.Unix(), nil
+}
 
-	if err != nil {
-		return "", "", 0, err
+// GenerateToken generates an opaque, URL-safe token, such as a refresh
+// token, and the hash under which it is stored.
+func GenerateToken(secret string) (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+
+	return token, HashToken(secret, token), nil
+}
+
+// HashToken returns the keyed hash under which a single-use token is
+// stored.
+func HashToken(secret, token string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(token))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// GenerateChallengeToken generates a short-lived token for completing a
+// two-factor sign-in. It is signed with the refresh secret so it can
+// never pass as an access token.
+func GenerateChallengeToken(app *Application, user *models.User, device string) (string, int64, error) {
+	expiration := time.Now().Add(ChallengeTokenDuration)
+	claims := CustomChallengeJWTClaims{
+		Device: device,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   user.ID,
+			Audience:  jwt.ClaimStrings{ChallengeTokenAudience},
+			ExpiresAt: jwt.NewNumericDate(expiration),
+		},
 	}
 
-	refreshToken, err := jwt.NewWithClaims(
+	token, err := jwt.NewWithClaims(
 		jwt.SigningMethodHS256,
-		refreshClaims,
+		claims,
 	).SignedString(
 		[]byte(app.Config.JWT.Refresh))
 
 	if err != nil {
-		return "", "", 0, err
+		return "", 0, err
 	}
 
-	return accessToken, refreshToken, accessTokenExpiration.Unix(), nil
+	return token, expiration.Unix(), nil
 }
 
-// ValidateRefreshToken validates the provided JWT.
-func ValidateRefreshToken(secret, signedToken string) (*CustomRefreshJWTClaims, error) {
+// ValidateChallengeToken validates the provided challenge token.
+func ValidateChallengeToken(app *Application, signedToken string) (*CustomChallengeJWTClaims, error) {
 	token, err := jwt.ParseWithClaims(
 		signedToken,
-		&CustomRefreshJWTClaims{},
+		&CustomChallengeJWTClaims{},
 		func(token *jwt.Token) (interface{}, error) {
-			return []byte(secret), nil
+			return []byte(app.Config.JWT.Refresh), nil
 		},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithAudience(ChallengeTokenAudience),
+		jwt.WithExpirationRequired(),
 	)
 	if err != nil {
 		return nil, errors.New("invalid or expired token")
 	}
 
-	claims, ok := token.Claims.(*CustomRefreshJWTClaims)
-	if !ok {
+	claims, ok := token.Claims.(*CustomChallengeJWTClaims)
+	if !ok || claims.Subject == "" {
 		return nil, errors.New("invalid or expired token")
 	}
 
 	return claims, nil
 }
+
+// GenerateLinkToken generates a short-lived token identifying a signed-in
+// user while they sign in with a third-party provider, to link their
+// account there. Like challenge tokens, it is signed with the refresh
+// secret.
+func GenerateLinkToken(app *Application, user *models.User, provider string) (string, int64, error) {
+	expiration := time.Now().Add(LinkTokenDuration)
+	claims := jwt.RegisteredClaims{
+		Subject:   user.ID,
+		Audience:  jwt.ClaimStrings{LinkTokenAudience, provider},
+		ExpiresAt: jwt.NewNumericDate(expiration),
+	}
+
+	token, err := jwt.NewWithClaims(
+		jwt.SigningMethodHS256,
+		claims,
+	).SignedString(
+		[]byte(app.Config.JWT.Refresh))
+
+	if err != nil {
+		return "", 0, err
+	}
+
+	return token, expiration.Unix(), nil
+}
+
+// ValidateLinkToken validates the provided link token for the provider,
+// returning the ID of the user linking their account.
+func ValidateLinkToken(app *Application, signedToken, provider string) (string, error) {
+	claims := &jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(
+		signedToken,
+		claims,
+		func(token *jwt.Token) (interface{}, error) {
+			return []byte(app.Config.JWT.Refresh), nil
+		},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithAudience(LinkTokenAudience),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil || claims.Subject == "" || !slices.Contains(claims.Audience, provider) {
+		return "", errors.New("invalid or expired token")
+	}
+
+	return claims.Subject, nil
+}
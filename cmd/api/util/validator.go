@@ -1,6 +1,12 @@
 package util
 
-import "github.com/go-playground/validator/v10"
+import (
+	"reflect"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/lokatalent/backend_go/internal/models"
+)
 
 // CustomValidator implements the validator interface of echo package
 type CustomValidator struct {
@@ -8,7 +14,15 @@ type CustomValidator struct {
 }
 
 func NewCustomValidator() *CustomValidator {
-	return &CustomValidator{validator: validator.New()}
+	v := validator.New()
+	// validate money in minor units, so gte=100 means at least 1 naira.
+	v.RegisterCustomTypeFunc(func(field reflect.Value) any {
+		if money, ok := field.Interface().(models.Money); ok {
+			return money.Minor()
+		}
+		return nil
+	}, models.Money{})
+	return &CustomValidator{validator: v}
 }
 
 func (cv *CustomValidator) Validate(i any) error {
@@ -0,0 +1,120 @@
+// Package audit describes the privileged actions recorded in the audit
+// log, and computes the before and after state stored for each of them.
+// Only the fields an action changed are kept, so that an entry reads as a
+// diff of the entity.
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// actions
+const (
+	ACTION_USER_ROLE_CHANGE = "user.role.change"
+	ACTION_USER_VERIFY      = "user.verify"
+
+	ACTION_PRICING_CREATE = "pricing.create"
+	ACTION_PRICING_UPDATE = "pricing.update"
+	ACTION_PRICING_DELETE = "pricing.delete"
+
+	ACTION_COMMISSION_CREATE = "commission.create"
+	ACTION_COMMISSION_UPDATE = "commission.update"
+
+	ACTION_ROLE_CREATE = "role.create"
+	ACTION_ROLE_UPDATE = "role.update"
+	ACTION_ROLE_DELETE = "role.delete"
+
+	// booking transitions made by an admin who is not a party to the
+	// booking.
+	ACTION_BOOKING_SELECT_PROVIDER = "booking.select_provider"
+	ACTION_BOOKING_ACCEPT          = "booking.accept"
+	ACTION_BOOKING_REJECT          = "booking.reject"
+	ACTION_BOOKING_START           = "booking.start"
+	ACTION_BOOKING_COMPLETE        = "booking.complete"
+	ACTION_BOOKING_CANCEL          = "booking.cancel"
+	ACTION_BOOKING_DISPUTE         = "booking.dispute"
+
+	ACTION_KYC_REVIEW           = "kyc.review"
+	ACTION_CERTIFICATION_REVIEW = "certification.review"
+
+	ACTION_OUTBOX_RETRY = "outbox.retry"
+	ACTION_JOB_RETRY    = "job.retry"
+)
+
+// entity types
+const (
+	ENTITY_USER          = "user"
+	ENTITY_PRICING       = "services_pricing"
+	ENTITY_COMMISSION    = "service_commission"
+	ENTITY_ROLE          = "role"
+	ENTITY_BOOKING       = "booking"
+	ENTITY_KYC           = "kyc_submission"
+	ENTITY_CERTIFICATION = "user_certification"
+	ENTITY_OUTBOX        = "outbox"
+	ENTITY_JOB           = "job"
+)
+
+// fields that change on every write and say nothing about the action.
+var ignoredFields = []string{"created_at", "updated_at"}
+
+// Diff returns the JSON encoded fields of before and after that differ.
+// Either may be nil, for created and deleted entities, in which case all
+// fields of the other are kept. A nil result means there is no state to
+// record on that side.
+func Diff(before, after any) (json.RawMessage, json.RawMessage, error) {
+	beforeFields, err := fields(before)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterFields, err := fields(after)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if beforeFields != nil && afterFields != nil {
+		for name, value := range beforeFields {
+			if other, ok := afterFields[name]; ok && reflect.DeepEqual(value, other) {
+				delete(beforeFields, name)
+				delete(afterFields, name)
+			}
+		}
+	}
+
+	beforeJSON, err := encode(beforeFields)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterJSON, err := encode(afterFields)
+	if err != nil {
+		return nil, nil, err
+	}
+	return beforeJSON, afterJSON, nil
+}
+
+// fields returns the top-level JSON fields of v.
+func fields(v any) (map[string]any, error) {
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Pointer && reflect.ValueOf(v).IsNil()) {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]any{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for _, name := range ignoredFields {
+		delete(fields, name)
+	}
+	return fields, nil
+}
+
+func encode(fields map[string]any) (json.RawMessage, error) {
+	if fields == nil {
+		return nil, nil
+	}
+	return json.Marshal(fields)
+}
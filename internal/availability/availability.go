@@ -0,0 +1,109 @@
+// Package availability answers whether a provider's schedule covers a
+// period of time. A period is covered when every instant of it falls in a
+// weekly range or an extra availability exception, and no blackout
+// exception overlaps it. The database mirrors these rules in
+// service_available for provider matching.
+package availability
+
+import (
+	"slices"
+	"time"
+
+	"github.com/lokatalent/backend_go/internal/models"
+)
+
+type interval struct {
+	start, end time.Time
+}
+
+// Covers reports whether schedule and exceptions make a provider
+// available for the whole of [start, end).
+func Covers(
+	schedule models.Availability,
+	exceptions []models.AvailabilityException,
+	start, end time.Time,
+) (bool, error) {
+	if !end.After(start) {
+		return false, nil
+	}
+
+	available := []interval{}
+	for _, exception := range exceptions {
+		switch exception.Kind {
+		case models.AVAILABILITY_BLACKOUT:
+			if exception.StartsAt.Before(end) && exception.EndsAt.After(start) {
+				return false, nil
+			}
+		case models.AVAILABILITY_EXTRA:
+			available = append(available, interval{exception.StartsAt, exception.EndsAt})
+		}
+	}
+
+	weekly, err := weeklyIntervals(schedule, start, end)
+	if err != nil {
+		return false, err
+	}
+	available = append(available, weekly...)
+
+	return covered(available, start, end), nil
+}
+
+// weeklyIntervals expands the weekly schedule into the instants it covers
+// on the days overlapping [start, end), in the schedule's timezone.
+func weeklyIntervals(schedule models.Availability, start, end time.Time) ([]interval, error) {
+	loc, err := schedule.Location()
+	if err != nil {
+		return nil, err
+	}
+
+	intervals := []interval{}
+	localStart := start.In(loc)
+	day := time.Date(localStart.Year(), localStart.Month(), localStart.Day(), 0, 0, 0, 0, loc)
+	for day.Before(end) {
+		for _, timeRange := range schedule.Day(day.Weekday()) {
+			from, to, err := timeRange.Minutes()
+			if err != nil {
+				// schedules saved before validation may hold free-form
+				// ranges, which cover nothing.
+				continue
+			}
+			intervals = append(intervals, interval{
+				start: atMinute(day, from),
+				end:   atMinute(day, to),
+			})
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return intervals, nil
+}
+
+// atMinute returns the instant minute minutes after midnight on day, as
+// read on a wall clock.
+func atMinute(day time.Time, minute int) time.Time {
+	return time.Date(
+		day.Year(), day.Month(), day.Day(),
+		minute/60, minute%60, 0, 0,
+		day.Location(),
+	)
+}
+
+// covered reports whether the union of intervals contains [start, end).
+func covered(intervals []interval, start, end time.Time) bool {
+	slices.SortFunc(intervals, func(a, b interval) int {
+		return a.start.Compare(b.start)
+	})
+
+	reached := start
+	for _, i := range intervals {
+		if i.start.After(reached) {
+			break
+		}
+		if i.end.After(reached) {
+			reached = i.end
+		}
+		if !reached.Before(end) {
+			return true
+		}
+	}
+	return false
+}
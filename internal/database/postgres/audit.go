@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/google/uuid"
+
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/repository"
+)
+
+type auditImplementation struct {
+	DB *sql.DB
+}
+
+func NewAuditImplementation(db *sql.DB) repository.AuditRepository {
+	return &auditImplementation{DB: db}
+}
+
+func (a *auditImplementation) Create(entry *models.AuditLog) error {
+	stmt := `
+    INSERT INTO audit_logs (
+        id,
+        actor_id,
+        action,
+        entity_type,
+        entity_id,
+        before,
+        after,
+        ip_address,
+        user_agent
+    ) VALUES (
+        $1, $2, $3, $4, $5, $6, $7, $8, $9
+    ) RETURNING created_at;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	if entry.ID == "" {
+		entry.ID = uuid.NewString()
+	}
+
+	return a.DB.QueryRowContext(
+		ctx,
+		stmt,
+		entry.ID,
+		entry.ActorID,
+		entry.Action,
+		entry.EntityType,
+		entry.EntityID,
+		nullJSON(entry.Before),
+		nullJSON(entry.After),
+		entry.IPAddress,
+		entry.UserAgent,
+	).Scan(&entry.CreatedAt)
+}
+
+func (a *auditImplementation) GetAll(filter models.AuditLogFilter) ([]models.AuditLog, error) {
+	stmt := `
+    SELECT
+        id,
+        actor_id,
+        action,
+        entity_type,
+        entity_id,
+        before,
+        after,
+        ip_address,
+        user_agent,
+        created_at
+    FROM audit_logs
+    WHERE
+        ($1 = '' OR actor_id = $1::UUID) AND
+        ($2 = '' OR action = $2) AND
+        ($3 = '' OR entity_type = $3) AND
+        ($4 = '' OR entity_id = $4) AND
+        ($5::TIMESTAMPTZ IS NULL OR created_at >= $5) AND
+        ($6::TIMESTAMPTZ IS NULL OR created_at < $6)
+    ORDER BY created_at DESC
+    LIMIT $7 OFFSET $8;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	rows, err := a.DB.QueryContext(
+		ctx,
+		stmt,
+		filter.ActorID,
+		filter.Action,
+		filter.EntityType,
+		filter.EntityID,
+		sql.NullTime{Time: filter.From, Valid: !filter.From.IsZero()},
+		sql.NullTime{Time: filter.To, Valid: !filter.To.IsZero()},
+		filter.Limit,
+		filter.Offset(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.AuditLog{}
+	for rows.Next() {
+		entry := models.AuditLog{}
+		var before, after []byte
+		err := rows.Scan(
+			&entry.ID,
+			&entry.ActorID,
+			&entry.Action,
+			&entry.EntityType,
+			&entry.EntityID,
+			&before,
+			&after,
+			&entry.IPAddress,
+			&entry.UserAgent,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entry.Before = before
+		entry.After = after
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// nullJSON stores missing state as NULL rather than the JSON null.
+func nullJSON(data json.RawMessage) sql.NullString {
+	return sql.NullString{String: string(data), Valid: data != nil}
+}
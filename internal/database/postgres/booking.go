@@ -4,11 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"slices"
-	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 
+	"github.com/lokatalent/backend_go/internal/geo"
 	"github.com/lokatalent/backend_go/internal/models"
 	"github.com/lokatalent/backend_go/internal/repository"
 )
@@ -30,6 +31,7 @@ func (b *bookingImplementation) Create(booking *models.Booking) error {
         id,
         requester_id,
         requester_addr,
+        requester_location,
         service_type,
         booking_type,
         service_desc,
@@ -41,11 +43,12 @@ func (b *bookingImplementation) Create(booking *models.Booking) error {
         status
     ) VALUES (
         $1, $2, $3, $4, $5, $6, $7, $8, $9, $10,
-        $11, $12
+        $11, $12, $13
     ) RETURNING 
         id,
         requester_id,
         requester_addr,
+        requester_location,
         service_type,
         booking_type,
         service_desc,
@@ -68,6 +71,7 @@ func (b *bookingImplementation) Create(booking *models.Booking) error {
 		booking.ID,
 		booking.RequesterID,
 		booking.RequesterAddr,
+		booking.RequesterLocation,
 		booking.ServiceType,
 		booking.BookingType,
 		booking.ServiceDesc,
@@ -81,6 +85,7 @@ func (b *bookingImplementation) Create(booking *models.Booking) error {
 		&booking.ID,
 		&booking.RequesterID,
 		&booking.RequesterAddr,
+		&booking.RequesterLocation,
 		&booking.ServiceType,
 		&booking.BookingType,
 		&booking.ServiceDesc,
@@ -107,6 +112,7 @@ func (b *bookingImplementation) GetByID(id string) (models.Booking, error) {
         requester_id,
         provider_id,
         requester_addr,
+        requester_location,
         service_type,
         booking_type,
         service_desc,
@@ -132,6 +138,7 @@ func (b *bookingImplementation) GetByID(id string) (models.Booking, error) {
 		&booking.RequesterID,
 		&booking.ProviderID,
 		&booking.RequesterAddr,
+		&booking.RequesterLocation,
 		&booking.ServiceType,
 		&booking.BookingType,
 		&booking.ServiceDesc,
@@ -185,6 +192,7 @@ func (b *bookingImplementation) GetAll(filter models.BookingFilter) ([]models.Bo
         requester_id,
         provider_id,
         requester_addr,
+        requester_location,
         service_type,
         booking_type,
         service_desc,
@@ -231,6 +239,7 @@ func (b *bookingImplementation) GetAll(filter models.BookingFilter) ([]models.Bo
 			&booking.RequesterID,
 			&booking.ProviderID,
 			&booking.RequesterAddr,
+			&booking.RequesterLocation,
 			&booking.ServiceType,
 			&booking.BookingType,
 			&booking.ServiceDesc,
@@ -253,18 +262,14 @@ func (b *bookingImplementation) GetAll(filter models.BookingFilter) ([]models.Bo
 	return bookings, nil
 }
 
-func (b *bookingImplementation) UpdateStatus(id, status string) (models.Booking, error) {
+func (b *bookingImplementation) GetExpired(limit int) ([]models.Booking, error) {
 	stmt := `
-	UPDATE bookings
-	SET
-	    status = $2,
-	    updated_at = now()
-	WHERE id = $1
-    RETURNING 
+    SELECT
         id,
         requester_id,
         provider_id,
         requester_addr,
+        requester_location,
         service_type,
         booking_type,
         service_desc,
@@ -276,48 +281,86 @@ func (b *bookingImplementation) UpdateStatus(id, status string) (models.Booking,
         actual_price,
         status,
         created_at,
-        updated_at;
+        updated_at
+    FROM bookings
+    WHERE
+        status = ANY($1::TEXT[])
+        AND start_date + start_time < now()
+    ORDER BY start_date, start_time
+    LIMIT $2;
     `
 	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
 	defer cancel()
 
-	booking := models.Booking{}
-	err := b.DB.QueryRowContext(ctx, stmt, id, status).Scan(
-		&booking.ID,
-		&booking.RequesterID,
-		&booking.ProviderID,
-		&booking.RequesterAddr,
-		&booking.ServiceType,
-		&booking.BookingType,
-		&booking.ServiceDesc,
-		&booking.StartTime,
-		&booking.EndTime,
-		&booking.StartDate,
-		&booking.EndDate,
-		&booking.TotalPrice,
-		&booking.ActualPrice,
-		&booking.Status,
-		&booking.CreatedAt,
-		&booking.UpdatedAt,
+	rows, err := b.DB.QueryContext(
+		ctx,
+		stmt,
+		pq.Array([]string{models.BOOKING_OPEN, models.BOOKING_PROVIDER_SELECTED}),
+		limit,
 	)
 	if err != nil {
-		return models.Booking{}, err
+		return nil, err
 	}
-	return booking, nil
+	defer rows.Close()
+
+	bookings := []models.Booking{}
+	for rows.Next() {
+		booking := models.Booking{}
+		err := rows.Scan(
+			&booking.ID,
+			&booking.RequesterID,
+			&booking.ProviderID,
+			&booking.RequesterAddr,
+			&booking.RequesterLocation,
+			&booking.ServiceType,
+			&booking.BookingType,
+			&booking.ServiceDesc,
+			&booking.StartTime,
+			&booking.EndTime,
+			&booking.StartDate,
+			&booking.EndDate,
+			&booking.TotalPrice,
+			&booking.ActualPrice,
+			&booking.Status,
+			&booking.CreatedAt,
+			&booking.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		bookings = append(bookings, booking)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return bookings, nil
 }
 
-func (b *bookingImplementation) AssignProvider(id, userID string) (models.Booking, error) {
+// TransitionStatus moves a booking from event.FromStatus to
+// event.ToStatus and records the event, with the payments and outbox
+// messages of its effects, in the same transaction. The provider in the
+// event is assigned when a provider is selected, and removed when the
+// booking returns to open. It returns ErrBookingStatusChanged if the
+// booking is no longer in FromStatus.
+func (b *bookingImplementation) TransitionStatus(id string, event *models.BookingEvent, effects models.TransitionEffects) (models.Booking, error) {
 	stmt := `
 	UPDATE bookings
 	SET
-	    provider_id = $2,
+	    status = $3,
+	    provider_id = CASE
+	        WHEN $3 = $5 THEN $4::UUID
+	        WHEN $3 = $6 THEN NULL
+	        ELSE provider_id
+	    END,
 	    updated_at = now()
-	WHERE id = $1
+	WHERE id = $1 AND status = $2
     RETURNING 
         id,
         requester_id,
         provider_id,
         requester_addr,
+        requester_location,
         service_type,
         booking_type,
         service_desc,
@@ -335,30 +378,147 @@ func (b *bookingImplementation) AssignProvider(id, userID string) (models.Bookin
 	defer cancel()
 
 	booking := models.Booking{}
-	err := b.DB.QueryRowContext(ctx, stmt, id, userID).Scan(
-		&booking.ID,
-		&booking.RequesterID,
-		&booking.ProviderID,
-		&booking.RequesterAddr,
-		&booking.ServiceType,
-		&booking.BookingType,
-		&booking.ServiceDesc,
-		&booking.StartTime,
-		&booking.EndTime,
-		&booking.StartDate,
-		&booking.EndDate,
-		&booking.TotalPrice,
-		&booking.ActualPrice,
-		&booking.Status,
-		&booking.CreatedAt,
-		&booking.UpdatedAt,
-	)
+	err := withTx(ctx, b.DB, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(
+			ctx,
+			stmt,
+			id,
+			event.FromStatus,
+			event.ToStatus,
+			event.ProviderID,
+			models.BOOKING_PROVIDER_SELECTED,
+			models.BOOKING_OPEN,
+		).Scan(
+			&booking.ID,
+			&booking.RequesterID,
+			&booking.ProviderID,
+			&booking.RequesterAddr,
+			&booking.RequesterLocation,
+			&booking.ServiceType,
+			&booking.BookingType,
+			&booking.ServiceDesc,
+			&booking.StartTime,
+			&booking.EndTime,
+			&booking.StartDate,
+			&booking.EndDate,
+			&booking.TotalPrice,
+			&booking.ActualPrice,
+			&booking.Status,
+			&booking.CreatedAt,
+			&booking.UpdatedAt,
+		)
+		if err != nil {
+			return err
+		}
+
+		if event.ID == "" {
+			event.ID = uuid.NewString()
+		}
+		event.BookingID = booking.ID
+		if !event.ProviderID.Valid {
+			event.ProviderID = booking.ProviderID
+		}
+		if err := insertBookingEvent(ctx, tx, event); err != nil {
+			return err
+		}
+
+		for _, payment := range effects.Payments {
+			err := insertPayment(ctx, tx, payment.Payment, payment.Entries)
+			if err != nil {
+				return err
+			}
+		}
+		return insertOutboxMessages(ctx, tx, effects.Outbox)
+	})
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Booking{}, repository.ErrBookingStatusChanged
+		}
 		return models.Booking{}, err
 	}
 	return booking, nil
 }
 
+func (b *bookingImplementation) GetEvents(bookingID string) ([]models.BookingEvent, error) {
+	stmt := `
+    SELECT
+        id,
+        booking_id,
+        action,
+        actor,
+        actor_id,
+        from_status,
+        to_status,
+        provider_id,
+        created_at
+    FROM booking_events
+    WHERE booking_id = $1
+    ORDER BY created_at;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	rows, err := b.DB.QueryContext(ctx, stmt, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []models.BookingEvent{}
+	for rows.Next() {
+		event := models.BookingEvent{}
+		err := rows.Scan(
+			&event.ID,
+			&event.BookingID,
+			&event.Action,
+			&event.Actor,
+			&event.ActorID,
+			&event.FromStatus,
+			&event.ToStatus,
+			&event.ProviderID,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func insertBookingEvent(ctx context.Context, tx *sql.Tx, event *models.BookingEvent) error {
+	stmt := `
+    INSERT INTO booking_events (
+        id,
+        booking_id,
+        action,
+        actor,
+        actor_id,
+        from_status,
+        to_status,
+        provider_id
+    ) VALUES (
+        $1, $2, $3, $4, $5, $6, $7, $8
+    ) RETURNING created_at;
+    `
+	return tx.QueryRowContext(
+		ctx,
+		stmt,
+		event.ID,
+		event.BookingID,
+		event.Action,
+		event.Actor,
+		event.ActorID,
+		event.FromStatus,
+		event.ToStatus,
+		event.ProviderID,
+	).Scan(&event.CreatedAt)
+}
+
 func (b *bookingImplementation) RejectBooking(id, userID string) error {
 	stmt := `
     INSERT INTO rejected_bookings (
@@ -405,72 +565,154 @@ func (b *bookingImplementation) CheckRejected(id, userID string) (bool, error) {
 	return true, nil
 }
 
-// match available service providers with a booking
+// HasOverlapping reports whether a provider has an accepted or
+// in-progress booking overlapping [start, end) on any of its days.
+func (b *bookingImplementation) HasOverlapping(providerID string, start, end time.Time) (bool, error) {
+	stmt := `
+    SELECT provider_booked($1::UUID, $2::TEXT[], $3::TIMESTAMPTZ, $4::TIMESTAMPTZ);
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	var overlapping bool
+	err := b.DB.QueryRowContext(
+		ctx,
+		stmt,
+		providerID,
+		pq.Array([]string{models.BOOKING_ACCEPTED, models.BOOKING_IN_PROGRESS}),
+		start,
+		end,
+	).Scan(&overlapping)
+	if err != nil {
+		return false, err
+	}
+	return overlapping, nil
+}
+
+// MatchServices finds services of the booking's type within
+// filter.RadiusKm of the booking, nearest first. Providers must be
+// available for the booking's hours on every day it spans, and have no
+// accepted or in-progress booking overlapping it. Services whose address
+// has not been geocoded are not matched.
 func (b *bookingImplementation) MatchServices(
 	booking *models.Booking,
 	filter models.ServiceFilter,
-) ([]models.UserService, error) {
+) ([]models.ServiceMatch, error) {
+	if !booking.RequesterLocation.HasCoordinates() {
+		return nil, repository.ErrMissingCoordinates
+	}
+
 	stmt := `
-    SELECT DISTINCT s.*
-    FROM services s
-    WHERE 
-        -- Match address by city, state, country
-        -- s.address LIKE '%' || $2 || ', ' || $3 || ', ' || $4
-        s.address ~* ($2 || ', ' || $3 || ', ' || $4 || '$')
-        
-        -- Match service type
-        AND s.service_type = $1
-        
-        -- Exclude services with users who have in-progress bookings
-        AND s.user_id NOT IN (
-            SELECT provider_id 
-            FROM bookings 
-            WHERE 
-                provider_id IS NOT NULL 
-                AND status = $5
-        )
-    LIMIT $6 OFFSET $7;
+    SELECT
+        id,
+        user_id,
+        service_type,
+        service_desc,
+        rate_per_hour,
+        experience_years,
+        availability,
+        address,
+        location,
+        created_at,
+        updated_at,
+        distance
+    FROM (
+        SELECT
+            s.*,
+            $4::FLOAT8 * 2 * ASIN(SQRT(
+                POWER(SIN(RADIANS(s.latitude - $2::FLOAT8) / 2), 2) +
+                COS(RADIANS($2::FLOAT8)) * COS(RADIANS(s.latitude)) *
+                POWER(SIN(RADIANS(s.longitude - $3::FLOAT8) / 2), 2)
+            )) AS distance
+        FROM services s
+        WHERE
+            s.service_type = $1
+
+            -- discard services outside the bounding box of the radius
+            AND s.latitude BETWEEN
+                $2::FLOAT8 - $6::FLOAT8 AND $2::FLOAT8 + $6::FLOAT8
+            AND s.longitude BETWEEN
+                $3::FLOAT8 - $7::FLOAT8 AND $3::FLOAT8 + $7::FLOAT8
+
+            AND s.user_id <> $8
+
+            -- weekly availability and exceptions cover the booking
+            AND service_available(
+                s.user_id, s.availability,
+                $10::DATE, $11::DATE, $12::TIMETZ, $13::TIMETZ
+            )
+
+            -- Exclude providers with bookings overlapping this one on
+            -- any of its days
+            AND NOT EXISTS (
+                SELECT 1
+                FROM generate_series($10::DATE, $11::DATE, INTERVAL '1 day') AS day
+                WHERE provider_booked(
+                    s.user_id, $9::TEXT[],
+                    day::DATE + $12::TIMETZ, day::DATE + $13::TIMETZ
+                )
+            )
+    ) matches
+    WHERE distance <= $5::FLOAT8
+    ORDER BY distance, id
+    LIMIT $14 OFFSET $15;
     `
 	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
 	defer cancel()
 
-	splittedAddr := strings.Split(booking.RequesterAddr, ",")
-	slices.Reverse(splittedAddr)
+	lat := *booking.RequesterLocation.Latitude
+	lng := *booking.RequesterLocation.Longitude
+	latDelta, lngDelta := geo.BoundingBox(lat, filter.RadiusKm)
 
 	rows, err := b.DB.QueryContext(
 		ctx,
 		stmt,
 		booking.ServiceType,
-		strings.TrimSpace(splittedAddr[2]), // city
-		strings.TrimSpace(splittedAddr[1]), // state
-		strings.TrimSpace(splittedAddr[0]), // country
-		models.BOOKING_IN_PROGRESS,
+		lat,
+		lng,
+		geo.EARTH_RADIUS_KM,
+		filter.RadiusKm,
+		latDelta,
+		lngDelta,
+		booking.RequesterID,
+		pq.Array([]string{models.BOOKING_ACCEPTED, models.BOOKING_IN_PROGRESS}),
+		booking.StartDate,
+		booking.EndDate,
+		booking.StartTime,
+		booking.EndTime,
 		filter.Limit,
 		filter.Offset(),
 	)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	services := []models.UserService{}
+	matches := []models.ServiceMatch{}
 	for rows.Next() {
-		service := models.UserService{}
+		match := models.ServiceMatch{}
 		err := rows.Scan(
-			&service.ID,
-			&service.UserID,
-			&service.ServiceType,
-			&service.ServiceDesc,
-			&service.RatePerHour,
-			&service.ExperienceYears,
-			&service.Availability,
-			&service.Address,
-			&service.CreatedAt,
-			&service.UpdatedAt,
+			&match.ID,
+			&match.UserID,
+			&match.ServiceType,
+			&match.ServiceDesc,
+			&match.RatePerHour,
+			&match.ExperienceYears,
+			&match.Availability,
+			&match.Address,
+			&match.Location,
+			&match.CreatedAt,
+			&match.UpdatedAt,
+			&match.DistanceKm,
 		)
 		if err != nil {
 			return nil, err
 		}
-		services = append(services, service)
+		matches = append(matches, match)
 	}
-	return services, nil
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return matches, nil
 }
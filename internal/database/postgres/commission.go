@@ -3,7 +3,7 @@ package postgres
 import (
 	"context"
 	"database/sql"
-	// "errors"
+	"errors"
 	// "strings"
 
 	"github.com/google/uuid"
@@ -80,6 +80,9 @@ func (c *commissionImplementation) GetServiceCommission() (models.ServiceCommiss
 		&serviceCommission.UpdatedAt,
 	)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ServiceCommission{}, repository.ErrRecordNotFound
+		}
 		return models.ServiceCommission{}, err
 	}
 
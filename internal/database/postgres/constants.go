@@ -13,3 +13,26 @@ const (
 	duplicateService     = "unique_user_id_service_type"
 	duplicateBankAcctNum = "users_bank_info_account_num_key"
 )
+
+// reviews table constraints
+const (
+	duplicateReview = "unique_booking_id_reviewer_role"
+)
+
+// roles table constraints
+const (
+	duplicateRole     = "roles_pkey"
+	unknownPermission = "role_permissions_permission_fkey"
+	roleInUse         = "users_role_fkey"
+)
+
+// user_identities table constraints
+const (
+	duplicateIdentitySubject  = "unique_identity_subject"
+	duplicateIdentityProvider = "unique_identity_user_provider"
+)
+
+// kyc_submissions table constraints
+const (
+	duplicatePendingKYC = "unique_kyc_pending_user"
+)
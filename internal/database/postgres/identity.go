@@ -0,0 +1,164 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/repository"
+)
+
+type identityImplementation struct {
+	DB *sql.DB
+}
+
+func NewIdentityImplementation(db *sql.DB) repository.IdentityRepository {
+	return &identityImplementation{DB: db}
+}
+
+func (i *identityImplementation) Create(identity *models.UserIdentity) error {
+	stmt := `
+    INSERT INTO user_identities (
+        id,
+        user_id,
+        provider,
+        subject,
+        email
+    ) VALUES (
+        $1, $2, $3, $4, $5
+    ) RETURNING created_at;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	if identity.ID == "" {
+		identity.ID = uuid.NewString()
+	}
+
+	err := i.DB.QueryRowContext(
+		ctx,
+		stmt,
+		identity.ID,
+		identity.UserID,
+		identity.Provider,
+		identity.Subject,
+		identity.Email,
+	).Scan(&identity.CreatedAt)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), duplicateIdentitySubject),
+			strings.Contains(err.Error(), duplicateIdentityProvider):
+			return repository.ErrIdentityLinked
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (i *identityImplementation) GetBySubject(provider, subject string) (models.UserIdentity, error) {
+	stmt := `
+    SELECT
+        id,
+        user_id,
+        provider,
+        subject,
+        email,
+        created_at
+    FROM user_identities
+    WHERE provider = $1 AND subject = $2;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	identity := models.UserIdentity{}
+	err := i.DB.QueryRowContext(ctx, stmt, provider, subject).Scan(
+		&identity.ID,
+		&identity.UserID,
+		&identity.Provider,
+		&identity.Subject,
+		&identity.Email,
+		&identity.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.UserIdentity{}, repository.ErrRecordNotFound
+		}
+		return models.UserIdentity{}, err
+	}
+
+	return identity, nil
+}
+
+func (i *identityImplementation) GetAll(userID string) ([]models.UserIdentity, error) {
+	stmt := `
+    SELECT
+        id,
+        user_id,
+        provider,
+        subject,
+        email,
+        created_at
+    FROM user_identities
+    WHERE user_id = $1
+    ORDER BY created_at;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	rows, err := i.DB.QueryContext(ctx, stmt, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	identities := []models.UserIdentity{}
+	for rows.Next() {
+		identity := models.UserIdentity{}
+		err := rows.Scan(
+			&identity.ID,
+			&identity.UserID,
+			&identity.Provider,
+			&identity.Subject,
+			&identity.Email,
+			&identity.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		identities = append(identities, identity)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return identities, nil
+}
+
+func (i *identityImplementation) Delete(userID, provider string) error {
+	stmt := `
+    DELETE FROM user_identities
+    WHERE user_id = $1 AND provider = $2;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	result, err := i.DB.ExecContext(ctx, stmt, userID, provider)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
+}
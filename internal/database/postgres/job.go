@@ -0,0 +1,402 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/repository"
+)
+
+type jobImplementation struct {
+	DB *sql.DB
+}
+
+func NewJobImplementation(db *sql.DB) repository.JobRepository {
+	return &jobImplementation{DB: db}
+}
+
+func (j *jobImplementation) Enqueue(job *models.Job) error {
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	return withTx(ctx, j.DB, func(tx *sql.Tx) error {
+		return insertJob(ctx, tx, job)
+	})
+}
+
+func (j *jobImplementation) EnqueueScheduled(name string, next time.Time, job *models.Job) (bool, error) {
+	// schedules seen for the first time are due immediately.
+	insertStmt := `
+    INSERT INTO job_schedules (
+        name,
+        next_run_at
+    ) VALUES (
+        $1, now()
+    )
+    ON CONFLICT (name) DO NOTHING;
+    `
+	advanceStmt := `
+    UPDATE job_schedules
+    SET next_run_at = $2
+    WHERE name = $1 AND next_run_at <= now();
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	enqueued := false
+	err := withTx(ctx, j.DB, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, insertStmt, name); err != nil {
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx, advanceStmt, name, next)
+		if err != nil {
+			return err
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			// not due, or enqueued by another instance.
+			return nil
+		}
+
+		enqueued = true
+		return insertJob(ctx, tx, job)
+	})
+	if err != nil {
+		return false, err
+	}
+	return enqueued, nil
+}
+
+func (j *jobImplementation) Claim(kinds []string, lease time.Duration) (models.Job, error) {
+	// jobs whose worker stopped during their last attempt are not run
+	// again.
+	expireStmt := `
+    UPDATE jobs
+    SET
+        status = $3,
+        last_error = 'lease expired on the last attempt',
+        locked_until = NULL,
+        updated_at = now(),
+        finished_at = now()
+    WHERE
+        kind = ANY($1::TEXT[]) AND
+        status = $2 AND
+        locked_until < now() AND
+        attempts >= max_attempts;
+    `
+	stmt := `
+    UPDATE jobs
+    SET
+        status = $4,
+        attempts = attempts + 1,
+        locked_until = now() + $2 * INTERVAL '1 second',
+        updated_at = now()
+    WHERE id = (
+        SELECT id
+        FROM jobs
+        WHERE
+            kind = ANY($1::TEXT[]) AND
+            (
+                (status = $3 AND run_at <= now()) OR
+                (status = $4 AND locked_until < now() AND attempts < max_attempts)
+            )
+        ORDER BY run_at
+        LIMIT 1
+        FOR UPDATE SKIP LOCKED
+    )
+    RETURNING
+        id,
+        kind,
+        payload,
+        status,
+        attempts,
+        max_attempts,
+        last_error,
+        run_at,
+        created_at,
+        updated_at,
+        finished_at;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	_, err := j.DB.ExecContext(
+		ctx,
+		expireStmt,
+		pq.Array(kinds),
+		models.JOB_RUNNING,
+		models.JOB_FAILED,
+	)
+	if err != nil {
+		return models.Job{}, err
+	}
+
+	job, err := scanJob(j.DB.QueryRowContext(
+		ctx,
+		stmt,
+		pq.Array(kinds),
+		lease.Seconds(),
+		models.JOB_PENDING,
+		models.JOB_RUNNING,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Job{}, repository.ErrRecordNotFound
+		}
+		return models.Job{}, err
+	}
+	return job, nil
+}
+
+func (j *jobImplementation) Complete(id string, attempt int) error {
+	stmt := `
+    UPDATE jobs
+    SET
+        status = $3,
+        last_error = '',
+        locked_until = NULL,
+        updated_at = now(),
+        finished_at = now()
+    WHERE id = $1 AND attempts = $2 AND status = $4;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	result, err := j.DB.ExecContext(
+		ctx,
+		stmt,
+		id,
+		attempt,
+		models.JOB_COMPLETED,
+		models.JOB_RUNNING,
+	)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return repository.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (j *jobImplementation) Fail(id string, attempt int, lastError string, retryAt time.Time, final bool) error {
+	stmt := `
+    UPDATE jobs
+    SET
+        status = CASE WHEN $5 THEN $6 ELSE $7 END,
+        last_error = $3,
+        run_at = $4,
+        locked_until = NULL,
+        updated_at = now(),
+        finished_at = CASE WHEN $5 THEN now() ELSE NULL END
+    WHERE id = $1 AND attempts = $2 AND status = $8;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	result, err := j.DB.ExecContext(
+		ctx,
+		stmt,
+		id,
+		attempt,
+		lastError,
+		retryAt,
+		final,
+		models.JOB_FAILED,
+		models.JOB_PENDING,
+		models.JOB_RUNNING,
+	)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return repository.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (j *jobImplementation) GetAll(filter models.JobFilter) ([]models.Job, error) {
+	stmt := `
+    SELECT
+        id,
+        kind,
+        payload,
+        status,
+        attempts,
+        max_attempts,
+        last_error,
+        run_at,
+        created_at,
+        updated_at,
+        finished_at
+    FROM jobs
+    WHERE
+        ($1 = '' OR kind = $1) AND
+        ($2 = '' OR status = $2)
+    ORDER BY created_at DESC
+    LIMIT $3 OFFSET $4;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	rows, err := j.DB.QueryContext(
+		ctx,
+		stmt,
+		filter.Kind,
+		filter.Status,
+		filter.Limit,
+		filter.Offset(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := []models.Job{}
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+func (j *jobImplementation) Retry(id string) (models.Job, error) {
+	stmt := `
+    UPDATE jobs
+    SET
+        status = $2,
+        attempts = 0,
+        run_at = now(),
+        updated_at = now(),
+        finished_at = NULL
+    WHERE id = $1 AND status = $3
+    RETURNING
+        id,
+        kind,
+        payload,
+        status,
+        attempts,
+        max_attempts,
+        last_error,
+        run_at,
+        created_at,
+        updated_at,
+        finished_at;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	job, err := scanJob(j.DB.QueryRowContext(
+		ctx,
+		stmt,
+		id,
+		models.JOB_PENDING,
+		models.JOB_FAILED,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Job{}, repository.ErrJobNotFailed
+		}
+		return models.Job{}, err
+	}
+	return job, nil
+}
+
+func (j *jobImplementation) Prune(before time.Time) (int64, error) {
+	stmt := `
+    DELETE FROM jobs
+    WHERE status = $1 AND finished_at < $2;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	result, err := j.DB.ExecContext(ctx, stmt, models.JOB_COMPLETED, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func insertJob(ctx context.Context, tx *sql.Tx, job *models.Job) error {
+	if job.ID == "" {
+		job.ID = uuid.NewString()
+	}
+	if len(job.Payload) == 0 {
+		job.Payload = []byte("{}")
+	}
+	if job.RunAt.IsZero() {
+		job.RunAt = time.Now()
+	}
+	stmt := `
+    INSERT INTO jobs (
+        id,
+        kind,
+        payload,
+        max_attempts,
+        run_at
+    ) VALUES (
+        $1, $2, $3, $4, $5
+    ) RETURNING
+        status,
+        attempts,
+        last_error,
+        created_at,
+        updated_at;
+    `
+	return tx.QueryRowContext(
+		ctx,
+		stmt,
+		job.ID,
+		job.Kind,
+		// sent as text, since byte slices are encoded as bytea.
+		string(job.Payload),
+		job.MaxAttempts,
+		job.RunAt,
+	).Scan(
+		&job.Status,
+		&job.Attempts,
+		&job.LastError,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+	)
+}
+
+// scanJob scans a jobs row selected with all its columns.
+func scanJob(row interface{ Scan(...any) error }) (models.Job, error) {
+	job := models.Job{}
+	err := row.Scan(
+		&job.ID,
+		&job.Kind,
+		&job.Payload,
+		&job.Status,
+		&job.Attempts,
+		&job.MaxAttempts,
+		&job.LastError,
+		&job.RunAt,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+		&job.FinishedAt,
+	)
+	return job, err
+}
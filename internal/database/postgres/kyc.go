@@ -0,0 +1,236 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/repository"
+)
+
+type kycImplementation struct {
+	DB *sql.DB
+}
+
+func NewKYCImplementation(db *sql.DB) repository.KYCRepository {
+	return &kycImplementation{DB: db}
+}
+
+func (k *kycImplementation) Create(submission *models.KYCSubmission) error {
+	stmt := `
+    INSERT INTO kyc_submissions (
+        id,
+        user_id,
+        document_type,
+        document_key,
+        selfie_key,
+        status
+    ) VALUES (
+        $1, $2, $3, $4, $5, $6
+    ) RETURNING
+        created_at,
+        updated_at;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	if submission.ID == "" {
+		submission.ID = uuid.NewString()
+	}
+	submission.Status = models.KYC_PENDING
+
+	err := k.DB.QueryRowContext(
+		ctx,
+		stmt,
+		submission.ID,
+		submission.UserID,
+		submission.DocumentType,
+		submission.DocumentKey,
+		submission.SelfieKey,
+		submission.Status,
+	).Scan(&submission.CreatedAt, &submission.UpdatedAt)
+	if err != nil {
+		if strings.Contains(err.Error(), duplicatePendingKYC) {
+			return repository.ErrKYCPending
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (k *kycImplementation) GetByID(id string) (models.KYCSubmission, error) {
+	stmt := `
+    SELECT
+        id,
+        user_id,
+        document_type,
+        document_key,
+        selfie_key,
+        status,
+        reason,
+        reviewer_id,
+        reviewed_at,
+        created_at,
+        updated_at
+    FROM kyc_submissions
+    WHERE id = $1;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	submission, err := scanKYCSubmission(k.DB.QueryRowContext(ctx, stmt, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.KYCSubmission{}, repository.ErrRecordNotFound
+		}
+		return models.KYCSubmission{}, err
+	}
+
+	return submission, nil
+}
+
+func (k *kycImplementation) GetLatest(userID string) (models.KYCSubmission, error) {
+	stmt := `
+    SELECT
+        id,
+        user_id,
+        document_type,
+        document_key,
+        selfie_key,
+        status,
+        reason,
+        reviewer_id,
+        reviewed_at,
+        created_at,
+        updated_at
+    FROM kyc_submissions
+    WHERE user_id = $1
+    ORDER BY created_at DESC
+    LIMIT 1;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	submission, err := scanKYCSubmission(k.DB.QueryRowContext(ctx, stmt, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.KYCSubmission{}, repository.ErrRecordNotFound
+		}
+		return models.KYCSubmission{}, err
+	}
+
+	return submission, nil
+}
+
+func (k *kycImplementation) GetAll(filter models.KYCFilter) ([]models.KYCSubmission, error) {
+	stmt := `
+    SELECT
+        id,
+        user_id,
+        document_type,
+        document_key,
+        selfie_key,
+        status,
+        reason,
+        reviewer_id,
+        reviewed_at,
+        created_at,
+        updated_at
+    FROM kyc_submissions
+    WHERE
+        ($1 = '' OR user_id = $1::UUID) AND
+        ($2 = '' OR status = $2)
+    ORDER BY created_at
+    LIMIT $3 OFFSET $4;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	rows, err := k.DB.QueryContext(
+		ctx,
+		stmt,
+		filter.UserID,
+		filter.Status,
+		filter.Limit,
+		filter.Offset(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	submissions := []models.KYCSubmission{}
+	for rows.Next() {
+		submission, err := scanKYCSubmission(rows)
+		if err != nil {
+			return nil, err
+		}
+		submissions = append(submissions, submission)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return submissions, nil
+}
+
+func (k *kycImplementation) Review(submission *models.KYCSubmission) error {
+	stmt := `
+    UPDATE kyc_submissions
+    SET
+        status = $2,
+        reason = $3,
+        reviewer_id = $4,
+        reviewed_at = now(),
+        updated_at = now()
+    WHERE id = $1 AND status = $5
+    RETURNING
+        reviewed_at,
+        updated_at;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	err := k.DB.QueryRowContext(
+		ctx,
+		stmt,
+		submission.ID,
+		submission.Status,
+		submission.Reason,
+		submission.ReviewerID,
+		models.KYC_PENDING,
+	).Scan(&submission.ReviewedAt, &submission.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return repository.ErrKYCReviewed
+		}
+		return err
+	}
+
+	return nil
+}
+
+// scanKYCSubmission scans a kyc_submissions row selected with all its
+// columns.
+func scanKYCSubmission(row interface{ Scan(...any) error }) (models.KYCSubmission, error) {
+	submission := models.KYCSubmission{}
+	err := row.Scan(
+		&submission.ID,
+		&submission.UserID,
+		&submission.DocumentType,
+		&submission.DocumentKey,
+		&submission.SelfieKey,
+		&submission.Status,
+		&submission.Reason,
+		&submission.ReviewerID,
+		&submission.ReviewedAt,
+		&submission.CreatedAt,
+		&submission.UpdatedAt,
+	)
+	return submission, err
+}
@@ -0,0 +1,353 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/lokatalent/backend_go/internal/ledger"
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/repository"
+)
+
+type ledgerImplementation struct {
+	DB *sql.DB
+}
+
+func NewLedgerImplementation(db *sql.DB) repository.LedgerRepository {
+	return &ledgerImplementation{DB: db}
+}
+
+func (l *ledgerImplementation) PostEntry(entry *models.LedgerEntry) error {
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	return withTx(ctx, l.DB, func(tx *sql.Tx) error {
+		return postLedgerEntry(ctx, tx, entry)
+	})
+}
+
+func (l *ledgerImplementation) GetAccounts(filter models.LedgerFilter) ([]models.LedgerAccount, error) {
+	stmt := `
+    SELECT
+        a.id,
+        a.type,
+        a.user_id,
+        a.currency,
+        COALESCE(SUM(p.amount), 0),
+        a.created_at
+    FROM ledger_accounts a
+    LEFT JOIN ledger_postings p ON p.account_id = a.id
+    WHERE
+        ($1 = '' OR a.type = $1) AND
+        ($2 = '' OR a.user_id = $2::UUID)
+    GROUP BY a.id
+    ORDER BY a.type, a.created_at
+    LIMIT $3 OFFSET $4;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	rows, err := l.DB.QueryContext(
+		ctx,
+		stmt,
+		filter.AccountType,
+		filter.UserID,
+		filter.Limit,
+		filter.Offset(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accounts := []models.LedgerAccount{}
+	for rows.Next() {
+		account := models.LedgerAccount{}
+		var sum models.Money
+		err := rows.Scan(
+			&account.ID,
+			&account.Type,
+			&account.UserID,
+			&account.Currency,
+			&sum,
+			&account.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		account.Balance = ledger.Balance(account.Type, sum)
+		accounts = append(accounts, account)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return accounts, nil
+}
+
+func (l *ledgerImplementation) GetEntries(filter models.LedgerFilter) ([]models.LedgerEntry, error) {
+	stmt := `
+    SELECT
+        e.id,
+        e.description,
+        e.payment_id,
+        e.booking_id,
+        e.created_at
+    FROM ledger_entries e
+    WHERE
+        ($1 = '' OR e.booking_id = $1::UUID) AND
+        ($2 = '' OR e.payment_id = $2::UUID) AND
+        (
+            ($3 = '' AND $4 = '') OR EXISTS (
+                SELECT 1
+                FROM ledger_postings p
+                JOIN ledger_accounts a ON a.id = p.account_id
+                WHERE
+                    p.entry_id = e.id AND
+                    ($3 = '' OR a.type = $3) AND
+                    ($4 = '' OR a.user_id = $4::UUID)
+            )
+        )
+    ORDER BY e.created_at DESC
+    LIMIT $5 OFFSET $6;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	rows, err := l.DB.QueryContext(
+		ctx,
+		stmt,
+		filter.BookingID,
+		filter.PaymentID,
+		filter.AccountType,
+		filter.UserID,
+		filter.Limit,
+		filter.Offset(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.LedgerEntry{}
+	entryIndex := map[string]int{}
+	entryIDs := []string{}
+	for rows.Next() {
+		entry := models.LedgerEntry{Postings: []models.LedgerPosting{}}
+		err := rows.Scan(
+			&entry.ID,
+			&entry.Description,
+			&entry.PaymentID,
+			&entry.BookingID,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entryIndex[entry.ID] = len(entries)
+		entryIDs = append(entryIDs, entry.ID)
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return entries, nil
+	}
+
+	stmt = `
+    SELECT
+        p.id,
+        p.entry_id,
+        p.account_id,
+        a.type,
+        COALESCE(a.user_id::TEXT, ''),
+        p.amount,
+        p.created_at
+    FROM ledger_postings p
+    JOIN ledger_accounts a ON a.id = p.account_id
+    WHERE p.entry_id = ANY($1::UUID[])
+    ORDER BY p.created_at, p.amount DESC;
+    `
+	postingRows, err := l.DB.QueryContext(ctx, stmt, pq.Array(entryIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer postingRows.Close()
+
+	for postingRows.Next() {
+		posting := models.LedgerPosting{}
+		err := postingRows.Scan(
+			&posting.ID,
+			&posting.EntryID,
+			&posting.AccountID,
+			&posting.AccountType,
+			&posting.UserID,
+			&posting.Amount,
+			&posting.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		i := entryIndex[posting.EntryID]
+		entries[i].Postings = append(entries[i].Postings, posting)
+	}
+	if err := postingRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// postLedgerEntry writes a balanced journal entry within tx. User-owned
+// accounts touched by the entry are locked and must not go negative, in
+// which case ErrInsufficientFunds is returned.
+func postLedgerEntry(ctx context.Context, tx *sql.Tx, entry *models.LedgerEntry) error {
+	if err := ledger.Validate(*entry); err != nil {
+		return err
+	}
+	if entry.ID == "" {
+		entry.ID = uuid.NewString()
+	}
+
+	stmt := `
+    INSERT INTO ledger_entries (
+        id,
+        description,
+        payment_id,
+        booking_id
+    ) VALUES (
+        $1, $2, $3, $4
+    ) RETURNING created_at;
+    `
+	err := tx.QueryRowContext(
+		ctx,
+		stmt,
+		entry.ID,
+		entry.Description,
+		entry.PaymentID,
+		entry.BookingID,
+	).Scan(&entry.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	// resolve accounts in a stable order so concurrent entries lock user
+	// accounts consistently.
+	type accountKey struct{ accountType, userID string }
+	keys := []accountKey{}
+	accountIDs := map[accountKey]string{}
+	for _, posting := range entry.Postings {
+		key := accountKey{posting.AccountType, posting.UserID}
+		if _, ok := accountIDs[key]; !ok {
+			accountIDs[key] = ""
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].accountType != keys[j].accountType {
+			return keys[i].accountType < keys[j].accountType
+		}
+		return keys[i].userID < keys[j].userID
+	})
+	for _, key := range keys {
+		accountID, err := getOrCreateLedgerAccount(ctx, tx, key.accountType, key.userID)
+		if err != nil {
+			return err
+		}
+		accountIDs[key] = accountID
+	}
+
+	stmt = `
+    INSERT INTO ledger_postings (
+        id,
+        entry_id,
+        account_id,
+        amount
+    ) VALUES (
+        $1, $2, $3, $4
+    ) RETURNING created_at;
+    `
+	for i := range entry.Postings {
+		posting := &entry.Postings[i]
+		posting.ID = uuid.NewString()
+		posting.EntryID = entry.ID
+		posting.AccountID = accountIDs[accountKey{posting.AccountType, posting.UserID}]
+		err := tx.QueryRowContext(
+			ctx,
+			stmt,
+			posting.ID,
+			posting.EntryID,
+			posting.AccountID,
+			posting.Amount,
+		).Scan(&posting.CreatedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	stmt = `
+    SELECT COALESCE(SUM(amount), 0)
+    FROM ledger_postings
+    WHERE account_id = $1;
+    `
+	for _, key := range keys {
+		if key.userID == "" {
+			continue
+		}
+		var sum models.Money
+		err := tx.QueryRowContext(ctx, stmt, accountIDs[key]).Scan(&sum)
+		if err != nil {
+			return err
+		}
+		if ledger.Balance(key.accountType, sum).IsNegative() {
+			return repository.ErrInsufficientFunds
+		}
+	}
+
+	return nil
+}
+
+// getOrCreateLedgerAccount returns the id of the account for accountType
+// and userID, creating it on first use. User accounts are locked for the
+// rest of the transaction.
+func getOrCreateLedgerAccount(ctx context.Context, tx *sql.Tx, accountType, userID string) (string, error) {
+	stmt := `
+    INSERT INTO ledger_accounts (
+        id,
+        type,
+        user_id
+    ) VALUES (
+        $1, $2, NULLIF($3, '')::UUID
+    ) ON CONFLICT DO NOTHING;
+    `
+	_, err := tx.ExecContext(ctx, stmt, uuid.NewString(), accountType, userID)
+	if err != nil {
+		return "", err
+	}
+
+	if userID == "" {
+		stmt = `
+        SELECT id
+        FROM ledger_accounts
+        WHERE type = $1 AND user_id IS NULL;
+        `
+		var accountID string
+		err = tx.QueryRowContext(ctx, stmt, accountType).Scan(&accountID)
+		return accountID, err
+	}
+
+	stmt = `
+    SELECT id
+    FROM ledger_accounts
+    WHERE type = $1 AND user_id = $2
+    FOR UPDATE;
+    `
+	var accountID string
+	err = tx.QueryRowContext(ctx, stmt, accountType, userID).Scan(&accountID)
+	return accountID, err
+}
@@ -3,7 +3,7 @@ package postgres
 import (
 	"context"
 	"database/sql"
-	// "errors"
+	"errors"
 
 	"github.com/google/uuid"
 
@@ -19,7 +19,7 @@ func NewNotificationImplementation(db *sql.DB) repository.NotificationRepository
 	return &notificationImplementation{DB: db}
 }
 
-func (n *notificationImplementation) Create(notification *models.Notification) error {
+func (n *notificationImplementation) Create(notification *models.Notification, messages ...models.OutboxMessage) error {
 	if notification.ID == "" {
 		notification.ID = uuid.NewString()
 	}
@@ -32,7 +32,9 @@ func (n *notificationImplementation) Create(notification *models.Notification) e
         message
     ) VALUES (
         $1, $2, $3, $4, $5
-    ) RETURNING
+    )
+    ON CONFLICT (id) DO NOTHING
+    RETURNING
         id,
         type,
         user_id,
@@ -44,24 +46,34 @@ func (n *notificationImplementation) Create(notification *models.Notification) e
 	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
 	defer cancel()
 
-	err := n.DB.QueryRowContext(
-		ctx,
-		stmt,
-		notification.ID,
-		notification.Type,
-		notification.UserID,
-		notification.BookingID,
-		notification.Message,
-	).Scan(
-		&notification.ID,
-		&notification.Type,
-		&notification.UserID,
-		&notification.BookingID,
-		&notification.Message,
-		&notification.Seen,
-		&notification.CreatedAt,
-	)
+	err := withTx(ctx, n.DB, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(
+			ctx,
+			stmt,
+			notification.ID,
+			notification.Type,
+			notification.UserID,
+			notification.BookingID,
+			notification.Message,
+		).Scan(
+			&notification.ID,
+			&notification.Type,
+			&notification.UserID,
+			&notification.BookingID,
+			&notification.Message,
+			&notification.Seen,
+			&notification.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+
+		return insertOutboxMessages(ctx, tx, messages)
+	})
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return repository.ErrNotificationExists
+		}
 		return err
 	}
 	return nil
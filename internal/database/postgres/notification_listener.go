@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/lokatalent/backend_go/internal/realtime"
+)
+
+// channel notification events are sent on, by the triggers of the
+// notifications table.
+const notificationChannel = "notifications"
+
+const (
+	listenerMinReconnect = 10 * time.Second
+	listenerMaxReconnect = time.Minute
+	// interval between checks of an idle listener's connection.
+	listenerPingInterval = 90 * time.Second
+)
+
+// ListenNotifications delivers the notification events sent by any
+// instance to the hub, until ctx is done. The listener has its own
+// connection, reconnecting when it is lost, after which subscribers are
+// told to resync as events may have been missed.
+func ListenNotifications(ctx context.Context, dsn string, hub *realtime.Hub) error {
+	listener := pq.NewListener(
+		dsn,
+		listenerMinReconnect,
+		listenerMaxReconnect,
+		func(event pq.ListenerEventType, err error) {
+			if err != nil {
+				log.Printf("notification listener: %v\n", err)
+			}
+		},
+	)
+	defer listener.Close()
+
+	if err := listener.Listen(notificationChannel); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(listenerPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case notification := <-listener.Notify:
+			if notification == nil {
+				// the connection was reestablished
+				hub.Broadcast(realtime.Event{Type: realtime.EVENT_RESYNC})
+				continue
+			}
+			event := realtime.Event{}
+			err := json.Unmarshal([]byte(notification.Extra), &event)
+			if err != nil {
+				log.Printf("notification listener: %v\n", err)
+				continue
+			}
+			hub.Publish(event)
+		case <-ticker.C:
+			go listener.Ping()
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/repository"
+)
+
+func (n *notificationImplementation) GetPreferences(userID string) ([]models.NotificationPreference, error) {
+	stmt := `
+    SELECT
+        user_id,
+        type,
+        channels,
+        updated_at
+    FROM notification_preferences
+    WHERE user_id = $1
+    ORDER BY type;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	rows, err := n.DB.QueryContext(ctx, stmt, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	preferences := []models.NotificationPreference{}
+	for rows.Next() {
+		preference := models.NotificationPreference{}
+		err := rows.Scan(
+			&preference.UserID,
+			&preference.Type,
+			pq.Array(&preference.Channels),
+			&preference.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		preferences = append(preferences, preference)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return preferences, nil
+}
+
+func (n *notificationImplementation) SetPreference(preference *models.NotificationPreference) error {
+	stmt := `
+    INSERT INTO notification_preferences (
+        user_id,
+        type,
+        channels
+    ) VALUES (
+        $1, $2, $3
+    )
+    ON CONFLICT (user_id, type) DO UPDATE
+    SET
+        channels = EXCLUDED.channels,
+        updated_at = now()
+    RETURNING updated_at;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	return n.DB.QueryRowContext(
+		ctx,
+		stmt,
+		preference.UserID,
+		preference.Type,
+		pq.Array(preference.Channels),
+	).Scan(&preference.UpdatedAt)
+}
+
+func (n *notificationImplementation) GetQuietHours(userID string) (models.QuietHours, error) {
+	stmt := `
+    SELECT
+        user_id,
+        start,
+        "end",
+        timezone,
+        updated_at
+    FROM notification_quiet_hours
+    WHERE user_id = $1;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	quietHours := models.QuietHours{}
+	err := n.DB.QueryRowContext(ctx, stmt, userID).Scan(
+		&quietHours.UserID,
+		&quietHours.Start,
+		&quietHours.End,
+		&quietHours.Timezone,
+		&quietHours.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.QuietHours{}, repository.ErrRecordNotFound
+		}
+		return models.QuietHours{}, err
+	}
+
+	return quietHours, nil
+}
+
+func (n *notificationImplementation) SetQuietHours(quietHours *models.QuietHours) error {
+	stmt := `
+    INSERT INTO notification_quiet_hours (
+        user_id,
+        start,
+        "end",
+        timezone
+    ) VALUES (
+        $1, $2, $3, $4
+    )
+    ON CONFLICT (user_id) DO UPDATE
+    SET
+        start = EXCLUDED.start,
+        "end" = EXCLUDED."end",
+        timezone = EXCLUDED.timezone,
+        updated_at = now()
+    RETURNING updated_at;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	return n.DB.QueryRowContext(
+		ctx,
+		stmt,
+		quietHours.UserID,
+		quietHours.Start,
+		quietHours.End,
+		quietHours.Timezone,
+	).Scan(&quietHours.UpdatedAt)
+}
+
+func (n *notificationImplementation) DeleteQuietHours(userID string) error {
+	stmt := `
+    DELETE FROM notification_quiet_hours
+    WHERE user_id = $1;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	result, err := n.DB.ExecContext(ctx, stmt, userID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (n *notificationImplementation) AddPushDevice(device *models.PushDevice) error {
+	stmt := `
+    INSERT INTO push_devices (
+        token,
+        user_id,
+        platform
+    ) VALUES (
+        $1, $2, $3
+    )
+    ON CONFLICT (token) DO UPDATE
+    SET
+        user_id = EXCLUDED.user_id,
+        platform = EXCLUDED.platform,
+        created_at = now()
+    RETURNING created_at;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	return n.DB.QueryRowContext(
+		ctx,
+		stmt,
+		device.Token,
+		device.UserID,
+		device.Platform,
+	).Scan(&device.CreatedAt)
+}
+
+func (n *notificationImplementation) GetPushDevices(userID string) ([]models.PushDevice, error) {
+	stmt := `
+    SELECT
+        token,
+        user_id,
+        platform,
+        created_at
+    FROM push_devices
+    WHERE user_id = $1
+    ORDER BY created_at;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	rows, err := n.DB.QueryContext(ctx, stmt, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	devices := []models.PushDevice{}
+	for rows.Next() {
+		device := models.PushDevice{}
+		err := rows.Scan(
+			&device.Token,
+			&device.UserID,
+			&device.Platform,
+			&device.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		devices = append(devices, device)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return devices, nil
+}
+
+func (n *notificationImplementation) DeletePushDevice(userID, token string) error {
+	stmt := `
+    DELETE FROM push_devices
+    WHERE user_id = $1 AND token = $2;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	result, err := n.DB.ExecContext(ctx, stmt, userID, token)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,294 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/repository"
+)
+
+type outboxImplementation struct {
+	DB *sql.DB
+}
+
+func NewOutboxImplementation(db *sql.DB) repository.OutboxRepository {
+	return &outboxImplementation{DB: db}
+}
+
+func (o *outboxImplementation) Enqueue(messages ...models.OutboxMessage) error {
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	return withTx(ctx, o.DB, func(tx *sql.Tx) error {
+		return insertOutboxMessages(ctx, tx, messages)
+	})
+}
+
+func (o *outboxImplementation) Claim(limit int, lease time.Duration) ([]models.OutboxMessage, error) {
+	stmt := `
+    UPDATE outbox
+    SET
+        attempts = attempts + 1,
+        available_at = now() + $2 * INTERVAL '1 second'
+    WHERE id IN (
+        SELECT id
+        FROM outbox
+        WHERE status = $3 AND available_at <= now()
+        ORDER BY available_at
+        LIMIT $1
+        FOR UPDATE SKIP LOCKED
+    )
+    RETURNING
+        id,
+        kind,
+        payload,
+        status,
+        attempts,
+        last_error,
+        available_at,
+        created_at,
+        delivered_at;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	rows, err := o.DB.QueryContext(
+		ctx,
+		stmt,
+		limit,
+		lease.Seconds(),
+		models.OUTBOX_PENDING,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := []models.OutboxMessage{}
+	for rows.Next() {
+		message, err := scanOutboxMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+func (o *outboxImplementation) MarkDelivered(id string) error {
+	stmt := `
+    UPDATE outbox
+    SET
+        status = $2,
+        last_error = '',
+        delivered_at = now()
+    WHERE id = $1;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	result, err := o.DB.ExecContext(ctx, stmt, id, models.OUTBOX_DELIVERED)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return repository.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (o *outboxImplementation) MarkFailed(id, lastError string, retryAt time.Time, dead bool) error {
+	stmt := `
+    UPDATE outbox
+    SET
+        status = CASE WHEN $4 THEN $5 ELSE status END,
+        last_error = $2,
+        available_at = $3
+    WHERE id = $1;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	result, err := o.DB.ExecContext(
+		ctx,
+		stmt,
+		id,
+		lastError,
+		retryAt,
+		dead,
+		models.OUTBOX_DEAD,
+	)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return repository.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (o *outboxImplementation) GetAll(filter models.OutboxFilter) ([]models.OutboxMessage, error) {
+	stmt := `
+    SELECT
+        id,
+        kind,
+        payload,
+        status,
+        attempts,
+        last_error,
+        available_at,
+        created_at,
+        delivered_at
+    FROM outbox
+    WHERE
+        ($1 = '' OR kind = $1) AND
+        ($2 = '' OR status = $2)
+    ORDER BY created_at DESC
+    LIMIT $3 OFFSET $4;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	rows, err := o.DB.QueryContext(
+		ctx,
+		stmt,
+		filter.Kind,
+		filter.Status,
+		filter.Limit,
+		filter.Offset(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := []models.OutboxMessage{}
+	for rows.Next() {
+		message, err := scanOutboxMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+func (o *outboxImplementation) Retry(id string) (models.OutboxMessage, error) {
+	stmt := `
+    UPDATE outbox
+    SET
+        status = $2,
+        attempts = 0,
+        available_at = now()
+    WHERE id = $1 AND status = $3
+    RETURNING
+        id,
+        kind,
+        payload,
+        status,
+        attempts,
+        last_error,
+        available_at,
+        created_at,
+        delivered_at;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	message, err := scanOutboxMessage(o.DB.QueryRowContext(
+		ctx,
+		stmt,
+		id,
+		models.OUTBOX_PENDING,
+		models.OUTBOX_DEAD,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.OutboxMessage{}, repository.ErrOutboxNotDead
+		}
+		return models.OutboxMessage{}, err
+	}
+	return message, nil
+}
+
+// insertOutboxMessages enqueues the messages in the transaction of the
+// change causing them. Messages already in the outbox are skipped, so
+// that enqueuing with stable IDs is idempotent.
+func insertOutboxMessages(ctx context.Context, tx *sql.Tx, messages []models.OutboxMessage) error {
+	stmt := `
+    INSERT INTO outbox (
+        id,
+        kind,
+        payload,
+        status
+    ) VALUES (
+        $1, $2, $3, $4
+    )
+    ON CONFLICT (id) DO NOTHING
+    RETURNING
+        attempts,
+        available_at,
+        created_at;
+    `
+	for i := range messages {
+		if messages[i].ID == "" {
+			messages[i].ID = uuid.NewString()
+		}
+		messages[i].Status = models.OUTBOX_PENDING
+		err := tx.QueryRowContext(
+			ctx,
+			stmt,
+			messages[i].ID,
+			messages[i].Kind,
+			// sent as text, since byte slices are encoded as bytea.
+			string(messages[i].Payload),
+			messages[i].Status,
+		).Scan(
+			&messages[i].Attempts,
+			&messages[i].AvailableAt,
+			&messages[i].CreatedAt,
+		)
+		if err != nil {
+			// the message was enqueued before.
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// scanOutboxMessage scans an outbox row selected with all its columns.
+func scanOutboxMessage(row interface{ Scan(...any) error }) (models.OutboxMessage, error) {
+	message := models.OutboxMessage{}
+	err := row.Scan(
+		&message.ID,
+		&message.Kind,
+		&message.Payload,
+		&message.Status,
+		&message.Attempts,
+		&message.LastError,
+		&message.AvailableAt,
+		&message.CreatedAt,
+		&message.DeliveredAt,
+	)
+	return message, err
+}
@@ -7,6 +7,7 @@ import (
 	// "strings"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 
 	"github.com/lokatalent/backend_go/internal/models"
 	"github.com/lokatalent/backend_go/internal/repository"
@@ -29,28 +30,21 @@ func (p *paymentImplementation) CreateWallet(wallet *models.UserWallet) error {
 	stmt := `
     INSERT INTO wallets (
         id,
-        user_id,
-        credits,
-        debits
+        user_id
     ) VALUES (
-        $1, $2, 0, 0
+        $1, $2
     ) RETURNING
         id,
         user_id,
-        credits,
-        debits,
         created_at,
         updated_at;
     `
 	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
 	defer cancel()
 
-	var walletCredits, walletDebits float64
 	err := p.DB.QueryRowContext(ctx, stmt, wallet.ID, wallet.UserID).Scan(
 		&wallet.ID,
 		&wallet.UserID,
-		&walletCredits,
-		&walletDebits,
 		&wallet.CreatedAt,
 		&wallet.UpdatedAt,
 	)
@@ -58,35 +52,43 @@ func (p *paymentImplementation) CreateWallet(wallet *models.UserWallet) error {
 		return err
 	}
 
-	wallet.Balance = walletCredits - walletDebits
-	wallet.Debits = walletDebits
-
 	return nil
 }
 
+// GetWallet returns a user's wallet with its balance, total debits and
+// provider earnings derived from the ledger.
 func (p *paymentImplementation) GetWallet(userID string) (models.UserWallet, error) {
 	stmt := `
     SELECT
-        id,
-        user_id,
-        credits,
-        debits,
-        created_at,
-        updated_at
-    FROM wallets
-    WHERE user_id = $1
-    LIMIT 1
+        w.id,
+        w.user_id,
+        COALESCE(SUM(-lp.amount) FILTER (WHERE la.type = $2), 0),
+        COALESCE(SUM(lp.amount) FILTER (WHERE la.type = $2 AND lp.amount > 0), 0),
+        COALESCE(SUM(-lp.amount) FILTER (WHERE la.type = $3), 0),
+        w.created_at,
+        w.updated_at
+    FROM wallets w
+    LEFT JOIN ledger_accounts la ON la.user_id = w.user_id
+    LEFT JOIN ledger_postings lp ON lp.account_id = la.id
+    WHERE w.user_id = $1
+    GROUP BY w.id;
     `
 	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
 	defer cancel()
 
-	var walletCredits, walletDebits float64
 	wallet := models.UserWallet{}
-	err := p.DB.QueryRowContext(ctx, stmt, userID).Scan(
+	err := p.DB.QueryRowContext(
+		ctx,
+		stmt,
+		userID,
+		models.LEDGER_ACCOUNT_REQUESTER_WALLET,
+		models.LEDGER_ACCOUNT_PROVIDER_EARNINGS,
+	).Scan(
 		&wallet.ID,
 		&wallet.UserID,
-		&walletCredits,
-		&walletDebits,
+		&wallet.Balance,
+		&wallet.Debits,
+		&wallet.Earnings,
 		&wallet.CreatedAt,
 		&wallet.UpdatedAt,
 	)
@@ -97,132 +99,29 @@ func (p *paymentImplementation) GetWallet(userID string) (models.UserWallet, err
 		return models.UserWallet{}, err
 	}
 
-	wallet.Balance = walletCredits - walletDebits
-	wallet.Debits = walletDebits
-	if wallet.Balance < 0 {
-		return models.UserWallet{}, repository.ErrInvalidWalletBalance
-	}
-
 	return wallet, nil
 }
 
-func (p *paymentImplementation) GetUserDebits(userID string) (float64, error) {
-	stmt := `
-    SELECT debits
-    FROM wallets
-    WHERE user_id = $1
-    LIMIT = 1;
-    `
-	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
-	defer cancel()
-
-	var walletDebits float64
-	err := p.DB.QueryRowContext(ctx, stmt, userID).Scan(&walletDebits)
+// GetUserDebits returns the total amount spent from a user's wallet.
+func (p *paymentImplementation) GetUserDebits(userID string) (models.Money, error) {
+	wallet, err := p.GetWallet(userID)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return 0.0, repository.ErrRecordNotFound
-		}
-		return 0.0, err
+		return models.Money{}, err
 	}
-
-	return walletDebits, nil
-}
-
-func (p *paymentImplementation) UpdateWallet(userID, paymentType string, amount float64) error {
-	var stmt string
-	switch paymentType {
-	case models.PAYMENT_TYPE_CREDIT:
-		stmt = `
-        UPDATE wallets
-        SET
-            credits = credits + $2,
-            debits = debits + $2
-        WHERE user_id = $1;
-        `
-	case models.PAYMENT_TYPE_DEBIT:
-		stmt = `
-        UPDATE wallets
-        SET
-            -- credits = credits - $2,
-            debits = debits + $2
-        WHERE user_id = $1;
-        `
-	case models.PAYMENT_TYPE_REFUND:
-		stmt = `
-        UPDATE wallets
-        SET
-            -- credits = credits + $2,
-            debits = debits - $2
-        WHERE user_id = $1;
-        `
-	default:
-		return errors.New("unknown payment type")
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
-	defer cancel()
-
-	_, err := p.DB.ExecContext(ctx, stmt, userID, amount)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return wallet.Debits, nil
 }
 
 // transactions
 
-func (p *paymentImplementation) CreatePayment(payment *models.Payment) error {
-	if payment.ID == "" {
-		payment.ID = uuid.NewString()
-	}
-	stmt := `
-    INSERT INTO payments (
-        id,
-        type,
-        booking_id,
-        amount,
-        payment_ref,
-        status
-    ) VALUES (
-        $1, $2, $3, $4, $5, $6
-    ) RETURNING
-        id,
-        type,
-        booking_id,
-        amount,
-        payment_ref,
-        status,
-        created_at,
-        updated_at;
-    `
+// CreatePayment inserts a payment and posts entries to the ledger in the
+// same transaction, linking each entry to the payment.
+func (p *paymentImplementation) CreatePayment(payment *models.Payment, entries ...models.LedgerEntry) error {
 	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
 	defer cancel()
 
-	err := p.DB.QueryRowContext(
-		ctx,
-		stmt,
-		payment.ID,
-		payment.Type,
-		payment.BookingID,
-		payment.Amount,
-		payment.PaymentRef,
-		payment.Status,
-	).Scan(
-		&payment.ID,
-		&payment.Type,
-		&payment.BookingID,
-		&payment.Amount,
-		&payment.PaymentRef,
-		&payment.Status,
-		&payment.CreatedAt,
-		&payment.UpdatedAt,
-	)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return withTx(ctx, p.DB, func(tx *sql.Tx) error {
+		return insertPayment(ctx, tx, payment, entries)
+	})
 }
 
 func (p *paymentImplementation) GetPayment(filter models.PaymentFilter) (models.Payment, error) {
@@ -314,6 +213,182 @@ func (p *paymentImplementation) UpdatePaymentStatus(id, status string) (models.P
 	return payment, nil
 }
 
+// TransitionPaymentStatus changes the status of a payment only if its
+// current status is one of from. It returns ErrRecordNotFound when the
+// payment is missing or already left those statuses, which lets callers
+// apply the side effects of a transition exactly once. Entries are posted
+// to the ledger in the same transaction as the status change.
+func (p *paymentImplementation) TransitionPaymentStatus(id string, from []string, to string, entries ...models.LedgerEntry) (models.Payment, error) {
+	stmt := `
+    UPDATE payments
+    SET
+        status = $3,
+        updated_at = now()
+    WHERE id = $1 AND status = ANY($2)
+    RETURNING
+        id,
+        type,
+        booking_id,
+        amount,
+        payment_ref,
+        status,
+        created_at,
+        updated_at;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	payment := models.Payment{}
+	err := withTx(ctx, p.DB, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, stmt, id, pq.Array(from), to).Scan(
+			&payment.ID,
+			&payment.Type,
+			&payment.BookingID,
+			&payment.Amount,
+			&payment.PaymentRef,
+			&payment.Status,
+			&payment.CreatedAt,
+			&payment.UpdatedAt,
+		)
+		if err != nil {
+			return err
+		}
+
+		return postPaymentEntries(ctx, tx, payment.ID, entries)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Payment{}, repository.ErrRecordNotFound
+		}
+		return models.Payment{}, err
+	}
+	return payment, nil
+}
+
+func (p *paymentImplementation) GetUserPayments(userID string) ([]models.Payment, error) {
+	stmt := `
+    SELECT
+        id,
+        type,
+        booking_id,
+        amount,
+        payment_ref,
+        status,
+        created_at,
+        updated_at
+    FROM payments
+    WHERE
+        booking_id IN (
+            SELECT id
+            FROM bookings
+            WHERE requester_id = $1
+        )
+        OR id IN (
+            SELECT e.payment_id
+            FROM ledger_entries e
+            JOIN ledger_postings lp ON lp.entry_id = e.id
+            JOIN ledger_accounts a ON a.id = lp.account_id
+            WHERE a.user_id = $1 AND e.payment_id IS NOT NULL
+        )
+    ORDER BY created_at DESC;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	rows, err := p.DB.QueryContext(ctx, stmt, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	payments := []models.Payment{}
+	for rows.Next() {
+		payment := models.Payment{}
+		err := rows.Scan(
+			&payment.ID,
+			&payment.Type,
+			&payment.BookingID,
+			&payment.Amount,
+			&payment.PaymentRef,
+			&payment.Status,
+			&payment.CreatedAt,
+			&payment.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, payment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return payments, nil
+}
+
+// insertPayment inserts a payment and posts its ledger entries in the
+// transaction.
+func insertPayment(ctx context.Context, tx *sql.Tx, payment *models.Payment, entries []models.LedgerEntry) error {
+	if payment.ID == "" {
+		payment.ID = uuid.NewString()
+	}
+	stmt := `
+    INSERT INTO payments (
+        id,
+        type,
+        booking_id,
+        amount,
+        payment_ref,
+        status
+    ) VALUES (
+        $1, $2, $3, $4, $5, $6
+    ) RETURNING
+        id,
+        type,
+        booking_id,
+        amount,
+        payment_ref,
+        status,
+        created_at,
+        updated_at;
+    `
+	err := tx.QueryRowContext(
+		ctx,
+		stmt,
+		payment.ID,
+		payment.Type,
+		payment.BookingID,
+		payment.Amount,
+		payment.PaymentRef,
+		payment.Status,
+	).Scan(
+		&payment.ID,
+		&payment.Type,
+		&payment.BookingID,
+		&payment.Amount,
+		&payment.PaymentRef,
+		&payment.Status,
+		&payment.CreatedAt,
+		&payment.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return postPaymentEntries(ctx, tx, payment.ID, entries)
+}
+
+func postPaymentEntries(ctx context.Context, tx *sql.Tx, paymentID string, entries []models.LedgerEntry) error {
+	for i := range entries {
+		entries[i].PaymentID.String = paymentID
+		entries[i].PaymentID.Valid = true
+		if err := postLedgerEntry(ctx, tx, &entries[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // paystack transaction utilities
 
 func (p *paymentImplementation) CreateAccessCode(id, paymentID, accessCode string) error {
@@ -0,0 +1,211 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/repository"
+)
+
+type reviewImplementation struct {
+	DB *sql.DB
+}
+
+func NewReviewImplementation(db *sql.DB) repository.ReviewRepository {
+	return &reviewImplementation{DB: db}
+}
+
+func (r *reviewImplementation) Create(review *models.Review) error {
+	if review.ID == "" {
+		review.ID = uuid.NewString()
+	}
+	stmt := `
+    INSERT INTO reviews (
+        id,
+        booking_id,
+        reviewer_id,
+        reviewee_id,
+        reviewer_role,
+        rating,
+        comment
+    ) VALUES (
+        $1, $2, $3, $4, $5, $6, $7
+    ) RETURNING created_at, updated_at;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	err := r.DB.QueryRowContext(
+		ctx,
+		stmt,
+		review.ID,
+		review.BookingID,
+		review.ReviewerID,
+		review.RevieweeID,
+		review.ReviewerRole,
+		review.Rating,
+		review.Comment,
+	).Scan(&review.CreatedAt, &review.UpdatedAt)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), duplicateReview):
+			return repository.ErrDuplicateReview
+		default:
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *reviewImplementation) GetForBooking(bookingID string) ([]models.Review, error) {
+	stmt := `
+    SELECT
+        id,
+        booking_id,
+        reviewer_id,
+        reviewee_id,
+        reviewer_role,
+        rating,
+        comment,
+        created_at,
+        updated_at
+    FROM reviews
+    WHERE booking_id = $1
+    ORDER BY created_at;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	rows, err := r.DB.QueryContext(ctx, stmt, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanReviews(rows)
+}
+
+func (r *reviewImplementation) GetForUser(filter models.ReviewFilter) ([]models.Review, error) {
+	stmt := `
+    SELECT
+        id,
+        booking_id,
+        reviewer_id,
+        reviewee_id,
+        reviewer_role,
+        rating,
+        comment,
+        created_at,
+        updated_at
+    FROM reviews
+    WHERE
+        reviewee_id = $1 AND
+        ($2 = '' OR reviewer_role = $2)
+    ORDER BY created_at DESC
+    LIMIT $3 OFFSET $4;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	rows, err := r.DB.QueryContext(
+		ctx,
+		stmt,
+		filter.RevieweeID,
+		filter.ReviewerRole,
+		filter.Limit,
+		filter.Offset(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanReviews(rows)
+}
+
+func (r *reviewImplementation) GetSummary(userID string) (models.RatingSummary, error) {
+	summaries, err := r.GetSummaries([]string{userID})
+	if err != nil {
+		return models.RatingSummary{}, err
+	}
+	return summaries[userID], nil
+}
+
+func (r *reviewImplementation) GetSummaries(userIDs []string) (map[string]models.RatingSummary, error) {
+	stmt := `
+    SELECT
+        reviewee_id,
+        AVG(rating)::FLOAT8,
+        COUNT(*)
+    FROM reviews
+    WHERE
+        reviewee_id = ANY($1::UUID[]) AND
+        reviewer_role = $2
+    GROUP BY reviewee_id;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	summaries := map[string]models.RatingSummary{}
+	if len(userIDs) == 0 {
+		return summaries, nil
+	}
+
+	rows, err := r.DB.QueryContext(
+		ctx,
+		stmt,
+		pq.Array(userIDs),
+		models.REVIEWER_REQUESTER,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID string
+		summary := models.RatingSummary{}
+		err := rows.Scan(&userID, &summary.Rating, &summary.ReviewCount)
+		if err != nil {
+			return nil, err
+		}
+		summaries[userID] = summary
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return summaries, nil
+}
+
+func scanReviews(rows *sql.Rows) ([]models.Review, error) {
+	reviews := []models.Review{}
+	for rows.Next() {
+		review := models.Review{}
+		err := rows.Scan(
+			&review.ID,
+			&review.BookingID,
+			&review.ReviewerID,
+			&review.RevieweeID,
+			&review.ReviewerRole,
+			&review.Rating,
+			&review.Comment,
+			&review.CreatedAt,
+			&review.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reviews, nil
+}
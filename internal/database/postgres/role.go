@@ -0,0 +1,269 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/repository"
+)
+
+type roleImplementation struct {
+	DB *sql.DB
+}
+
+func NewRoleImplementation(db *sql.DB) repository.RoleRepository {
+	return &roleImplementation{DB: db}
+}
+
+func (r *roleImplementation) Create(role *models.Role) error {
+	stmt := `
+    INSERT INTO roles (
+        name,
+        description,
+        requires_two_factor
+    ) VALUES (
+        $1, $2, $3
+    ) RETURNING builtin, created_at, updated_at;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	err := withTx(ctx, r.DB, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(
+			ctx,
+			stmt,
+			role.Name,
+			role.Description,
+			role.RequiresTwoFactor,
+		).Scan(&role.Builtin, &role.CreatedAt, &role.UpdatedAt)
+		if err != nil {
+			return err
+		}
+		return setRolePermissions(ctx, tx, role.Name, role.Permissions)
+	})
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), duplicateRole):
+			return repository.ErrDuplicateRole
+		case strings.Contains(err.Error(), unknownPermission):
+			return repository.ErrUnknownPermission
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *roleImplementation) Get(name string) (models.Role, error) {
+	stmt := `
+    SELECT
+        r.name,
+        r.description,
+        r.requires_two_factor,
+        r.builtin,
+        COALESCE(
+            ARRAY_AGG(rp.permission ORDER BY rp.permission)
+                FILTER (WHERE rp.permission IS NOT NULL),
+            '{}'
+        ),
+        r.created_at,
+        r.updated_at
+    FROM roles r
+    LEFT JOIN role_permissions rp ON rp.role = r.name
+    WHERE r.name = $1
+    GROUP BY r.name;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	role := models.Role{}
+	err := r.DB.QueryRowContext(ctx, stmt, name).Scan(
+		&role.Name,
+		&role.Description,
+		&role.RequiresTwoFactor,
+		&role.Builtin,
+		pq.Array(&role.Permissions),
+		&role.CreatedAt,
+		&role.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Role{}, repository.ErrRecordNotFound
+		}
+		return models.Role{}, err
+	}
+
+	return role, nil
+}
+
+func (r *roleImplementation) GetAll() ([]models.Role, error) {
+	stmt := `
+    SELECT
+        r.name,
+        r.description,
+        r.requires_two_factor,
+        r.builtin,
+        COALESCE(
+            ARRAY_AGG(rp.permission ORDER BY rp.permission)
+                FILTER (WHERE rp.permission IS NOT NULL),
+            '{}'
+        ),
+        r.created_at,
+        r.updated_at
+    FROM roles r
+    LEFT JOIN role_permissions rp ON rp.role = r.name
+    GROUP BY r.name
+    ORDER BY r.name;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	rows, err := r.DB.QueryContext(ctx, stmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []models.Role{}
+	for rows.Next() {
+		role := models.Role{}
+		err := rows.Scan(
+			&role.Name,
+			&role.Description,
+			&role.RequiresTwoFactor,
+			&role.Builtin,
+			pq.Array(&role.Permissions),
+			&role.CreatedAt,
+			&role.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+func (r *roleImplementation) Update(role *models.Role) error {
+	stmt := `
+    UPDATE roles
+    SET
+        description = $2,
+        requires_two_factor = $3,
+        updated_at = now()
+    WHERE name = $1
+    RETURNING builtin, created_at, updated_at;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	err := withTx(ctx, r.DB, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(
+			ctx,
+			stmt,
+			role.Name,
+			role.Description,
+			role.RequiresTwoFactor,
+		).Scan(&role.Builtin, &role.CreatedAt, &role.UpdatedAt)
+		if err != nil {
+			return err
+		}
+		return setRolePermissions(ctx, tx, role.Name, role.Permissions)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return repository.ErrRecordNotFound
+		case strings.Contains(err.Error(), unknownPermission):
+			return repository.ErrUnknownPermission
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *roleImplementation) Delete(name string) error {
+	stmt := `
+    DELETE FROM roles
+    WHERE name = $1 AND builtin = false;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	result, err := r.DB.ExecContext(ctx, stmt, name)
+	if err != nil {
+		if strings.Contains(err.Error(), roleInUse) {
+			return repository.ErrRoleInUse
+		}
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *roleImplementation) GetPermissions() ([]models.Permission, error) {
+	stmt := `
+    SELECT name, description
+    FROM permissions
+    ORDER BY name;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	rows, err := r.DB.QueryContext(ctx, stmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	permissions := []models.Permission{}
+	for rows.Next() {
+		permission := models.Permission{}
+		err := rows.Scan(&permission.Name, &permission.Description)
+		if err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, permission)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+func setRolePermissions(ctx context.Context, tx *sql.Tx, role string, permissions []string) error {
+	deleteStmt := `
+    DELETE FROM role_permissions
+    WHERE role = $1;
+    `
+	insertStmt := `
+    INSERT INTO role_permissions (role, permission)
+    SELECT $1, UNNEST($2::TEXT[])
+    ON CONFLICT DO NOTHING;
+    `
+	if _, err := tx.ExecContext(ctx, deleteStmt, role); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx, insertStmt, role, pq.Array(permissions))
+	return err
+}
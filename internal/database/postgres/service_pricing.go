@@ -3,7 +3,7 @@ package postgres
 import (
 	"context"
 	"database/sql"
-	// "errors"
+	"errors"
 	// "strings"
 
 	"github.com/google/uuid"
@@ -85,6 +85,9 @@ func (s *servicePricingImplementation) GetServicePricing(serviceType string) (mo
 		&servicePricing.UpdatedAt,
 	)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ServicePricing{}, repository.ErrRecordNotFound
+		}
 		return models.ServicePricing{}, err
 	}
 
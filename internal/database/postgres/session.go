@@ -0,0 +1,299 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/repository"
+)
+
+type sessionImplementation struct {
+	DB *sql.DB
+}
+
+func NewSessionImplementation(db *sql.DB) repository.SessionRepository {
+	return &sessionImplementation{DB: db}
+}
+
+func (s *sessionImplementation) Create(session *models.Session, token *models.RefreshToken) error {
+	if session.ID == "" {
+		session.ID = uuid.NewString()
+	}
+	stmt := `
+    INSERT INTO sessions (
+        id,
+        user_id,
+        device,
+        user_agent,
+        ip_address,
+        expires_at
+    ) VALUES (
+        $1, $2, $3, $4, $5, $6
+    ) RETURNING last_used_at, created_at;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	return withTx(ctx, s.DB, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(
+			ctx,
+			stmt,
+			session.ID,
+			session.UserID,
+			session.Device,
+			session.UserAgent,
+			session.IPAddress,
+			token.ExpiresAt,
+		).Scan(&session.LastUsedAt, &session.CreatedAt)
+		if err != nil {
+			return err
+		}
+		session.ExpiresAt = token.ExpiresAt
+
+		token.SessionID = session.ID
+		return insertRefreshToken(ctx, tx, token)
+	})
+}
+
+func (s *sessionImplementation) GetByID(id string) (models.Session, error) {
+	stmt := `
+    SELECT
+        id,
+        user_id,
+        device,
+        user_agent,
+        ip_address,
+        expires_at,
+        revoked_at,
+        last_used_at,
+        created_at
+    FROM sessions
+    WHERE id = $1;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	session := models.Session{}
+	err := s.DB.QueryRowContext(ctx, stmt, id).Scan(
+		&session.ID,
+		&session.UserID,
+		&session.Device,
+		&session.UserAgent,
+		&session.IPAddress,
+		&session.ExpiresAt,
+		&session.RevokedAt,
+		&session.LastUsedAt,
+		&session.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Session{}, repository.ErrRecordNotFound
+		}
+		return models.Session{}, err
+	}
+
+	return session, nil
+}
+
+func (s *sessionImplementation) GetActive(userID string) ([]models.Session, error) {
+	stmt := `
+    SELECT
+        id,
+        user_id,
+        device,
+        user_agent,
+        ip_address,
+        expires_at,
+        revoked_at,
+        last_used_at,
+        created_at
+    FROM sessions
+    WHERE
+        user_id = $1 AND
+        revoked_at IS NULL AND
+        expires_at > now()
+    ORDER BY last_used_at DESC;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	rows, err := s.DB.QueryContext(ctx, stmt, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []models.Session{}
+	for rows.Next() {
+		session := models.Session{}
+		err := rows.Scan(
+			&session.ID,
+			&session.UserID,
+			&session.Device,
+			&session.UserAgent,
+			&session.IPAddress,
+			&session.ExpiresAt,
+			&session.RevokedAt,
+			&session.LastUsedAt,
+			&session.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+func (s *sessionImplementation) GetRefreshToken(tokenHash string) (models.RefreshToken, error) {
+	stmt := `
+    SELECT
+        id,
+        session_id,
+        token_hash,
+        expires_at,
+        used_at,
+        created_at
+    FROM refresh_tokens
+    WHERE token_hash = $1;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	token := models.RefreshToken{}
+	err := s.DB.QueryRowContext(ctx, stmt, tokenHash).Scan(
+		&token.ID,
+		&token.SessionID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.UsedAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.RefreshToken{}, repository.ErrRecordNotFound
+		}
+		return models.RefreshToken{}, err
+	}
+
+	return token, nil
+}
+
+func (s *sessionImplementation) Rotate(sessionID, previousID string, next *models.RefreshToken) error {
+	stmt := `
+    UPDATE refresh_tokens
+    SET used_at = now()
+    WHERE
+        session_id = $1 AND
+        ($2 = '' OR id::TEXT = $2) AND
+        used_at IS NULL;
+    `
+	sessionStmt := `
+    UPDATE sessions
+    SET
+        expires_at = $2,
+        last_used_at = now()
+    WHERE id = $1 AND revoked_at IS NULL;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	return withTx(ctx, s.DB, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, stmt, sessionID, previousID)
+		if err != nil {
+			return err
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		// a concurrent refresh already used the previous token.
+		if previousID != "" && rows == 0 {
+			return repository.ErrRefreshTokenReused
+		}
+
+		result, err = tx.ExecContext(ctx, sessionStmt, sessionID, next.ExpiresAt)
+		if err != nil {
+			return err
+		}
+		rows, err = result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return repository.ErrRecordNotFound
+		}
+
+		next.SessionID = sessionID
+		return insertRefreshToken(ctx, tx, next)
+	})
+}
+
+func (s *sessionImplementation) Revoke(id, userID string) error {
+	stmt := `
+    UPDATE sessions
+    SET revoked_at = now()
+    WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	result, err := s.DB.ExecContext(ctx, stmt, id, userID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (s *sessionImplementation) RevokeAll(userID string) error {
+	stmt := `
+    UPDATE sessions
+    SET revoked_at = now()
+    WHERE user_id = $1 AND revoked_at IS NULL;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	_, err := s.DB.ExecContext(ctx, stmt, userID)
+	return err
+}
+
+func insertRefreshToken(ctx context.Context, tx *sql.Tx, token *models.RefreshToken) error {
+	if token.ID == "" {
+		token.ID = uuid.NewString()
+	}
+	stmt := `
+    INSERT INTO refresh_tokens (
+        id,
+        session_id,
+        token_hash,
+        expires_at
+    ) VALUES (
+        $1, $2, $3, $4
+    ) RETURNING created_at;
+    `
+	return tx.QueryRowContext(
+		ctx,
+		stmt,
+		token.ID,
+		token.SessionID,
+		token.TokenHash,
+		token.ExpiresAt,
+	).Scan(&token.CreatedAt)
+}
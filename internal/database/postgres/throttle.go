@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/repository"
+)
+
+type throttleImplementation struct {
+	DB *sql.DB
+}
+
+func NewThrottleImplementation(db *sql.DB) repository.ThrottleRepository {
+	return &throttleImplementation{DB: db}
+}
+
+func (t *throttleImplementation) Get(key string) (models.AuthThrottle, error) {
+	stmt := `
+    SELECT
+        key,
+        failures,
+        lockouts,
+        locked_until,
+        updated_at
+    FROM auth_throttles
+    WHERE key = $1;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	throttle := models.AuthThrottle{}
+	err := t.DB.QueryRowContext(ctx, stmt, key).Scan(
+		&throttle.Key,
+		&throttle.Failures,
+		&throttle.Lockouts,
+		&throttle.LockedUntil,
+		&throttle.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.AuthThrottle{}, repository.ErrRecordNotFound
+		}
+		return models.AuthThrottle{}, err
+	}
+
+	return throttle, nil
+}
+
+func (t *throttleImplementation) RecordFailure(key string, window time.Duration) (models.AuthThrottle, error) {
+	stmt := `
+    INSERT INTO auth_throttles (
+        key,
+        failures
+    ) VALUES (
+        $1, 1
+    ) ON CONFLICT (key) DO UPDATE
+    SET
+        failures = CASE
+            WHEN auth_throttles.updated_at < now() - $2 * INTERVAL '1 second' THEN 1
+            ELSE auth_throttles.failures + 1
+        END,
+        updated_at = now()
+    RETURNING
+        key,
+        failures,
+        lockouts,
+        locked_until,
+        updated_at;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	throttle := models.AuthThrottle{}
+	err := t.DB.QueryRowContext(ctx, stmt, key, window.Seconds()).Scan(
+		&throttle.Key,
+		&throttle.Failures,
+		&throttle.Lockouts,
+		&throttle.LockedUntil,
+		&throttle.UpdatedAt,
+	)
+	if err != nil {
+		return models.AuthThrottle{}, err
+	}
+
+	return throttle, nil
+}
+
+func (t *throttleImplementation) Lock(key string, until time.Time, lockouts int) error {
+	stmt := `
+    UPDATE auth_throttles
+    SET
+        failures = 0,
+        lockouts = $3,
+        locked_until = $2,
+        updated_at = now()
+    WHERE key = $1;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	_, err := t.DB.ExecContext(ctx, stmt, key, until, lockouts)
+	return err
+}
+
+func (t *throttleImplementation) Reset(key string) error {
+	stmt := `
+    DELETE FROM auth_throttles
+    WHERE key = $1;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	_, err := t.DB.ExecContext(ctx, stmt, key)
+	return err
+}
@@ -0,0 +1,21 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+)
+
+// withTx runs fn inside a database transaction, committing when fn
+// returns nil and rolling back otherwise.
+func withTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
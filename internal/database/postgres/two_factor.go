@@ -0,0 +1,239 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/repository"
+)
+
+type twoFactorImplementation struct {
+	DB *sql.DB
+}
+
+func NewTwoFactorImplementation(db *sql.DB) repository.TwoFactorRepository {
+	return &twoFactorImplementation{DB: db}
+}
+
+func (t *twoFactorImplementation) Get(userID string) (models.TwoFactor, error) {
+	stmt := `
+    SELECT
+        user_id,
+        secret,
+        enabled,
+        last_used_step,
+        confirmed_at,
+        created_at,
+        updated_at
+    FROM two_factor
+    WHERE user_id = $1;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	twoFactor := models.TwoFactor{}
+	err := t.DB.QueryRowContext(ctx, stmt, userID).Scan(
+		&twoFactor.UserID,
+		&twoFactor.Secret,
+		&twoFactor.Enabled,
+		&twoFactor.LastUsedStep,
+		&twoFactor.ConfirmedAt,
+		&twoFactor.CreatedAt,
+		&twoFactor.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.TwoFactor{}, repository.ErrRecordNotFound
+		}
+		return models.TwoFactor{}, err
+	}
+
+	return twoFactor, nil
+}
+
+func (t *twoFactorImplementation) Enroll(twoFactor *models.TwoFactor) error {
+	stmt := `
+    INSERT INTO two_factor (
+        user_id,
+        secret
+    ) VALUES (
+        $1, $2
+    ) ON CONFLICT (user_id) DO UPDATE
+    SET
+        secret = EXCLUDED.secret,
+        last_used_step = 0,
+        updated_at = now()
+    WHERE two_factor.enabled = false
+    RETURNING
+        enabled,
+        last_used_step,
+        confirmed_at,
+        created_at,
+        updated_at;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	err := t.DB.QueryRowContext(
+		ctx,
+		stmt,
+		twoFactor.UserID,
+		twoFactor.Secret,
+	).Scan(
+		&twoFactor.Enabled,
+		&twoFactor.LastUsedStep,
+		&twoFactor.ConfirmedAt,
+		&twoFactor.CreatedAt,
+		&twoFactor.UpdatedAt,
+	)
+	if err != nil {
+		// an enabled enrollment is never replaced
+		if errors.Is(err, sql.ErrNoRows) {
+			return repository.ErrTwoFactorEnabled
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (t *twoFactorImplementation) Enable(userID string, step int64, recoveryCodeHashes []string) error {
+	stmt := `
+    UPDATE two_factor
+    SET
+        enabled = true,
+        last_used_step = $2,
+        confirmed_at = now(),
+        updated_at = now()
+    WHERE user_id = $1 AND enabled = false AND last_used_step < $2;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	return withTx(ctx, t.DB, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, stmt, userID, step)
+		if err != nil {
+			return err
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return repository.ErrRecordNotFound
+		}
+
+		return replaceRecoveryCodes(ctx, tx, userID, recoveryCodeHashes)
+	})
+}
+
+func (t *twoFactorImplementation) Delete(userID string) error {
+	stmt := `
+    DELETE FROM two_factor
+    WHERE user_id = $1;
+    `
+	codesStmt := `
+    DELETE FROM recovery_codes
+    WHERE user_id = $1;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	return withTx(ctx, t.DB, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, codesStmt, userID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, stmt, userID)
+		return err
+	})
+}
+
+func (t *twoFactorImplementation) UseStep(userID string, step int64) error {
+	stmt := `
+    UPDATE two_factor
+    SET
+        last_used_step = $2,
+        updated_at = now()
+    WHERE user_id = $1 AND last_used_step < $2;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	result, err := t.DB.ExecContext(ctx, stmt, userID, step)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (t *twoFactorImplementation) UseRecoveryCode(userID, codeHash string) error {
+	stmt := `
+    UPDATE recovery_codes
+    SET used_at = now()
+    WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	result, err := t.DB.ExecContext(ctx, stmt, userID, codeHash)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (t *twoFactorImplementation) ReplaceRecoveryCodes(userID string, codeHashes []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	return withTx(ctx, t.DB, func(tx *sql.Tx) error {
+		return replaceRecoveryCodes(ctx, tx, userID, codeHashes)
+	})
+}
+
+func replaceRecoveryCodes(ctx context.Context, tx *sql.Tx, userID string, codeHashes []string) error {
+	deleteStmt := `
+    DELETE FROM recovery_codes
+    WHERE user_id = $1;
+    `
+	insertStmt := `
+    INSERT INTO recovery_codes (
+        id,
+        user_id,
+        code_hash
+    ) VALUES (
+        $1, $2, $3
+    );
+    `
+	if _, err := tx.ExecContext(ctx, deleteStmt, userID); err != nil {
+		return err
+	}
+	for _, codeHash := range codeHashes {
+		_, err := tx.ExecContext(ctx, insertStmt, uuid.NewString(), userID, codeHash)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,253 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/repository"
+)
+
+func (u *userImplementation) UpdateServiceAvailability(userID, serviceType string, availability models.Availability) (models.UserService, error) {
+	stmt := `
+    UPDATE services
+    SET
+        availability = $3,
+        updated_at = now()
+    WHERE user_id = $1 AND service_type = $2
+    RETURNING
+        id,
+        user_id,
+        service_type,
+        service_desc,
+        rate_per_hour,
+        experience_years,
+        availability,
+        address,
+        location,
+        created_at,
+        updated_at
+    `
+
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	service := models.UserService{}
+	err := u.DB.QueryRowContext(
+		ctx,
+		stmt,
+		userID,
+		serviceType,
+		availability,
+	).Scan(
+		&service.ID,
+		&service.UserID,
+		&service.ServiceType,
+		&service.ServiceDesc,
+		&service.RatePerHour,
+		&service.ExperienceYears,
+		&service.Availability,
+		&service.Address,
+		&service.Location,
+		&service.CreatedAt,
+		&service.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.UserService{}, repository.ErrRecordNotFound
+		}
+		return models.UserService{}, err
+	}
+
+	return service, nil
+}
+
+func (u *userImplementation) CreateAvailabilityException(exception *models.AvailabilityException) error {
+	if exception.ID == "" {
+		exception.ID = uuid.NewString()
+	}
+	stmt := `
+    INSERT INTO availability_exceptions (
+        id,
+        user_id,
+        kind,
+        starts_at,
+        ends_at,
+        reason
+    ) VALUES (
+        $1, $2, $3, $4, $5, $6
+    ) RETURNING created_at, updated_at;
+    `
+
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	return u.DB.QueryRowContext(
+		ctx,
+		stmt,
+		exception.ID,
+		exception.UserID,
+		exception.Kind,
+		exception.StartsAt,
+		exception.EndsAt,
+		exception.Reason,
+	).Scan(&exception.CreatedAt, &exception.UpdatedAt)
+}
+
+func (u *userImplementation) GetAvailabilityException(id, userID string) (models.AvailabilityException, error) {
+	stmt := `
+    SELECT
+        id,
+        user_id,
+        kind,
+        starts_at,
+        ends_at,
+        reason,
+        created_at,
+        updated_at
+    FROM availability_exceptions
+    WHERE id = $1 AND user_id = $2;
+    `
+
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	exception := models.AvailabilityException{}
+	err := u.DB.QueryRowContext(ctx, stmt, id, userID).Scan(
+		&exception.ID,
+		&exception.UserID,
+		&exception.Kind,
+		&exception.StartsAt,
+		&exception.EndsAt,
+		&exception.Reason,
+		&exception.CreatedAt,
+		&exception.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.AvailabilityException{}, repository.ErrRecordNotFound
+		}
+		return models.AvailabilityException{}, err
+	}
+
+	return exception, nil
+}
+
+// GetAvailabilityExceptions returns a user's exceptions overlapping
+// [from, to), earliest first.
+func (u *userImplementation) GetAvailabilityExceptions(userID string, from, to time.Time) ([]models.AvailabilityException, error) {
+	stmt := `
+    SELECT
+        id,
+        user_id,
+        kind,
+        starts_at,
+        ends_at,
+        reason,
+        created_at,
+        updated_at
+    FROM availability_exceptions
+    WHERE
+        user_id = $1 AND
+        starts_at < $3 AND
+        ends_at > $2
+    ORDER BY starts_at;
+    `
+
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	rows, err := u.DB.QueryContext(ctx, stmt, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	exceptions := []models.AvailabilityException{}
+	for rows.Next() {
+		exception := models.AvailabilityException{}
+		err := rows.Scan(
+			&exception.ID,
+			&exception.UserID,
+			&exception.Kind,
+			&exception.StartsAt,
+			&exception.EndsAt,
+			&exception.Reason,
+			&exception.CreatedAt,
+			&exception.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		exceptions = append(exceptions, exception)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return exceptions, nil
+}
+
+func (u *userImplementation) UpdateAvailabilityException(exception *models.AvailabilityException) error {
+	stmt := `
+    UPDATE availability_exceptions
+    SET
+        kind = $3,
+        starts_at = $4,
+        ends_at = $5,
+        reason = $6,
+        updated_at = now()
+    WHERE id = $1 AND user_id = $2
+    RETURNING created_at, updated_at;
+    `
+
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	err := u.DB.QueryRowContext(
+		ctx,
+		stmt,
+		exception.ID,
+		exception.UserID,
+		exception.Kind,
+		exception.StartsAt,
+		exception.EndsAt,
+		exception.Reason,
+	).Scan(&exception.CreatedAt, &exception.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return repository.ErrRecordNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (u *userImplementation) DeleteAvailabilityException(id, userID string) error {
+	stmt := `
+    DELETE FROM availability_exceptions
+    WHERE id = $1 AND user_id = $2;
+    `
+
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	result, err := u.DB.ExecContext(ctx, stmt, id, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
+}
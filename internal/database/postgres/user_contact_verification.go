@@ -28,6 +28,7 @@ func (u *userImplementation) CreateVerificationCode(verCode *models.UserVerifica
         user_id,
         code,
         contact_type,
+        attempts,
         created_at,
         expires_at;
     `
@@ -47,6 +48,7 @@ func (u *userImplementation) CreateVerificationCode(verCode *models.UserVerifica
 		&verCode.UserID,
 		&verCode.Code,
 		&verCode.ContactType,
+		&verCode.Attempts,
 		&verCode.CreatedAt,
 		&verCode.ExpiresAt,
 	)
@@ -64,6 +66,7 @@ func (u *userImplementation) GetVerificationCode(userID, contactType string) (mo
         user_id,
         code,
         contact_type,
+        attempts,
         created_at,
         expires_at
     FROM contact_verifications
@@ -84,6 +87,7 @@ func (u *userImplementation) GetVerificationCode(userID, contactType string) (mo
 		&newCode.UserID,
 		&newCode.Code,
 		&newCode.ContactType,
+		&newCode.Attempts,
 		&newCode.CreatedAt,
 		&newCode.ExpiresAt,
 	)
@@ -113,3 +117,38 @@ func (u *userImplementation) DeleteVerificationCode(userID, contactType string)
 
 	return nil
 }
+
+func (u *userImplementation) IncrementVerificationAttempts(id string) error {
+	stmt := `
+    UPDATE contact_verifications
+    SET attempts = attempts + 1
+    WHERE id = $1;
+    `
+
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	_, err := u.DB.ExecContext(ctx, stmt, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (u *userImplementation) DeleteExpiredVerificationCodes() (int64, error) {
+	stmt := `
+    DELETE FROM contact_verifications
+        WHERE expires_at < now()
+    `
+
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	result, err := u.DB.ExecContext(ctx, stmt)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,314 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/repository"
+)
+
+// statuses of bookings that have run their course.
+var finishedBookingStatuses = []string{
+	models.BOOKING_COMPLETED,
+	models.BOOKING_CANCELED,
+}
+
+func (u *userImplementation) ScheduleDeletion(deletion *models.AccountDeletion) error {
+	stmt := `
+    INSERT INTO account_deletions (
+        user_id,
+        scheduled_for
+    ) VALUES (
+        $1, $2
+    )
+    ON CONFLICT (user_id) DO UPDATE
+    SET
+        requested_at = now(),
+        scheduled_for = EXCLUDED.scheduled_for
+    WHERE account_deletions.completed_at IS NULL
+    RETURNING requested_at;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	err := u.DB.QueryRowContext(
+		ctx,
+		stmt,
+		deletion.UserID,
+		deletion.ScheduledFor,
+	).Scan(&deletion.RequestedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return repository.ErrRecordNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (u *userImplementation) GetDeletion(userID string) (models.AccountDeletion, error) {
+	stmt := `
+    SELECT
+        user_id,
+        requested_at,
+        scheduled_for,
+        completed_at
+    FROM account_deletions
+    WHERE user_id = $1;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	deletion := models.AccountDeletion{}
+	err := u.DB.QueryRowContext(ctx, stmt, userID).Scan(
+		&deletion.UserID,
+		&deletion.RequestedAt,
+		&deletion.ScheduledFor,
+		&deletion.CompletedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.AccountDeletion{}, repository.ErrRecordNotFound
+		}
+		return models.AccountDeletion{}, err
+	}
+
+	return deletion, nil
+}
+
+func (u *userImplementation) CancelDeletion(userID string) error {
+	stmt := `
+    DELETE FROM account_deletions
+    WHERE user_id = $1 AND completed_at IS NULL;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	result, err := u.DB.ExecContext(ctx, stmt, userID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (u *userImplementation) GetDueDeletions(limit int) ([]models.AccountDeletion, error) {
+	stmt := `
+    SELECT
+        user_id,
+        requested_at,
+        scheduled_for,
+        completed_at
+    FROM account_deletions
+    WHERE completed_at IS NULL AND scheduled_for <= now()
+    ORDER BY scheduled_for
+    LIMIT $1;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	rows, err := u.DB.QueryContext(ctx, stmt, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deletions := []models.AccountDeletion{}
+	for rows.Next() {
+		deletion := models.AccountDeletion{}
+		err := rows.Scan(
+			&deletion.UserID,
+			&deletion.RequestedAt,
+			&deletion.ScheduledFor,
+			&deletion.CompletedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		deletions = append(deletions, deletion)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deletions, nil
+}
+
+func (u *userImplementation) RescheduleDeletion(userID string, scheduledFor time.Time) error {
+	stmt := `
+    UPDATE account_deletions
+    SET scheduled_for = $2
+    WHERE user_id = $1 AND completed_at IS NULL;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	result, err := u.DB.ExecContext(ctx, stmt, userID, scheduledFor)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (u *userImplementation) HasActiveBookings(userID string) (bool, error) {
+	stmt := `
+    SELECT EXISTS (
+        SELECT 1
+        FROM bookings
+        WHERE
+            (requester_id = $1 OR provider_id = $1)
+            AND status <> ALL($2)
+    );
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	var active bool
+	err := u.DB.QueryRowContext(
+		ctx,
+		stmt,
+		userID,
+		pq.Array(finishedBookingStatuses),
+	).Scan(&active)
+	if err != nil {
+		return false, err
+	}
+	return active, nil
+}
+
+func (u *userImplementation) Anonymize(userID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	return withTx(ctx, u.DB, func(tx *sql.Tx) error {
+		// lock the user so no booking starts while they're anonymized
+		stmt := `
+        SELECT EXISTS (
+            SELECT 1
+            FROM bookings
+            WHERE
+                (requester_id = $1 OR provider_id = $1)
+                AND status <> ALL($2)
+        )
+        FROM users
+        WHERE id = $1
+        FOR UPDATE;
+        `
+		var active bool
+		err := tx.QueryRowContext(
+			ctx,
+			stmt,
+			userID,
+			pq.Array(finishedBookingStatuses),
+		).Scan(&active)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return repository.ErrRecordNotFound
+			}
+			return err
+		}
+		if active {
+			return repository.ErrActiveBookings
+		}
+
+		stmt = `
+        UPDATE users
+        SET
+            first_name = 'Deleted',
+            last_name = 'User',
+            email = '',
+            phone_num = '',
+            password = '',
+            gender = '',
+            date_of_birth = '0001-01-01',
+            bio = '',
+            address = '',
+            avatar = '',
+            role = $2,
+            service_role = '',
+            is_verified = false,
+            email_verified = false,
+            phone_verified = false,
+            updated_at = now()
+        WHERE id = $1;
+        `
+		_, err = tx.ExecContext(ctx, stmt, userID, models.USER_REGULAR)
+		if err != nil {
+			return err
+		}
+
+		// bookings are kept for the other party's and the platform's
+		// records, without the requester's address.
+		stmt = `
+        UPDATE bookings
+        SET
+            requester_addr = '',
+            requester_location = '{}',
+            updated_at = now()
+        WHERE requester_id = $1;
+        `
+		_, err = tx.ExecContext(ctx, stmt, userID)
+		if err != nil {
+			return err
+		}
+
+		tables := []string{
+			"contact_verifications",
+			"password_resets",
+			"two_factor",
+			"recovery_codes",
+			"user_identities",
+			"sessions",
+			"notifications",
+			"users_bank_info",
+			"users_education",
+			"users_experience",
+			"users_certifications",
+			"service_images",
+			"services",
+			"availability_exceptions",
+			"rejected_bookings",
+			"payment_recipient_codes",
+			"kyc_submissions",
+			"notification_preferences",
+			"notification_quiet_hours",
+			"push_devices",
+		}
+		for _, table := range tables {
+			stmt = `DELETE FROM ` + pq.QuoteIdentifier(table) + ` WHERE user_id = $1;`
+			_, err = tx.ExecContext(ctx, stmt, userID)
+			if err != nil {
+				return err
+			}
+		}
+
+		stmt = `
+        UPDATE account_deletions
+        SET completed_at = now()
+        WHERE user_id = $1;
+        `
+		_, err = tx.ExecContext(ctx, stmt, userID)
+		return err
+	})
+}
@@ -12,9 +12,13 @@ import (
 	"github.com/lokatalent/backend_go/internal/repository"
 )
 
-func (u *userImplementation) CreateEducationInfo(eduInfo *models.UserEducationInfo) error {
+func (u *userImplementation) CreateEducation(education *models.UserEducation) error {
+	if education.ID == "" {
+		education.ID = uuid.NewString()
+	}
 	stmt := `
-    INSERT INTO users_education_info (
+    INSERT INTO users_education (
+        id,
         user_id,
         institute,
         degree,
@@ -22,14 +26,8 @@ func (u *userImplementation) CreateEducationInfo(eduInfo *models.UserEducationIn
         start,
         finish
     ) VALUES (
-        $1, $2, $3, $4, $5, $6
+        $1, $2, $3, $4, $5, $6, $7
     ) RETURNING
-        user_id,
-        institute,
-        degree,
-        discipline,
-        start,
-        finish,
         created_at,
         updated_at;
     `
@@ -40,22 +38,14 @@ func (u *userImplementation) CreateEducationInfo(eduInfo *models.UserEducationIn
 	err := u.DB.QueryRowContext(
 		ctx,
 		stmt,
-		eduInfo.UserID,
-		eduInfo.Institute,
-		eduInfo.Degree,
-		eduInfo.Discipline,
-		eduInfo.Start,
-		eduInfo.Finish,
-	).Scan(
-		&eduInfo.UserID,
-		&eduInfo.Institute,
-		&eduInfo.Degree,
-		&eduInfo.Discipline,
-		&eduInfo.Start,
-		&eduInfo.Finish,
-		&eduInfo.CreatedAt,
-		&eduInfo.UpdatedAt,
-	)
+		education.ID,
+		education.UserID,
+		education.Institute,
+		education.Degree,
+		education.Discipline,
+		education.Start,
+		education.Finish,
+	).Scan(&education.CreatedAt, &education.UpdatedAt)
 	if err != nil {
 		return err
 	}
@@ -63,9 +53,10 @@ func (u *userImplementation) CreateEducationInfo(eduInfo *models.UserEducationIn
 	return nil
 }
 
-func (u *userImplementation) GetEducationInfo(userID string) (models.UserEducationInfo, error) {
+func (u *userImplementation) GetEducation(userID string) ([]models.UserEducation, error) {
 	stmt := `
     SELECT
+        id,
         user_id,
         institute,
         degree,
@@ -74,53 +65,58 @@ func (u *userImplementation) GetEducationInfo(userID string) (models.UserEducati
         finish,
         created_at,
         updated_at
-    FROM users_education_info
-    WHERE user_id = $1;
+    FROM users_education
+    WHERE user_id = $1
+    ORDER BY start DESC;
     `
 
 	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
 	defer cancel()
 
-	newEduInfo := models.UserEducationInfo{}
-	err := u.DB.QueryRowContext(ctx, stmt, userID).Scan(
-		&newEduInfo.UserID,
-		&newEduInfo.Institute,
-		&newEduInfo.Degree,
-		&newEduInfo.Discipline,
-		&newEduInfo.Start,
-		&newEduInfo.Finish,
-		&newEduInfo.CreatedAt,
-		&newEduInfo.UpdatedAt,
-	)
-
+	rows, err := u.DB.QueryContext(ctx, stmt, userID)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return models.UserEducationInfo{}, repository.ErrRecordNotFound
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.UserEducation{}
+	for rows.Next() {
+		education := models.UserEducation{}
+		err := rows.Scan(
+			&education.ID,
+			&education.UserID,
+			&education.Institute,
+			&education.Degree,
+			&education.Discipline,
+			&education.Start,
+			&education.Finish,
+			&education.CreatedAt,
+			&education.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
 		}
-		return models.UserEducationInfo{}, err
+		entries = append(entries, education)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
-	return newEduInfo, nil
+	return entries, nil
 }
 
-func (u *userImplementation) UpdateEducationInfo(eduInfo *models.UserEducationInfo) error {
+func (u *userImplementation) UpdateEducation(education *models.UserEducation) error {
 	stmt := `
-    UPDATE users_education_info
+    UPDATE users_education
     SET
-        institute = $2,
-        degree = $3,
-        discipline = $4,
-        start = $5,
-        finish = $6,
+        institute = $3,
+        degree = $4,
+        discipline = $5,
+        start = $6,
+        finish = $7,
         updated_at = now()
-    WHERE user_id = $1
+    WHERE id = $1 AND user_id = $2
     RETURNING
-        user_id,
-        institute,
-        degree,
-        discipline,
-        start,
-        finish,
         created_at,
         updated_at;
     `
@@ -131,25 +127,44 @@ func (u *userImplementation) UpdateEducationInfo(eduInfo *models.UserEducationIn
 	err := u.DB.QueryRowContext(
 		ctx,
 		stmt,
-		eduInfo.UserID,
-		eduInfo.Institute,
-		eduInfo.Degree,
-		eduInfo.Discipline,
-		eduInfo.Start,
-		eduInfo.Finish,
-	).Scan(
-		&eduInfo.UserID,
-		&eduInfo.Institute,
-		&eduInfo.Degree,
-		&eduInfo.Discipline,
-		&eduInfo.Start,
-		&eduInfo.Finish,
-		&eduInfo.CreatedAt,
-		&eduInfo.UpdatedAt,
-	)
+		education.ID,
+		education.UserID,
+		education.Institute,
+		education.Degree,
+		education.Discipline,
+		education.Start,
+		education.Finish,
+	).Scan(&education.CreatedAt, &education.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return repository.ErrRecordNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (u *userImplementation) DeleteEducation(id, userID string) error {
+	stmt := `
+    DELETE FROM users_education
+    WHERE id = $1 AND user_id = $2;
+    `
+
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	result, err := u.DB.ExecContext(ctx, stmt, id, userID)
 	if err != nil {
 		return err
 	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return repository.ErrRecordNotFound
+	}
 
 	return nil
 }
@@ -163,43 +178,104 @@ func (u *userImplementation) CreateCertification(cert *models.UserCertification)
     INSERT INTO users_certifications (
         id,
         user_id,
-        url
+        url,
+        title,
+        issuer,
+        issued_on,
+        expires_on,
+        status
     ) VALUES (
-        $1, $2, $3
+        $1, $2, $3, $4, $5, $6, $7, $8
     ) RETURNING
         id,
         user_id,
-        url;
+        url,
+        title,
+        issuer,
+        issued_on,
+        expires_on,
+        status,
+        review_notes,
+        reviewer_id,
+        reviewed_at,
+        created_at,
+        updated_at;
     `
 	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
 	defer cancel()
 
-	err := u.DB.QueryRowContext(
+	created, err := scanCertification(u.DB.QueryRowContext(
 		ctx,
 		stmt,
 		cert.ID,
 		cert.UserID,
 		cert.URL,
-	).Scan(
-		&cert.ID,
-		&cert.UserID,
-		&cert.URL,
-	)
+		cert.Title,
+		cert.Issuer,
+		cert.IssuedOn,
+		cert.ExpiresOn,
+		models.CERTIFICATION_PENDING,
+	))
 	if err != nil {
 		return err
 	}
+	*cert = created
 
 	return nil
 }
 
+func (u *userImplementation) GetCertification(id string) (models.UserCertification, error) {
+	stmt := `
+    SELECT
+        id,
+        user_id,
+        url,
+        title,
+        issuer,
+        issued_on,
+        expires_on,
+        status,
+        review_notes,
+        reviewer_id,
+        reviewed_at,
+        created_at,
+        updated_at
+    FROM users_certifications
+    WHERE id = $1;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	cert, err := scanCertification(u.DB.QueryRowContext(ctx, stmt, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.UserCertification{}, repository.ErrRecordNotFound
+		}
+		return models.UserCertification{}, err
+	}
+
+	return cert, nil
+}
+
 func (u *userImplementation) GetCertifications(userID string) ([]models.UserCertification, error) {
 	stmt := `
     SELECT
         id,
         user_id,
-        url
+        url,
+        title,
+        issuer,
+        issued_on,
+        expires_on,
+        status,
+        review_notes,
+        reviewer_id,
+        reviewed_at,
+        created_at,
+        updated_at
     FROM users_certifications
     WHERE user_id = $1
+    ORDER BY created_at;
     `
 
 	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
@@ -212,15 +288,196 @@ func (u *userImplementation) GetCertifications(userID string) ([]models.UserCert
 		}
 		return nil, err
 	}
+	defer rows.Close()
+
+	return scanCertifications(rows)
+}
+
+func (u *userImplementation) GetApprovedCertifications(userID string) ([]models.UserCertification, error) {
+	stmt := `
+    SELECT
+        id,
+        user_id,
+        url,
+        title,
+        issuer,
+        issued_on,
+        expires_on,
+        status,
+        review_notes,
+        reviewer_id,
+        reviewed_at,
+        created_at,
+        updated_at
+    FROM users_certifications
+    WHERE
+        user_id = $1
+        AND status = $2
+        AND (expires_on IS NULL OR expires_on >= CURRENT_DATE)
+    ORDER BY created_at;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	rows, err := u.DB.QueryContext(ctx, stmt, userID, models.CERTIFICATION_APPROVED)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanCertifications(rows)
+}
+
+func (u *userImplementation) GetAllCertifications(filter models.CertificationFilter) ([]models.UserCertification, error) {
+	stmt := `
+    SELECT
+        id,
+        user_id,
+        url,
+        title,
+        issuer,
+        issued_on,
+        expires_on,
+        status,
+        review_notes,
+        reviewer_id,
+        reviewed_at,
+        created_at,
+        updated_at
+    FROM users_certifications
+    WHERE
+        ($1 = '' OR user_id = $1::UUID) AND
+        ($2 = '' OR status = $2)
+    ORDER BY created_at
+    LIMIT $3 OFFSET $4;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	rows, err := u.DB.QueryContext(
+		ctx,
+		stmt,
+		filter.UserID,
+		filter.Status,
+		filter.Limit,
+		filter.Offset(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanCertifications(rows)
+}
+
+func (u *userImplementation) ReviewCertification(cert *models.UserCertification) error {
+	stmt := `
+    UPDATE users_certifications
+    SET
+        status = $2,
+        review_notes = $3,
+        reviewer_id = $4,
+        reviewed_at = now(),
+        updated_at = now()
+    WHERE id = $1 AND status = $5
+    RETURNING
+        reviewed_at,
+        updated_at;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	err := u.DB.QueryRowContext(
+		ctx,
+		stmt,
+		cert.ID,
+		cert.Status,
+		cert.ReviewNotes,
+		cert.ReviewerID,
+		models.CERTIFICATION_PENDING,
+	).Scan(&cert.ReviewedAt, &cert.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return repository.ErrCertReviewed
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (u *userImplementation) ExpireCertifications() ([]models.UserCertification, error) {
+	stmt := `
+    UPDATE users_certifications
+    SET
+        status = $2,
+        updated_at = now()
+    WHERE status = $1 AND expires_on < CURRENT_DATE
+    RETURNING
+        id,
+        user_id,
+        url,
+        title,
+        issuer,
+        issued_on,
+        expires_on,
+        status,
+        review_notes,
+        reviewer_id,
+        reviewed_at,
+        created_at,
+        updated_at;
+    `
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	rows, err := u.DB.QueryContext(
+		ctx,
+		stmt,
+		models.CERTIFICATION_APPROVED,
+		models.CERTIFICATION_EXPIRED,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
+	return scanCertifications(rows)
+}
+
+// scanCertification scans a users_certifications row selected with all
+// its columns.
+func scanCertification(row interface{ Scan(...any) error }) (models.UserCertification, error) {
+	cert := models.UserCertification{}
+	err := row.Scan(
+		&cert.ID,
+		&cert.UserID,
+		&cert.URL,
+		&cert.Title,
+		&cert.Issuer,
+		&cert.IssuedOn,
+		&cert.ExpiresOn,
+		&cert.Status,
+		&cert.ReviewNotes,
+		&cert.ReviewerID,
+		&cert.ReviewedAt,
+		&cert.CreatedAt,
+		&cert.UpdatedAt,
+	)
+	return cert, err
+}
+
+func scanCertifications(rows *sql.Rows) ([]models.UserCertification, error) {
 	certs := []models.UserCertification{}
 	for rows.Next() {
-		newCert := models.UserCertification{}
-		err := rows.Scan(&newCert.ID, &newCert.UserID, &newCert.URL)
+		cert, err := scanCertification(rows)
 		if err != nil {
 			return nil, err
 		}
-		certs = append(certs, newCert)
+		certs = append(certs, cert)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
 	return certs, nil
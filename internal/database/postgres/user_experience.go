@@ -0,0 +1,169 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/repository"
+)
+
+func (u *userImplementation) CreateExperience(experience *models.UserExperience) error {
+	if experience.ID == "" {
+		experience.ID = uuid.NewString()
+	}
+	stmt := `
+    INSERT INTO users_experience (
+        id,
+        user_id,
+        employer,
+        role,
+        start,
+        finish,
+        description
+    ) VALUES (
+        $1, $2, $3, $4, $5, $6, $7
+    ) RETURNING
+        created_at,
+        updated_at;
+    `
+
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	err := u.DB.QueryRowContext(
+		ctx,
+		stmt,
+		experience.ID,
+		experience.UserID,
+		experience.Employer,
+		experience.Role,
+		experience.Start,
+		experience.Finish,
+		experience.Description,
+	).Scan(&experience.CreatedAt, &experience.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (u *userImplementation) GetExperience(userID string) ([]models.UserExperience, error) {
+	stmt := `
+    SELECT
+        id,
+        user_id,
+        employer,
+        role,
+        start,
+        finish,
+        description,
+        created_at,
+        updated_at
+    FROM users_experience
+    WHERE user_id = $1
+    ORDER BY start DESC;
+    `
+
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	rows, err := u.DB.QueryContext(ctx, stmt, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.UserExperience{}
+	for rows.Next() {
+		experience := models.UserExperience{}
+		err := rows.Scan(
+			&experience.ID,
+			&experience.UserID,
+			&experience.Employer,
+			&experience.Role,
+			&experience.Start,
+			&experience.Finish,
+			&experience.Description,
+			&experience.CreatedAt,
+			&experience.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, experience)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (u *userImplementation) UpdateExperience(experience *models.UserExperience) error {
+	stmt := `
+    UPDATE users_experience
+    SET
+        employer = $3,
+        role = $4,
+        start = $5,
+        finish = $6,
+        description = $7,
+        updated_at = now()
+    WHERE id = $1 AND user_id = $2
+    RETURNING
+        created_at,
+        updated_at;
+    `
+
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	err := u.DB.QueryRowContext(
+		ctx,
+		stmt,
+		experience.ID,
+		experience.UserID,
+		experience.Employer,
+		experience.Role,
+		experience.Start,
+		experience.Finish,
+		experience.Description,
+	).Scan(&experience.CreatedAt, &experience.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return repository.ErrRecordNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (u *userImplementation) DeleteExperience(id, userID string) error {
+	stmt := `
+    DELETE FROM users_experience
+    WHERE id = $1 AND user_id = $2;
+    `
+
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	result, err := u.DB.ExecContext(ctx, stmt, id, userID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,175 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/repository"
+)
+
+func (u *userImplementation) UpdatePassword(id, hashedPassword string) error {
+	stmt := `
+    UPDATE users
+    SET
+        password = $2,
+        updated_at = now()
+    WHERE id = $1;
+    `
+
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	result, err := u.DB.ExecContext(ctx, stmt, id, hashedPassword)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (u *userImplementation) CreatePasswordReset(reset *models.PasswordReset) error {
+	if reset.ID == "" {
+		reset.ID = uuid.NewString()
+	}
+	stmt := `
+    INSERT INTO password_resets (
+        id,
+        user_id,
+        token_hash,
+        channel
+    ) VALUES (
+        $1, $2, $3, $4
+    ) RETURNING
+        attempts,
+        created_at,
+        expires_at;
+    `
+
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	err := u.DB.QueryRowContext(
+		ctx,
+		stmt,
+		reset.ID,
+		reset.UserID,
+		reset.TokenHash,
+		reset.Channel,
+	).Scan(
+		&reset.Attempts,
+		&reset.CreatedAt,
+		&reset.ExpiresAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (u *userImplementation) GetPasswordReset(userID string) (models.PasswordReset, error) {
+	stmt := `
+    SELECT
+        id,
+        user_id,
+        token_hash,
+        channel,
+        attempts,
+        created_at,
+        expires_at
+    FROM password_resets
+    WHERE user_id = $1;
+    `
+
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	reset := models.PasswordReset{}
+	err := u.DB.QueryRowContext(ctx, stmt, userID).Scan(
+		&reset.ID,
+		&reset.UserID,
+		&reset.TokenHash,
+		&reset.Channel,
+		&reset.Attempts,
+		&reset.CreatedAt,
+		&reset.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.PasswordReset{}, repository.ErrRecordNotFound
+		}
+		return models.PasswordReset{}, err
+	}
+
+	return reset, nil
+}
+
+func (u *userImplementation) IncrementPasswordResetAttempts(id string) error {
+	stmt := `
+    UPDATE password_resets
+    SET attempts = attempts + 1
+    WHERE id = $1;
+    `
+
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	_, err := u.DB.ExecContext(ctx, stmt, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (u *userImplementation) ConsumePasswordReset(id string) error {
+	stmt := `
+    DELETE FROM password_resets
+    WHERE id = $1;
+    `
+
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	result, err := u.DB.ExecContext(ctx, stmt, id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (u *userImplementation) DeletePasswordReset(userID string) error {
+	stmt := `
+    DELETE FROM password_resets
+    WHERE user_id = $1;
+    `
+
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	_, err := u.DB.ExecContext(ctx, stmt, userID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -26,9 +26,10 @@ func (u *userImplementation) CreateService(service *models.UserService) error {
         rate_per_hour,
         experience_years,
         availability,
-        address
+        address,
+        location
     ) VALUES (
-        $1, $2, $3, $4, $5, $6, $7, $8
+        $1, $2, $3, $4, $5, $6, $7, $8, $9
     ) RETURNING
         id,
         user_id,
@@ -38,6 +39,7 @@ func (u *userImplementation) CreateService(service *models.UserService) error {
         experience_years,
         availability,
         address,
+        location,
         created_at,
         updated_at;
     `
@@ -56,6 +58,7 @@ func (u *userImplementation) CreateService(service *models.UserService) error {
 		service.ExperienceYears,
 		service.Availability,
 		service.Address,
+		service.Location,
 	).Scan(
 		&service.ID,
 		&service.UserID,
@@ -65,6 +68,7 @@ func (u *userImplementation) CreateService(service *models.UserService) error {
 		&service.ExperienceYears,
 		&service.Availability,
 		&service.Address,
+		&service.Location,
 		&service.CreatedAt,
 		&service.UpdatedAt,
 	)
@@ -91,6 +95,7 @@ func (u *userImplementation) GetService(userID, serviceType string) (models.User
         experience_years,
         availability,
         address,
+        location,
         created_at,
         updated_at
     FROM services
@@ -110,6 +115,7 @@ func (u *userImplementation) GetService(userID, serviceType string) (models.User
 		&newService.ExperienceYears,
 		&newService.Availability,
 		&newService.Address,
+		&newService.Location,
 		&newService.CreatedAt,
 		&newService.UpdatedAt,
 	)
@@ -134,6 +140,7 @@ func (u *userImplementation) GetAllServices(userID string) ([]models.UserService
         experience_years,
         availability,
         address,
+        location,
         created_at,
         updated_at
     FROM services
@@ -159,6 +166,7 @@ func (u *userImplementation) GetAllServices(userID string) ([]models.UserService
 			&newService.ExperienceYears,
 			&newService.Availability,
 			&newService.Address,
+			&newService.Location,
 			&newService.CreatedAt,
 			&newService.UpdatedAt,
 		)
@@ -179,6 +187,7 @@ func (u *userImplementation) UpdateService(service *models.UserService) error {
         experience_years = $5,
         availability = $6,
         address = $7,
+        location = $8,
         updated_at = now()
     WHERE user_id = $1 AND service_type = $2
     RETURNING
@@ -190,6 +199,7 @@ func (u *userImplementation) UpdateService(service *models.UserService) error {
         experience_years,
         availability,
         address,
+        location,
         created_at,
         updated_at
     `
@@ -207,6 +217,7 @@ func (u *userImplementation) UpdateService(service *models.UserService) error {
 		service.ExperienceYears,
 		service.Availability,
 		service.Address,
+		service.Location,
 	).Scan(
 		&service.ID,
 		&service.UserID,
@@ -216,6 +227,7 @@ func (u *userImplementation) UpdateService(service *models.UserService) error {
 		&service.ExperienceYears,
 		&service.Availability,
 		&service.Address,
+		&service.Location,
 		&service.CreatedAt,
 		&service.UpdatedAt,
 	)
@@ -243,6 +255,93 @@ func (u *userImplementation) DeleteService(userID, serviceType string) error {
 	return nil
 }
 
+func (u *userImplementation) GetUngeocodedServices(afterID string, limit int) ([]models.UserService, error) {
+	stmt := `
+    SELECT
+        id,
+        user_id,
+        service_type,
+        service_desc,
+        rate_per_hour,
+        experience_years,
+        availability,
+        address,
+        location,
+        created_at,
+        updated_at
+    FROM services
+    WHERE
+        (latitude IS NULL OR longitude IS NULL) AND
+        address <> '' AND
+        ($1 = '' OR id > $1::UUID)
+    ORDER BY id
+    LIMIT $2;
+    `
+
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	rows, err := u.DB.QueryContext(ctx, stmt, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	services := []models.UserService{}
+	for rows.Next() {
+		newService := models.UserService{}
+		err := rows.Scan(
+			&newService.ID,
+			&newService.UserID,
+			&newService.ServiceType,
+			&newService.ServiceDesc,
+			&newService.RatePerHour,
+			&newService.ExperienceYears,
+			&newService.Availability,
+			&newService.Address,
+			&newService.Location,
+			&newService.CreatedAt,
+			&newService.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		services = append(services, newService)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return services, nil
+}
+
+func (u *userImplementation) UpdateServiceLocation(id string, location models.Location) error {
+	stmt := `
+    UPDATE services
+    SET
+        location = $2,
+        updated_at = now()
+    WHERE id = $1;
+    `
+
+	ctx, cancel := context.WithTimeout(context.Background(), DB_QUERY_TIMEOUT)
+	defer cancel()
+
+	result, err := u.DB.ExecContext(ctx, stmt, id, location)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
+}
+
 // Service images
 func (u *userImplementation) CreateServiceImage(serviceImg *models.ServiceImage) error {
 	if serviceImg.ID == "" {
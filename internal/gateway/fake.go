@@ -0,0 +1,164 @@
+package gateway
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/lokatalent/backend_go/internal/models"
+)
+
+const fakeSignatureHeader = "x-fake-signature"
+
+// Fake is an in-process PaymentGateway for local development and tests.
+// Charges and transfers resolve to a default status that can be overridden
+// per reference, so success, failure and pending flows can be simulated
+// without calling a payment provider.
+type Fake struct {
+	mu        sync.Mutex
+	secret    string
+	status    string
+	overrides map[string]string
+	charges   map[string]Charge
+	transfers map[string]Transfer
+}
+
+// NewFake returns a fake gateway that resolves charges and transfers with
+// status, and signs webhooks with secret.
+func NewFake(secret, status string) *Fake {
+	if status == "" {
+		status = STATUS_SUCCESS
+	}
+	return &Fake{
+		secret:    secret,
+		status:    status,
+		overrides: map[string]string{},
+		charges:   map[string]Charge{},
+		transfers: map[string]Transfer{},
+	}
+}
+
+// SetStatus sets the status reported for a charge or transfer reference.
+func (f *Fake) SetStatus(reference, status string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.overrides[reference] = status
+}
+
+// Charges returns the charges initialized so far, by reference.
+func (f *Fake) Charges() map[string]Charge {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	charges := make(map[string]Charge, len(f.charges))
+	for reference, charge := range f.charges {
+		charges[reference] = charge
+	}
+	return charges
+}
+
+// Transfers returns the transfers started so far, by reference.
+func (f *Fake) Transfers() map[string]Transfer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	transfers := make(map[string]Transfer, len(f.transfers))
+	for reference, transfer := range f.transfers {
+		transfers[reference] = transfer
+	}
+	return transfers
+}
+
+func (f *Fake) InitializeCharge(charge Charge) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.charges[charge.Reference] = charge
+	return "fake_access_" + charge.Reference, nil
+}
+
+// VerifyCharge reports the amount of the initialized charge, or zero for
+// an unknown reference.
+func (f *Fake) VerifyCharge(reference string) (string, models.Money, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.statusFor(reference), f.charges[reference].Amount, nil
+}
+
+func (f *Fake) ResolveAccount(accountNum, bankCode string) (string, error) {
+	return fmt.Sprintf("FAKE ACCOUNT %s", accountNum), nil
+}
+
+func (f *Fake) CreateRecipient(account BankAccount) (string, error) {
+	return fmt.Sprintf("RCP_fake_%s_%s", account.BankCode, account.AccountNum), nil
+}
+
+func (f *Fake) DeleteRecipient(recipientCode string) error {
+	return nil
+}
+
+func (f *Fake) Transfer(transfer Transfer) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.transfers[transfer.Reference] = transfer
+	return f.statusFor(transfer.Reference), nil
+}
+
+// VerifyTransfer fails with ErrNotFound for references that were neither
+// transferred nor given a status.
+func (f *Fake) VerifyTransfer(reference string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, transferred := f.transfers[reference]
+	_, overridden := f.overrides[reference]
+	if !transferred && !overridden {
+		return "", ErrNotFound
+	}
+	return f.statusFor(reference), nil
+}
+
+type fakeEvent struct {
+	Type      string       `json:"type"`
+	Reference string       `json:"reference"`
+	Status    string       `json:"status"`
+	Amount    models.Money `json:"amount"`
+}
+
+// ParseWebhook decodes an event signed by Webhook in the
+// x-fake-signature header.
+func (f *Fake) ParseWebhook(payload []byte, header http.Header) (Event, error) {
+	if !verifySignature(sha512.New, payload, header.Get(fakeSignatureHeader), f.secret) {
+		return Event{}, ErrInvalidSignature
+	}
+
+	event := fakeEvent{}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return Event{}, err
+	}
+	return Event(event), nil
+}
+
+// Webhook returns a signed webhook request body and headers for event,
+// to be delivered to the webhook endpoint.
+func (f *Fake) Webhook(event Event) ([]byte, http.Header, error) {
+	payload, err := json.Marshal(fakeEvent(event))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mac := hmac.New(sha512.New, []byte(f.secret))
+	mac.Write(payload)
+	header := http.Header{}
+	header.Set(fakeSignatureHeader, hex.EncodeToString(mac.Sum(nil)))
+	header.Set("Content-Type", "application/json")
+	return payload, header, nil
+}
+
+// statusFor must be called with f.mu held.
+func (f *Fake) statusFor(reference string) string {
+	if status, ok := f.overrides[reference]; ok {
+		return status
+	}
+	return f.status
+}
@@ -0,0 +1,96 @@
+// Package gateway defines the payment gateway used to collect booking
+// charges and pay out service providers, with Paystack and an in-process
+// fake as implementations.
+package gateway
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/lokatalent/backend_go/internal/models"
+)
+
+// gateway names, selected with the PAYMENT_GATEWAY environment variable.
+const (
+	PAYSTACK = "paystack"
+	FAKE     = "fake"
+)
+
+// charge and transfer statuses
+const (
+	STATUS_SUCCESS   = "success"
+	STATUS_PENDING   = "pending"
+	STATUS_FAILED    = "failed"
+	STATUS_ABANDONED = "abandoned"
+	STATUS_REVERSED  = "reversed"
+)
+
+// webhook events
+const (
+	EVENT_CHARGE_SUCCESS    = "charge.success"
+	EVENT_TRANSFER_SUCCESS  = "transfer.success"
+	EVENT_TRANSFER_FAILED   = "transfer.failed"
+	EVENT_TRANSFER_REVERSED = "transfer.reversed"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrNotFound is returned when the provider has no record of a
+	// reference.
+	ErrNotFound = errors.New("reference not found")
+)
+
+// Charge is a payment collected from a customer.
+type Charge struct {
+	Email       string
+	Reference   string
+	CallbackURL string
+	Amount      models.Money
+}
+
+// Transfer is a payout to a transfer recipient.
+type Transfer struct {
+	Reference     string
+	RecipientCode string
+	Reason        string
+	Amount        models.Money
+}
+
+// BankAccount identifies the bank account of a transfer recipient.
+type BankAccount struct {
+	AccountName string
+	AccountNum  string
+	BankCode    string
+}
+
+// Event is a charge or transfer notification received by webhook.
+type Event struct {
+	Type      string
+	Reference string
+	Status    string
+	Amount    models.Money
+}
+
+// PaymentGateway collects charges and makes transfers through a payment
+// provider.
+type PaymentGateway interface {
+	// InitializeCharge starts a charge and returns its access code.
+	InitializeCharge(charge Charge) (string, error)
+	// VerifyCharge returns the status of a charge and the amount paid.
+	VerifyCharge(reference string) (string, models.Money, error)
+	// ResolveAccount returns the name on a bank account.
+	ResolveAccount(accountNum, bankCode string) (string, error)
+	// CreateRecipient registers a bank account for transfers and returns
+	// its recipient code.
+	CreateRecipient(account BankAccount) (string, error)
+	DeleteRecipient(recipientCode string) error
+	// Transfer starts a transfer and returns its status.
+	Transfer(transfer Transfer) (string, error)
+	// VerifyTransfer returns the status of a transfer, or ErrNotFound if
+	// it was never started.
+	VerifyTransfer(reference string) (string, error)
+	// ParseWebhook authenticates a webhook request and decodes its event.
+	// It returns ErrInvalidSignature when the request was not signed by
+	// the provider.
+	ParseWebhook(payload []byte, header http.Header) (Event, error)
+}
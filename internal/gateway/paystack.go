@@ -0,0 +1,312 @@
+package gateway
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/lokatalent/backend_go/internal/models"
+)
+
+const (
+	PAYSTACK_BASE_URL = "https://api.paystack.co"
+
+	paystackSignatureHeader = "x-paystack-signature"
+	paystackTimeout         = 10 * time.Second
+	paystackAttempts        = 3
+)
+
+// Paystack is a PaymentGateway backed by the Paystack API.
+type Paystack struct {
+	apiKey  string
+	baseURL string
+	client  *http.Client
+}
+
+// NewPaystack returns a Paystack gateway authenticated with a secret key.
+func NewPaystack(apiKey string) *Paystack {
+	return &Paystack{
+		apiKey:  apiKey,
+		baseURL: PAYSTACK_BASE_URL,
+		client:  &http.Client{Timeout: paystackTimeout},
+	}
+}
+
+type initTransactionPayload struct {
+	Amount      string `json:"amount"`
+	Email       string `json:"email"`
+	Reference   string `json:"reference"`
+	Currency    string `json:"currency"`
+	CallbackURL string `json:"callback_url"`
+}
+
+type initTransferPayload struct {
+	Source        string `json:"source"`
+	Amount        string `json:"amount"`
+	Reason        string `json:"reason"`
+	RecipientCode string `json:"recipient"`
+	Reference     string `json:"reference"`
+	Currency      string `json:"currency"`
+}
+
+type createRecipientPayload struct {
+	Type        string `json:"type"`
+	AccountName string `json:"name"`
+	AccountNum  string `json:"account_number"`
+	BankCode    string `json:"bank_code"`
+	Currency    string `json:"currency"`
+}
+
+type paystackResponse struct {
+	Status  bool         `json:"status"`
+	Message string       `json:"message"`
+	Data    responseData `json:"data"`
+}
+
+type responseData struct {
+	Status           string `json:"status,omitempty"`
+	Reference        string `json:"reference,omitempty"`
+	Amount           int64  `json:"amount,omitempty"` // in minor units.
+	Currency         string `json:"currency,omitempty"`
+	AccessCode       string `json:"access_code,omitempty"`
+	AuthorizationURL string `json:"authorization_url,omitempty"`
+	AccountName      string `json:"account_name,omitempty"`
+	AccountNumber    string `json:"account_number,omitempty"`
+	RecipientCode    string `json:"recipient_code,omitempty"`
+}
+
+type paystackEvent struct {
+	Event string       `json:"event"`
+	Data  responseData `json:"data"`
+}
+
+func (p *Paystack) InitializeCharge(charge Charge) (string, error) {
+	resp, err := p.do(http.MethodPost, "/transaction/initialize", initTransactionPayload{
+		Amount:      strconv.FormatInt(charge.Amount.Minor(), 10),
+		Email:       charge.Email,
+		Reference:   charge.Reference,
+		Currency:    charge.Amount.Currency(),
+		CallbackURL: charge.CallbackURL,
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.Data.AccessCode, nil
+}
+
+func (p *Paystack) VerifyCharge(reference string) (string, models.Money, error) {
+	resp, err := p.do(http.MethodGet, "/transaction/verify/"+url.PathEscape(reference), nil)
+	if err != nil {
+		return "", models.Money{}, err
+	}
+	return resp.Data.Status, resp.Data.money(), nil
+}
+
+func (p *Paystack) ResolveAccount(accountNum, bankCode string) (string, error) {
+	query := url.Values{}
+	query.Set("account_number", accountNum)
+	query.Set("bank_code", bankCode)
+
+	resp, err := p.do(http.MethodGet, "/bank/resolve?"+query.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	return resp.Data.AccountName, nil
+}
+
+func (p *Paystack) CreateRecipient(account BankAccount) (string, error) {
+	resp, err := p.do(http.MethodPost, "/transferrecipient", createRecipientPayload{
+		Type:        "nuban",
+		AccountName: account.AccountName,
+		AccountNum:  account.AccountNum,
+		BankCode:    account.BankCode,
+		Currency:    models.CURRENCY_NGN,
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.Data.RecipientCode, nil
+}
+
+// DeleteRecipient deletes a transfer recipient. A recipient that no
+// longer exists is not an error.
+func (p *Paystack) DeleteRecipient(recipientCode string) error {
+	path := "/transferrecipient/" + url.PathEscape(recipientCode)
+
+	var err error
+	for range paystackAttempts {
+		var req *http.Request
+		req, err = p.newRequest(http.MethodDelete, path, nil)
+		if err != nil {
+			return err
+		}
+
+		var resp *http.Response
+		resp, err = p.client.Do(req)
+		if err != nil {
+			continue
+		}
+		var body []byte
+		body, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			continue
+		}
+
+		switch resp.StatusCode {
+		case http.StatusOK, http.StatusNotFound:
+			return nil
+		default:
+			err = fmt.Errorf("%s: %d %s", req.URL, resp.StatusCode, string(body))
+		}
+	}
+	return err
+}
+
+func (p *Paystack) Transfer(transfer Transfer) (string, error) {
+	resp, err := p.do(http.MethodPost, "/transfer", initTransferPayload{
+		Amount:        strconv.FormatInt(transfer.Amount.Minor(), 10),
+		Source:        "balance",
+		Reference:     transfer.Reference,
+		Currency:      transfer.Amount.Currency(),
+		RecipientCode: transfer.RecipientCode,
+		Reason:        transfer.Reason,
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.Data.Status, nil
+}
+
+func (p *Paystack) VerifyTransfer(reference string) (string, error) {
+	resp, err := p.do(http.MethodGet, "/transfer/verify/"+url.PathEscape(reference), nil)
+	if err != nil {
+		return "", err
+	}
+	return resp.Data.Status, nil
+}
+
+// ParseWebhook checks the x-paystack-signature header, the HMAC-SHA512
+// of the payload keyed with the secret key, and decodes the event.
+func (p *Paystack) ParseWebhook(payload []byte, header http.Header) (Event, error) {
+	if !verifySignature(sha512.New, payload, header.Get(paystackSignatureHeader), p.apiKey) {
+		return Event{}, ErrInvalidSignature
+	}
+
+	event := paystackEvent{}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return Event{}, err
+	}
+
+	return Event{
+		Type:      event.Event,
+		Reference: event.Data.Reference,
+		Status:    event.Data.Status,
+		Amount:    event.Data.money(),
+	}, nil
+}
+
+// helpers
+
+// money returns the amount of a charge or transfer.
+func (d responseData) money() models.Money {
+	currency := d.Currency
+	if currency == "" {
+		currency = models.DEFAULT_CURRENCY
+	}
+	return models.NewMoney(d.Amount, currency)
+}
+
+func (p *Paystack) newRequest(method, path string, body []byte) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, p.baseURL+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add(
+		"Authorization",
+		fmt.Sprintf("Bearer %s", p.apiKey),
+	)
+	if body != nil {
+		req.Header.Add(
+			"Content-Type",
+			"application/json",
+		)
+	}
+	return req, nil
+}
+
+// do sends a request to the Paystack API, retrying on network and server
+// errors. Requests for unknown resources fail with ErrNotFound.
+func (p *Paystack) do(method, path string, payload any) (paystackResponse, error) {
+	var body []byte
+	if payload != nil {
+		var err error
+		body, err = json.Marshal(payload)
+		if err != nil {
+			return paystackResponse{}, err
+		}
+	}
+
+	var err error
+	var paystackResp paystackResponse
+	for range paystackAttempts {
+		var req *http.Request
+		req, err = p.newRequest(method, path, body)
+		if err != nil {
+			return paystackResponse{}, err
+		}
+
+		var resp *http.Response
+		resp, err = p.client.Do(req)
+		if err != nil {
+			continue
+		}
+		var respBody []byte
+		respBody, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			continue
+		}
+
+		if resp.StatusCode == http.StatusNotFound {
+			return paystackResp, fmt.Errorf("%w: %s: %s", ErrNotFound, req.URL, string(respBody))
+		}
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			err = fmt.Errorf("%s: %d %s", req.URL, resp.StatusCode, string(respBody))
+			// client errors will not succeed on retry.
+			if resp.StatusCode < 500 {
+				return paystackResp, err
+			}
+			continue
+		}
+		err = json.Unmarshal(respBody, &paystackResp)
+		return paystackResp, err
+	}
+	return paystackResp, err
+}
+
+// verifySignature compares a hex encoded HMAC of payload with signature
+// in constant time.
+func verifySignature(newHash func() hash.Hash, payload []byte, signature, key string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil || len(expected) == 0 {
+		return false
+	}
+
+	mac := hmac.New(newHash, []byte(key))
+	mac.Write(payload)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
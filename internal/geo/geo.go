@@ -0,0 +1,50 @@
+// Package geo geocodes place addresses and measures distances between
+// them.
+package geo
+
+import (
+	"errors"
+	"math"
+
+	"github.com/lokatalent/backend_go/internal/models"
+)
+
+// EARTH_RADIUS_KM is the mean radius of the earth used by the haversine
+// formula. Queries computing distances in SQL must use the same value.
+const EARTH_RADIUS_KM = 6371.0
+
+// kilometres per degree of latitude.
+const kmPerDegree = 2 * math.Pi * EARTH_RADIUS_KM / 360
+
+var ErrAddressNotFound = errors.New("address could not be located")
+
+// Geocoder resolves a place address to a location with coordinates.
+type Geocoder interface {
+	Geocode(address string) (models.Location, error)
+}
+
+// Distance returns the great-circle distance between two points in
+// kilometres.
+func Distance(lat1, lng1, lat2, lng2 float64) float64 {
+	dLat := radians(lat2 - lat1)
+	dLng := radians(lng2 - lng1)
+	a := math.Pow(math.Sin(dLat/2), 2) +
+		math.Cos(radians(lat1))*math.Cos(radians(lat2))*math.Pow(math.Sin(dLng/2), 2)
+	return 2 * EARTH_RADIUS_KM * math.Asin(math.Sqrt(a))
+}
+
+// BoundingBox returns the latitude and longitude deltas, in degrees, of a
+// box enclosing a circle of radiusKm around a point at lat. It lets
+// queries discard far away rows with an index before computing
+// distances.
+func BoundingBox(lat, radiusKm float64) (float64, float64) {
+	latDelta := radiusKm / kmPerDegree
+	// longitude lines converge towards the poles.
+	cos := math.Max(math.Cos(radians(lat)), 0.01)
+	lngDelta := math.Min(latDelta/cos, 180)
+	return latDelta, lngDelta
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
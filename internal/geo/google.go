@@ -0,0 +1,134 @@
+package geo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lokatalent/backend_go/internal/models"
+)
+
+const (
+	GOOGLE_GEOCODE_URL = "https://maps.googleapis.com/maps/api/geocode/json"
+
+	googleTimeout  = 10 * time.Second
+	googleAttempts = 3
+)
+
+// Google geocodes addresses with the Google Geocoding API.
+type Google struct {
+	apiKey string
+	client *http.Client
+}
+
+// NewGoogle returns a geocoder authenticated with a Maps API key.
+func NewGoogle(apiKey string) *Google {
+	return &Google{
+		apiKey: apiKey,
+		client: &http.Client{Timeout: googleTimeout},
+	}
+}
+
+type geocodeResponse struct {
+	Status  string          `json:"status"`
+	ErrMsg  string          `json:"error_message,omitempty"`
+	Results []geocodeResult `json:"results"`
+}
+
+type geocodeResult struct {
+	AddressComponents []struct {
+		LongName string   `json:"long_name"`
+		Types    []string `json:"types"`
+	} `json:"address_components"`
+	Geometry struct {
+		Location struct {
+			Lat float64 `json:"lat"`
+			Lng float64 `json:"lng"`
+		} `json:"location"`
+	} `json:"geometry"`
+}
+
+// Geocode resolves address to its best match. Components missing from
+// the match keep the values given in the "street_addr, city, state,
+// country" address.
+func (g *Google) Geocode(address string) (models.Location, error) {
+	query := url.Values{}
+	query.Set("address", address)
+	query.Set("language", "en")
+	query.Set("key", g.apiKey)
+	URL := GOOGLE_GEOCODE_URL + "?" + query.Encode()
+
+	var err error
+	var geocodeResp geocodeResponse
+	for range googleAttempts {
+		var resp *http.Response
+		resp, err = g.client.Get(URL)
+		if err != nil {
+			continue
+		}
+		err = json.NewDecoder(resp.Body).Decode(&geocodeResp)
+		resp.Body.Close()
+		if err != nil {
+			continue
+		}
+
+		switch geocodeResp.Status {
+		case "OK":
+			return locationFromResult(address, geocodeResp.Results[0]), nil
+		case "ZERO_RESULTS":
+			return models.Location{}, ErrAddressNotFound
+		case "UNKNOWN_ERROR":
+			err = fmt.Errorf("geocode: %s", geocodeResp.Status)
+			continue
+		default:
+			return models.Location{}, fmt.Errorf(
+				"geocode: %s %s", geocodeResp.Status, geocodeResp.ErrMsg)
+		}
+	}
+	return models.Location{}, err
+}
+
+func locationFromResult(address string, result geocodeResult) models.Location {
+	location := ParseAddress(address)
+	lat := result.Geometry.Location.Lat
+	lng := result.Geometry.Location.Lng
+	location.Latitude = &lat
+	location.Longitude = &lng
+
+	for _, component := range result.AddressComponents {
+		switch {
+		case slices.Contains(component.Types, "locality"):
+			location.City = component.LongName
+		case slices.Contains(component.Types, "administrative_area_level_1"):
+			location.State = component.LongName
+		case slices.Contains(component.Types, "country"):
+			location.Country = component.LongName
+		}
+	}
+	return location
+}
+
+// ParseAddress splits a "street_addr, city, state, country" address into
+// components, without coordinates.
+func ParseAddress(address string) models.Location {
+	parts := strings.Split(address, ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+
+	location := models.Location{}
+	n := len(parts)
+	if n < 4 {
+		location.StreetAddr = strings.Join(parts, ", ")
+		return location
+	}
+	location.StreetAddr = strings.Join(parts[:n-3], ", ")
+	location.City = parts[n-3]
+	location.State = parts[n-2]
+	location.Country = parts[n-1]
+	return location
+}
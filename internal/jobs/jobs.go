@@ -0,0 +1,259 @@
+// Package jobs runs background jobs stored in Postgres. Workers of every
+// API instance claim due jobs with FOR UPDATE SKIP LOCKED, so each job
+// runs on one worker at a time without an external queue. Failed jobs
+// are retried with exponential backoff, and recurring jobs are enqueued
+// by schedule.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/repository"
+)
+
+const (
+	// interval between polls of an idle worker, and between checks for
+	// due schedules.
+	pollInterval = 5 * time.Second
+	// time a job may run before it is assumed lost, and claimed again.
+	jobLease = 15 * time.Minute
+
+	minBackoff = 30 * time.Second
+	maxBackoff = time.Hour
+)
+
+// Handler runs a job. ctx is canceled when the runner stops.
+type Handler func(ctx context.Context, job models.Job) error
+
+// Schedule returns the time of the next run after a time.
+type Schedule interface {
+	Next(after time.Time) time.Time
+}
+
+type every time.Duration
+
+func (e every) Next(after time.Time) time.Time {
+	return after.Add(time.Duration(e))
+}
+
+// Every runs a job at a fixed interval.
+func Every(interval time.Duration) Schedule {
+	return every(interval)
+}
+
+type daily struct {
+	hour, minute int
+}
+
+func (d daily) Next(after time.Time) time.Time {
+	after = after.UTC()
+	next := time.Date(after.Year(), after.Month(), after.Day(), d.hour, d.minute, 0, 0, time.UTC)
+	if !next.After(after) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// Daily runs a job once a day, at hour:minute UTC.
+func Daily(hour, minute int) Schedule {
+	return daily{hour: hour, minute: minute}
+}
+
+type registration struct {
+	handler     Handler
+	maxAttempts int
+}
+
+type scheduled struct {
+	kind     string
+	schedule Schedule
+}
+
+// Runner runs the jobs of the registered kinds on a pool of workers.
+type Runner struct {
+	repo     repository.JobRepository
+	workers  int
+	handlers map[string]registration
+	kinds    []string
+	schedule []scheduled
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewRunner(repo repository.JobRepository, workers int) *Runner {
+	return &Runner{
+		repo:     repo,
+		workers:  workers,
+		handlers: map[string]registration{},
+	}
+}
+
+// Register sets the handler of a kind of job, which is tried up to
+// maxAttempts times. Jobs must be registered before the runner starts.
+func (r *Runner) Register(kind string, maxAttempts int, handler Handler) {
+	if _, ok := r.handlers[kind]; !ok {
+		r.kinds = append(r.kinds, kind)
+	}
+	r.handlers[kind] = registration{handler: handler, maxAttempts: maxAttempts}
+}
+
+// Schedule enqueues a registered kind of job on schedule. A schedule
+// seen for the first time runs immediately.
+func (r *Runner) Schedule(kind string, schedule Schedule) {
+	r.schedule = append(r.schedule, scheduled{kind: kind, schedule: schedule})
+}
+
+// Enqueue adds a job of a registered kind, run once runAt is reached.
+func (r *Runner) Enqueue(kind string, payload any, runAt time.Time) (models.Job, error) {
+	registration, ok := r.handlers[kind]
+	if !ok {
+		return models.Job{}, fmt.Errorf("jobs: unknown kind %q", kind)
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return models.Job{}, err
+	}
+
+	job := models.Job{
+		Kind:        kind,
+		Payload:     data,
+		MaxAttempts: registration.maxAttempts,
+		RunAt:       runAt,
+	}
+	if err := r.repo.Enqueue(&job); err != nil {
+		return models.Job{}, err
+	}
+	return job, nil
+}
+
+// Start runs the workers and the scheduler in the background.
+func (r *Runner) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	r.wg.Add(r.workers + 1)
+	for range r.workers {
+		go func() {
+			defer r.wg.Done()
+			r.work(ctx)
+		}()
+	}
+	go func() {
+		defer r.wg.Done()
+		r.runSchedules(ctx)
+	}()
+}
+
+// Stop cancels the running jobs and waits for the workers to return.
+func (r *Runner) Stop() {
+	if r.cancel == nil {
+		return
+	}
+	r.cancel()
+	r.wg.Wait()
+}
+
+// work runs due jobs until ctx is done, polling while there are none.
+func (r *Runner) work(ctx context.Context) {
+	for ctx.Err() == nil {
+		job, err := r.repo.Claim(r.kinds, jobLease)
+		if err == nil {
+			r.run(ctx, job)
+			continue
+		}
+		if !errors.Is(err, repository.ErrRecordNotFound) {
+			log.Printf("jobs: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// run runs a claimed job, recording its outcome. A job interrupted by the
+// runner stopping is retried immediately by the next worker.
+func (r *Runner) run(ctx context.Context, job models.Job) {
+	registration := r.handlers[job.Kind]
+	err := runHandler(ctx, registration.handler, job)
+	if err == nil {
+		r.record(job, r.repo.Complete(job.ID, job.Attempts))
+		return
+	}
+
+	log.Printf("jobs: %s %s attempt %d: %v\n", job.Kind, job.ID, job.Attempts, err)
+	retryAt := time.Now().Add(backoff(job.Attempts))
+	if ctx.Err() != nil {
+		retryAt = time.Now()
+	}
+	final := job.Attempts >= job.MaxAttempts && ctx.Err() == nil
+	r.record(job, r.repo.Fail(job.ID, job.Attempts, err.Error(), retryAt, final))
+}
+
+// record logs a failure to record the outcome of a job's attempt.
+func (r *Runner) record(job models.Job, err error) {
+	switch {
+	case err == nil:
+	case errors.Is(err, repository.ErrRecordNotFound):
+		log.Printf("jobs: %s %s attempt %d lost its lease\n", job.Kind, job.ID, job.Attempts)
+	default:
+		log.Printf("jobs: %s %s: %v\n", job.Kind, job.ID, err)
+	}
+}
+
+// runSchedules enqueues the scheduled jobs as they become due, until ctx
+// is done.
+func (r *Runner) runSchedules(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, s := range r.schedule {
+			job := models.Job{
+				Kind:        s.kind,
+				MaxAttempts: r.handlers[s.kind].maxAttempts,
+			}
+			_, err := r.repo.EnqueueScheduled(s.kind, s.schedule.Next(time.Now()), &job)
+			if err != nil {
+				log.Printf("jobs: schedule %s: %v\n", s.kind, err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runHandler runs the handler, turning a panic into an error so that it
+// fails the job rather than the worker.
+func runHandler(ctx context.Context, handler Handler, job models.Job) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return handler(ctx, job)
+}
+
+// backoff returns the delay before retrying a job after the given number
+// of attempts, doubling from 30 seconds up to an hour.
+func backoff(attempts int) time.Duration {
+	delay := minBackoff
+	for i := 1; i < attempts && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
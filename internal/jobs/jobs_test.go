@@ -0,0 +1,190 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/repository"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 0, want: 30 * time.Second},
+		{attempts: 1, want: 30 * time.Second},
+		{attempts: 2, want: time.Minute},
+		{attempts: 3, want: 2 * time.Minute},
+		{attempts: 7, want: 32 * time.Minute},
+		{attempts: 8, want: time.Hour},
+		{attempts: 100, want: time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestEvery(t *testing.T) {
+	after := time.Date(2024, 3, 10, 23, 30, 0, 0, time.UTC)
+	want := time.Date(2024, 3, 11, 0, 30, 0, 0, time.UTC)
+	if got := Every(time.Hour).Next(after); !got.Equal(want) {
+		t.Errorf("Every(1h).Next(%v) = %v, want %v", after, got, want)
+	}
+}
+
+func TestDailyNext(t *testing.T) {
+	lagos := time.FixedZone("WAT", 60*60)
+
+	tests := []struct {
+		name   string
+		hour   int
+		minute int
+		after  time.Time
+		want   time.Time
+	}{
+		{
+			name:  "later today",
+			hour:  3,
+			after: time.Date(2024, 5, 1, 1, 0, 0, 0, time.UTC),
+			want:  time.Date(2024, 5, 1, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "passed today",
+			hour:  3,
+			after: time.Date(2024, 5, 1, 4, 0, 0, 0, time.UTC),
+			want:  time.Date(2024, 5, 2, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "exactly at the time",
+			hour:  3,
+			after: time.Date(2024, 5, 1, 3, 0, 0, 0, time.UTC),
+			want:  time.Date(2024, 5, 2, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "minutes",
+			hour:   3,
+			minute: 30,
+			after:  time.Date(2024, 5, 1, 3, 15, 0, 0, time.UTC),
+			want:   time.Date(2024, 5, 1, 3, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "end of month",
+			hour:  0,
+			after: time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC),
+			want:  time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "end of year",
+			hour:  2,
+			after: time.Date(2024, 12, 31, 23, 59, 0, 0, time.UTC),
+			want:  time.Date(2025, 1, 1, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "other time zone",
+			hour:  3,
+			after: time.Date(2024, 5, 1, 3, 30, 0, 0, lagos), // 02:30 UTC
+			want:  time.Date(2024, 5, 1, 3, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Daily(tt.hour, tt.minute).Next(tt.after)
+			if !got.Equal(tt.want) {
+				t.Errorf("Daily(%d, %d).Next(%v) = %v, want %v", tt.hour, tt.minute, tt.after, got, tt.want)
+			}
+		})
+	}
+}
+
+// outcomeRepo records the outcome of the attempts run.
+type outcomeRepo struct {
+	repository.JobRepository
+	completed bool
+	failed    bool
+	final     bool
+	retryAt   time.Time
+}
+
+func (r *outcomeRepo) Complete(id string, attempt int) error {
+	r.completed = true
+	return nil
+}
+
+func (r *outcomeRepo) Fail(id string, attempt int, lastError string, retryAt time.Time, final bool) error {
+	r.failed = true
+	r.final = final
+	r.retryAt = retryAt
+	return nil
+}
+
+func TestRun(t *testing.T) {
+	failing := func(ctx context.Context, job models.Job) error {
+		return errors.New("failed")
+	}
+
+	tests := []struct {
+		name      string
+		handler   Handler
+		attempts  int
+		stopped   bool
+		completed bool
+		final     bool
+		retryIn   time.Duration
+	}{
+		{
+			name:      "success",
+			handler:   func(ctx context.Context, job models.Job) error { return nil },
+			attempts:  1,
+			completed: true,
+		},
+		{name: "first failure", handler: failing, attempts: 1, retryIn: 30 * time.Second},
+		{name: "later failure", handler: failing, attempts: 2, retryIn: time.Minute},
+		{name: "last attempt", handler: failing, attempts: 3, final: true, retryIn: 2 * time.Minute},
+		{name: "stopped", handler: failing, attempts: 3, stopped: true},
+		{
+			name:     "panic",
+			handler:  func(ctx context.Context, job models.Job) error { panic("boom") },
+			attempts: 1,
+			retryIn:  30 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &outcomeRepo{}
+			runner := NewRunner(repo, 1)
+			runner.Register("test", 3, tt.handler)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.stopped {
+				cancel()
+			}
+
+			start := time.Now()
+			runner.run(ctx, models.Job{ID: "job", Kind: "test", Attempts: tt.attempts, MaxAttempts: 3})
+
+			if repo.completed != tt.completed || repo.failed == tt.completed {
+				t.Fatalf("completed = %v, failed = %v, want completed %v", repo.completed, repo.failed, tt.completed)
+			}
+			if tt.completed {
+				return
+			}
+			if repo.final != tt.final {
+				t.Errorf("final = %v, want %v", repo.final, tt.final)
+			}
+			retryIn := repo.retryAt.Sub(start)
+			if retryIn < tt.retryIn || retryIn > tt.retryIn+time.Second {
+				t.Errorf("retried in %v, want %v", retryIn, tt.retryIn)
+			}
+		})
+	}
+}
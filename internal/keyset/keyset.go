@@ -0,0 +1,322 @@
+// Package keyset holds the keys access tokens are signed and verified
+// with. One key signs new tokens, while previous keys stay available for
+// verification until the tokens they signed have expired, so that keys can
+// be rotated without signing everyone out. Public keys are published as a
+// JSON Web Key Set (RFC 7517) for other services to verify tokens with.
+package keyset
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// signing algorithms
+const (
+	ALG_RS256 = "RS256"
+	ALG_EDDSA = "EdDSA"
+	ALG_HS256 = "HS256"
+)
+
+var (
+	ErrUnsupportedKey = errors.New("unsupported key type, expected RSA or Ed25519")
+	ErrUnknownKey     = errors.New("unknown signing key")
+	ErrNoSigningKey   = errors.New("no private key to sign with")
+)
+
+// Key is a signing or verification key. Private is nil for keys that are
+// only used to verify tokens.
+type Key struct {
+	ID        string
+	Algorithm string
+	Private   crypto.PrivateKey
+	Public    crypto.PublicKey
+}
+
+// ParsePEM parses a PEM encoded RSA or Ed25519 key, private (PKCS #8 or
+// PKCS #1) or public (PKIX). The key ID is its RFC 7638 thumbprint.
+func ParsePEM(data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+
+	key := &Key{}
+	switch block.Type {
+	case "PRIVATE KEY":
+		private, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key.Private = private
+	case "RSA PRIVATE KEY":
+		private, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key.Private = private
+	case "PUBLIC KEY":
+		public, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key.Public = public
+	default:
+		return nil, fmt.Errorf("unexpected PEM block %q", block.Type)
+	}
+
+	switch private := key.Private.(type) {
+	case nil:
+	case *rsa.PrivateKey:
+		key.Public = &private.PublicKey
+	case ed25519.PrivateKey:
+		key.Public = private.Public()
+	default:
+		return nil, ErrUnsupportedKey
+	}
+
+	switch key.Public.(type) {
+	case *rsa.PublicKey:
+		key.Algorithm = ALG_RS256
+	case ed25519.PublicKey:
+		key.Algorithm = ALG_EDDSA
+	default:
+		return nil, ErrUnsupportedKey
+	}
+
+	jwk, err := key.JWK()
+	if err != nil {
+		return nil, err
+	}
+	key.ID, err = jwk.Thumbprint()
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// SigningMethod returns the JWT signing method of the key.
+func (k *Key) SigningMethod() jwt.SigningMethod {
+	switch k.Algorithm {
+	case ALG_RS256:
+		return jwt.SigningMethodRS256
+	case ALG_EDDSA:
+		return jwt.SigningMethodEdDSA
+	default:
+		return jwt.SigningMethodHS256
+	}
+}
+
+// KeySet is the set of keys tokens are signed and verified with.
+type KeySet struct {
+	signing *Key
+	keys    map[string]*Key
+	order   []string // signing key first
+
+	// secret signs and verifies HS256 tokens, without a key ID, when no
+	// signing key is configured.
+	secret []byte
+}
+
+// New returns a keyset signing with the signing key, and verifying with
+// it as well as the previous keys. Tokens are signed with the HS256
+// secret when signing is nil.
+func New(signing *Key, previous []*Key, secret string) (*KeySet, error) {
+	ks := &KeySet{
+		signing: signing,
+		keys:    map[string]*Key{},
+		secret:  []byte(secret),
+	}
+
+	if signing != nil {
+		if signing.Private == nil {
+			return nil, ErrNoSigningKey
+		}
+		ks.add(signing)
+	}
+	for _, key := range previous {
+		ks.add(key)
+	}
+
+	return ks, nil
+}
+
+func (ks *KeySet) add(key *Key) {
+	if _, ok := ks.keys[key.ID]; ok {
+		return
+	}
+	ks.keys[key.ID] = key
+	ks.order = append(ks.order, key.ID)
+}
+
+// Sign signs the claims with the current signing key.
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	if ks.signing == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(ks.secret)
+	}
+
+	token := jwt.NewWithClaims(ks.signing.SigningMethod(), claims)
+	token.Header["kid"] = ks.signing.ID
+	return token.SignedString(ks.signing.Private)
+}
+
+// Keyfunc selects the key verifying a token from its kid header. Tokens
+// without one are verified with the HS256 secret, unless a signing key is
+// configured.
+func (ks *KeySet) Keyfunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		if ks.signing != nil || token.Method.Alg() != ALG_HS256 {
+			return nil, ErrUnknownKey
+		}
+		return ks.secret, nil
+	}
+
+	key, ok := ks.keys[kid]
+	if !ok || token.Method.Alg() != key.Algorithm {
+		return nil, ErrUnknownKey
+	}
+	return key.Public, nil
+}
+
+// JWKS returns the public keys of the set.
+func (ks *KeySet) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+	for _, kid := range ks.order {
+		jwk, err := ks.keys[kid].JWK()
+		if err != nil {
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+// JWKSet is a JSON Web Key Set.
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK is the JSON Web Key of a public key.
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Kid string `json:"kid,omitempty"`
+
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// OKP
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWK returns the JSON Web Key of the key's public part.
+func (k *Key) JWK() (JWK, error) {
+	encode := base64.RawURLEncoding.EncodeToString
+
+	switch public := k.Public.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: k.Algorithm,
+			Kid: k.ID,
+			N:   encode(public.N.Bytes()),
+			E:   encode(big.NewInt(int64(public.E)).Bytes()),
+		}, nil
+	case ed25519.PublicKey:
+		return JWK{
+			Kty: "OKP",
+			Use: "sig",
+			Alg: k.Algorithm,
+			Kid: k.ID,
+			Crv: "Ed25519",
+			X:   encode(public),
+		}, nil
+	default:
+		return JWK{}, ErrUnsupportedKey
+	}
+}
+
+// Thumbprint returns the RFC 7638 thumbprint of the key.
+func (j JWK) Thumbprint() (string, error) {
+	// required members only, in lexicographic order
+	var members any
+	switch j.Kty {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{j.E, j.Kty, j.N}
+	case "OKP":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{j.Crv, j.Kty, j.X}
+	default:
+		return "", ErrUnsupportedKey
+	}
+
+	data, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+// PublicKey returns the public key a JSON Web Key describes, such as one
+// published by an identity provider to verify its tokens with.
+func (j JWK) PublicKey() (crypto.PublicKey, error) {
+	decode := base64.RawURLEncoding.DecodeString
+
+	switch j.Kty {
+	case "RSA":
+		n, err := decode(j.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decode(j.E)
+		if err != nil {
+			return nil, err
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(exponent.Int64()),
+		}, nil
+	case "OKP":
+		if j.Crv != "Ed25519" {
+			return nil, ErrUnsupportedKey
+		}
+		x, err := decode(j.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, ErrUnsupportedKey
+	}
+}
@@ -0,0 +1,153 @@
+// Package ledger builds the balanced journal entries posted for booking
+// payments, payouts and refunds.
+//
+// Amounts on postings are signed: a debit is positive and a credit is
+// negative, so the postings of a valid entry sum to zero. Money held for
+// users (wallets, earnings), in escrow and as commission are credit-normal
+// accounts, the Paystack clearing account is debit-normal.
+package ledger
+
+import (
+	"errors"
+
+	"github.com/lokatalent/backend_go/internal/models"
+)
+
+var (
+	ErrUnbalancedEntry = errors.New("ledger entry is not balanced")
+	ErrEmptyEntry      = errors.New("ledger entry has no postings")
+	ErrZeroPosting     = errors.New("ledger posting amount must not be zero")
+)
+
+// Debit returns a posting that debits amount to an account.
+func Debit(accountType, userID string, amount models.Money) models.LedgerPosting {
+	return models.LedgerPosting{
+		AccountType: accountType,
+		UserID:      userID,
+		Amount:      amount,
+	}
+}
+
+// Credit returns a posting that credits amount to an account.
+func Credit(accountType, userID string, amount models.Money) models.LedgerPosting {
+	return models.LedgerPosting{
+		AccountType: accountType,
+		UserID:      userID,
+		Amount:      amount.Neg(),
+	}
+}
+
+// Validate checks that an entry has postings, none of them zero, and that
+// they sum to zero to the kobo.
+func Validate(entry models.LedgerEntry) error {
+	if len(entry.Postings) == 0 {
+		return ErrEmptyEntry
+	}
+	var sum int64
+	for _, posting := range entry.Postings {
+		if posting.Amount.IsZero() {
+			return ErrZeroPosting
+		}
+		sum += posting.Amount.Minor()
+	}
+	if sum != 0 {
+		return ErrUnbalancedEntry
+	}
+	return nil
+}
+
+// IsDebitNormal reports whether an account type grows with debits.
+func IsDebitNormal(accountType string) bool {
+	return accountType == models.LEDGER_ACCOUNT_PAYSTACK_CLEARING
+}
+
+// Balance converts the signed sum of an account's postings into its
+// natural balance.
+func Balance(accountType string, sum models.Money) models.Money {
+	if IsDebitNormal(accountType) {
+		return sum
+	}
+	return sum.Neg()
+}
+
+// BookingCharge records a booking paid through Paystack: the charge is
+// credited to the requester's wallet and immediately spent into escrow.
+func BookingCharge(booking models.Booking, amount models.Money) models.LedgerEntry {
+	return newEntry(
+		"booking charge via paystack",
+		booking.ID,
+		Debit(models.LEDGER_ACCOUNT_PAYSTACK_CLEARING, "", amount),
+		Credit(models.LEDGER_ACCOUNT_REQUESTER_WALLET, booking.RequesterID, amount),
+		Debit(models.LEDGER_ACCOUNT_REQUESTER_WALLET, booking.RequesterID, amount),
+		Credit(models.LEDGER_ACCOUNT_BOOKING_ESCROW, "", amount),
+	)
+}
+
+// WalletPayment records a booking paid from the requester's wallet.
+func WalletPayment(booking models.Booking, amount models.Money) models.LedgerEntry {
+	return newEntry(
+		"booking payment from wallet",
+		booking.ID,
+		Debit(models.LEDGER_ACCOUNT_REQUESTER_WALLET, booking.RequesterID, amount),
+		Credit(models.LEDGER_ACCOUNT_BOOKING_ESCROW, "", amount),
+	)
+}
+
+// BookingSettlement releases a completed booking's escrow, splitting it
+// between the provider's earnings and the platform commission.
+func BookingSettlement(booking models.Booking) models.LedgerEntry {
+	postings := []models.LedgerPosting{
+		Debit(models.LEDGER_ACCOUNT_BOOKING_ESCROW, "", booking.TotalPrice),
+		Credit(models.LEDGER_ACCOUNT_PROVIDER_EARNINGS, booking.ProviderID.String, booking.ActualPrice),
+	}
+	if commission := booking.Commission(); !commission.IsZero() {
+		postings = append(
+			postings,
+			Credit(models.LEDGER_ACCOUNT_PLATFORM_COMMISSION, "", commission),
+		)
+	}
+	return newEntry("booking settlement", booking.ID, postings...)
+}
+
+// Payout records provider earnings transferred out through Paystack.
+func Payout(booking models.Booking, amount models.Money) models.LedgerEntry {
+	return newEntry(
+		"provider payout via paystack",
+		booking.ID,
+		Debit(models.LEDGER_ACCOUNT_PROVIDER_EARNINGS, booking.ProviderID.String, amount),
+		Credit(models.LEDGER_ACCOUNT_PAYSTACK_CLEARING, "", amount),
+	)
+}
+
+// PayoutReturned reverses a payout that failed or was reversed, returning
+// the amount to the provider's earnings.
+func PayoutReturned(booking models.Booking, amount models.Money) models.LedgerEntry {
+	return newEntry(
+		"provider payout returned",
+		booking.ID,
+		Debit(models.LEDGER_ACCOUNT_PAYSTACK_CLEARING, "", amount),
+		Credit(models.LEDGER_ACCOUNT_PROVIDER_EARNINGS, booking.ProviderID.String, amount),
+	)
+}
+
+// Refund returns a canceled booking's escrow to the requester's wallet.
+func Refund(booking models.Booking, amount models.Money) models.LedgerEntry {
+	return newEntry(
+		"booking refund to wallet",
+		booking.ID,
+		Debit(models.LEDGER_ACCOUNT_BOOKING_ESCROW, "", amount),
+		Credit(models.LEDGER_ACCOUNT_REQUESTER_WALLET, booking.RequesterID, amount),
+	)
+}
+
+func newEntry(description, bookingID string, postings ...models.LedgerPosting) models.LedgerEntry {
+	entry := models.LedgerEntry{
+		Description: description,
+		Postings:    postings,
+	}
+	if bookingID != "" {
+		entry.BookingID.String = bookingID
+		entry.BookingID.Valid = true
+	}
+	return entry
+}
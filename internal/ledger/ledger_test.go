@@ -0,0 +1,213 @@
+package ledger
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lokatalent/backend_go/internal/models"
+)
+
+func testBooking() models.Booking {
+	booking := models.Booking{
+		ID:          "booking",
+		RequesterID: "requester",
+		TotalPrice:  models.NGN(1150000),
+		ActualPrice: models.NGN(1000000),
+	}
+	booking.ProviderID.String = "provider"
+	booking.ProviderID.Valid = true
+	return booking
+}
+
+func TestValidate(t *testing.T) {
+	amount := models.NGN(5000)
+	tests := []struct {
+		name     string
+		postings []models.LedgerPosting
+		err      error
+	}{
+		{name: "no postings", err: ErrEmptyEntry},
+		{
+			name: "balanced",
+			postings: []models.LedgerPosting{
+				Debit(models.LEDGER_ACCOUNT_REQUESTER_WALLET, "requester", amount),
+				Credit(models.LEDGER_ACCOUNT_BOOKING_ESCROW, "", amount),
+			},
+		},
+		{
+			name: "unbalanced",
+			postings: []models.LedgerPosting{
+				Debit(models.LEDGER_ACCOUNT_REQUESTER_WALLET, "requester", amount),
+				Credit(models.LEDGER_ACCOUNT_BOOKING_ESCROW, "", models.NGN(4999)),
+			},
+			err: ErrUnbalancedEntry,
+		},
+		{
+			name: "one sided",
+			postings: []models.LedgerPosting{
+				Debit(models.LEDGER_ACCOUNT_REQUESTER_WALLET, "requester", amount),
+			},
+			err: ErrUnbalancedEntry,
+		},
+		{
+			name: "zero posting",
+			postings: []models.LedgerPosting{
+				Debit(models.LEDGER_ACCOUNT_REQUESTER_WALLET, "requester", amount),
+				Credit(models.LEDGER_ACCOUNT_BOOKING_ESCROW, "", amount),
+				Debit(models.LEDGER_ACCOUNT_PLATFORM_COMMISSION, "", models.NGN(0)),
+			},
+			err: ErrZeroPosting,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(models.LedgerEntry{Postings: tt.postings})
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Validate = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestEntriesBalance(t *testing.T) {
+	booking := testBooking()
+	noCommission := testBooking()
+	noCommission.ActualPrice = noCommission.TotalPrice
+
+	tests := []struct {
+		name     string
+		entry    models.LedgerEntry
+		postings int
+	}{
+		{name: "booking charge", entry: BookingCharge(booking, booking.TotalPrice), postings: 4},
+		{name: "wallet payment", entry: WalletPayment(booking, booking.TotalPrice), postings: 2},
+		{name: "settlement", entry: BookingSettlement(booking), postings: 3},
+		{name: "settlement without commission", entry: BookingSettlement(noCommission), postings: 2},
+		{name: "payout", entry: Payout(booking, booking.ActualPrice), postings: 2},
+		{name: "payout returned", entry: PayoutReturned(booking, booking.ActualPrice), postings: 2},
+		{name: "refund", entry: Refund(booking, booking.TotalPrice), postings: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Validate(tt.entry); err != nil {
+				t.Fatalf("Validate = %v", err)
+			}
+			if len(tt.entry.Postings) != tt.postings {
+				t.Errorf("got %d postings, want %d", len(tt.entry.Postings), tt.postings)
+			}
+			if !tt.entry.BookingID.Valid || tt.entry.BookingID.String != booking.ID {
+				t.Errorf("booking id = %v, want %s", tt.entry.BookingID, booking.ID)
+			}
+		})
+	}
+}
+
+func TestBalance(t *testing.T) {
+	tests := []struct {
+		account string
+		sum     int64
+		want    int64
+	}{
+		{account: models.LEDGER_ACCOUNT_PAYSTACK_CLEARING, sum: 500, want: 500},
+		{account: models.LEDGER_ACCOUNT_PAYSTACK_CLEARING, sum: -500, want: -500},
+		{account: models.LEDGER_ACCOUNT_REQUESTER_WALLET, sum: -500, want: 500},
+		{account: models.LEDGER_ACCOUNT_PROVIDER_EARNINGS, sum: -500, want: 500},
+		{account: models.LEDGER_ACCOUNT_PLATFORM_COMMISSION, sum: -500, want: 500},
+		{account: models.LEDGER_ACCOUNT_BOOKING_ESCROW, sum: 500, want: -500},
+	}
+
+	for _, tt := range tests {
+		got := Balance(tt.account, models.NGN(tt.sum))
+		if got.Minor() != tt.want {
+			t.Errorf("Balance(%s, %d) = %d, want %d", tt.account, tt.sum, got.Minor(), tt.want)
+		}
+	}
+}
+
+// TestBookingFlows posts the entries of a booking's life and checks the
+// balance each account is left with.
+func TestBookingFlows(t *testing.T) {
+	booking := testBooking()
+	commission := booking.TotalPrice.Sub(booking.ActualPrice)
+
+	tests := []struct {
+		name    string
+		entries []models.LedgerEntry
+		want    map[string]models.Money
+	}{
+		{
+			name: "charged, completed and paid out",
+			entries: []models.LedgerEntry{
+				BookingCharge(booking, booking.TotalPrice),
+				BookingSettlement(booking),
+				Payout(booking, booking.ActualPrice),
+			},
+			want: map[string]models.Money{
+				models.LEDGER_ACCOUNT_PAYSTACK_CLEARING:   commission,
+				models.LEDGER_ACCOUNT_REQUESTER_WALLET:    models.NGN(0),
+				models.LEDGER_ACCOUNT_BOOKING_ESCROW:      models.NGN(0),
+				models.LEDGER_ACCOUNT_PROVIDER_EARNINGS:   models.NGN(0),
+				models.LEDGER_ACCOUNT_PLATFORM_COMMISSION: commission,
+			},
+		},
+		{
+			name: "paid from wallet and completed",
+			entries: []models.LedgerEntry{
+				WalletPayment(booking, booking.TotalPrice),
+				BookingSettlement(booking),
+			},
+			want: map[string]models.Money{
+				models.LEDGER_ACCOUNT_REQUESTER_WALLET:    booking.TotalPrice.Neg(),
+				models.LEDGER_ACCOUNT_BOOKING_ESCROW:      models.NGN(0),
+				models.LEDGER_ACCOUNT_PROVIDER_EARNINGS:   booking.ActualPrice,
+				models.LEDGER_ACCOUNT_PLATFORM_COMMISSION: commission,
+			},
+		},
+		{
+			name: "charged and canceled",
+			entries: []models.LedgerEntry{
+				BookingCharge(booking, booking.TotalPrice),
+				Refund(booking, booking.TotalPrice),
+			},
+			want: map[string]models.Money{
+				models.LEDGER_ACCOUNT_PAYSTACK_CLEARING: booking.TotalPrice,
+				models.LEDGER_ACCOUNT_REQUESTER_WALLET:  booking.TotalPrice,
+				models.LEDGER_ACCOUNT_BOOKING_ESCROW:    models.NGN(0),
+			},
+		},
+		{
+			name: "payout returned",
+			entries: []models.LedgerEntry{
+				BookingCharge(booking, booking.TotalPrice),
+				BookingSettlement(booking),
+				Payout(booking, booking.ActualPrice),
+				PayoutReturned(booking, booking.ActualPrice),
+			},
+			want: map[string]models.Money{
+				models.LEDGER_ACCOUNT_PAYSTACK_CLEARING: booking.TotalPrice,
+				models.LEDGER_ACCOUNT_PROVIDER_EARNINGS: booking.ActualPrice,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sums := map[string]models.Money{}
+			for _, entry := range tt.entries {
+				if err := Validate(entry); err != nil {
+					t.Fatalf("Validate(%s) = %v", entry.Description, err)
+				}
+				for _, posting := range entry.Postings {
+					sums[posting.AccountType] = sums[posting.AccountType].Add(posting.Amount)
+				}
+			}
+			for account, want := range tt.want {
+				if got := Balance(account, sums[account]); !got.Equal(want) {
+					t.Errorf("%s balance = %s, want %s", account, got, want)
+				}
+			}
+		})
+	}
+}
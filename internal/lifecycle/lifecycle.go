@@ -0,0 +1,194 @@
+// Package lifecycle defines the booking state machine: the legal status
+// transitions, who may trigger each of them and the side effects that
+// follow. The happy path is open -> provider_selected -> accepted ->
+// in_progress -> completed. Bookings may also be canceled or disputed, and
+// return to open when the selected provider rejects them. Bookings not
+// accepted by their start time expire.
+package lifecycle
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/lokatalent/backend_go/internal/models"
+)
+
+// actions
+const (
+	ACTION_SELECT_PROVIDER = "select_provider"
+	ACTION_ACCEPT          = "accept"
+	ACTION_REJECT          = "reject"
+	ACTION_START           = "start"
+	ACTION_COMPLETE        = "complete"
+	ACTION_CANCEL          = "cancel"
+	ACTION_DISPUTE         = "dispute"
+	// ACTION_EXPIRE cancels a booking nobody accepted before its start.
+	ACTION_EXPIRE = "expire"
+)
+
+// actors
+const (
+	ACTOR_REQUESTER = "requester"
+	ACTOR_PROVIDER  = "provider"
+	ACTOR_ADMIN     = "admin"
+	ACTOR_SYSTEM    = "system"
+)
+
+// side effects
+const (
+	// EFFECT_REQUIRE_PAYMENT must succeed before the transition is applied.
+	EFFECT_REQUIRE_PAYMENT = "require_payment"
+
+	EFFECT_PAY_PROVIDER     = "pay_provider"
+	EFFECT_REFUND_REQUESTER = "refund_requester"
+	EFFECT_NOTIFY_REQUESTER = "notify_requester"
+	EFFECT_NOTIFY_PROVIDER  = "notify_provider"
+)
+
+var (
+	ErrUnknownAction     = errors.New("unknown booking action")
+	ErrInvalidTransition = errors.New("action not allowed in current booking status")
+	ErrActorNotAllowed   = errors.New("actor not allowed to perform booking action")
+)
+
+// Transition is a legal status change triggered by Action.
+type Transition struct {
+	Action  string
+	From    []string
+	To      string
+	Actors  []string
+	Effects []string
+}
+
+// HasEffect reports whether the transition has a side effect.
+func (t Transition) HasEffect(effect string) bool {
+	return slices.Contains(t.Effects, effect)
+}
+
+// StateMachine looks up the transition for an action.
+type StateMachine struct {
+	transitions []Transition
+}
+
+func NewStateMachine(transitions ...Transition) *StateMachine {
+	return &StateMachine{transitions: transitions}
+}
+
+// Booking is the booking lifecycle.
+var Booking = NewStateMachine(
+	Transition{
+		Action:  ACTION_SELECT_PROVIDER,
+		From:    []string{models.BOOKING_OPEN},
+		To:      models.BOOKING_PROVIDER_SELECTED,
+		Actors:  []string{ACTOR_REQUESTER},
+		Effects: []string{EFFECT_REQUIRE_PAYMENT, EFFECT_NOTIFY_PROVIDER},
+	},
+	Transition{
+		Action:  ACTION_ACCEPT,
+		From:    []string{models.BOOKING_PROVIDER_SELECTED},
+		To:      models.BOOKING_ACCEPTED,
+		Actors:  []string{ACTOR_PROVIDER},
+		Effects: []string{EFFECT_NOTIFY_REQUESTER},
+	},
+	Transition{
+		Action:  ACTION_REJECT,
+		From:    []string{models.BOOKING_PROVIDER_SELECTED},
+		To:      models.BOOKING_OPEN,
+		Actors:  []string{ACTOR_PROVIDER},
+		Effects: []string{EFFECT_NOTIFY_REQUESTER},
+	},
+	Transition{
+		Action:  ACTION_START,
+		From:    []string{models.BOOKING_ACCEPTED},
+		To:      models.BOOKING_IN_PROGRESS,
+		Actors:  []string{ACTOR_PROVIDER, ACTOR_SYSTEM},
+		Effects: []string{EFFECT_NOTIFY_REQUESTER},
+	},
+	Transition{
+		Action:  ACTION_COMPLETE,
+		From:    []string{models.BOOKING_IN_PROGRESS},
+		To:      models.BOOKING_COMPLETED,
+		Actors:  []string{ACTOR_REQUESTER, ACTOR_ADMIN},
+		Effects: []string{EFFECT_PAY_PROVIDER, EFFECT_NOTIFY_PROVIDER},
+	},
+	Transition{
+		Action:  ACTION_COMPLETE,
+		From:    []string{models.BOOKING_DISPUTED},
+		To:      models.BOOKING_COMPLETED,
+		Actors:  []string{ACTOR_ADMIN},
+		Effects: []string{EFFECT_PAY_PROVIDER, EFFECT_NOTIFY_REQUESTER, EFFECT_NOTIFY_PROVIDER},
+	},
+	Transition{
+		Action: ACTION_CANCEL,
+		From: []string{
+			models.BOOKING_OPEN,
+			models.BOOKING_PROVIDER_SELECTED,
+			models.BOOKING_ACCEPTED,
+		},
+		To:      models.BOOKING_CANCELED,
+		Actors:  []string{ACTOR_REQUESTER, ACTOR_ADMIN, ACTOR_SYSTEM},
+		Effects: []string{EFFECT_REFUND_REQUESTER, EFFECT_NOTIFY_PROVIDER},
+	},
+	Transition{
+		Action:  ACTION_CANCEL,
+		From:    []string{models.BOOKING_IN_PROGRESS, models.BOOKING_DISPUTED},
+		To:      models.BOOKING_CANCELED,
+		Actors:  []string{ACTOR_ADMIN},
+		Effects: []string{EFFECT_REFUND_REQUESTER, EFFECT_NOTIFY_REQUESTER, EFFECT_NOTIFY_PROVIDER},
+	},
+	Transition{
+		Action:  ACTION_EXPIRE,
+		From:    []string{models.BOOKING_OPEN, models.BOOKING_PROVIDER_SELECTED},
+		To:      models.BOOKING_CANCELED,
+		Actors:  []string{ACTOR_SYSTEM},
+		Effects: []string{EFFECT_REFUND_REQUESTER, EFFECT_NOTIFY_REQUESTER, EFFECT_NOTIFY_PROVIDER},
+	},
+	Transition{
+		Action:  ACTION_DISPUTE,
+		From:    []string{models.BOOKING_IN_PROGRESS},
+		To:      models.BOOKING_DISPUTED,
+		Actors:  []string{ACTOR_REQUESTER, ACTOR_PROVIDER},
+		Effects: []string{EFFECT_NOTIFY_REQUESTER, EFFECT_NOTIFY_PROVIDER},
+	},
+)
+
+// Find returns the transition for action from status, performed by the
+// first of actors allowed to trigger it, along with that actor.
+func (m *StateMachine) Find(action, status string, actors ...string) (Transition, string, error) {
+	known := false
+	for _, transition := range m.transitions {
+		if transition.Action != action {
+			continue
+		}
+		known = true
+		if !slices.Contains(transition.From, status) {
+			continue
+		}
+		for _, actor := range actors {
+			if slices.Contains(transition.Actors, actor) {
+				return transition, actor, nil
+			}
+		}
+		return Transition{}, "", ErrActorNotAllowed
+	}
+
+	if !known {
+		return Transition{}, "", ErrUnknownAction
+	}
+	return Transition{}, "", ErrInvalidTransition
+}
+
+// ActionFor returns the action that moves a booking to status, for
+// clients that request a status rather than an action.
+func ActionFor(status string) (string, bool) {
+	switch status {
+	case models.BOOKING_COMPLETED:
+		return ACTION_COMPLETE, true
+	case models.BOOKING_CANCELED:
+		return ACTION_CANCEL, true
+	case models.BOOKING_DISPUTED:
+		return ACTION_DISPUTE, true
+	default:
+		return "", false
+	}
+}
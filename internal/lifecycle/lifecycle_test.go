@@ -0,0 +1,197 @@
+package lifecycle
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/lokatalent/backend_go/internal/models"
+)
+
+type findCase struct {
+	action string
+	status string
+	actors []string
+	to     string
+	actor  string
+	err    error
+}
+
+func runFindCases(t *testing.T, tests []findCase) {
+	t.Helper()
+	for _, tt := range tests {
+		transition, actor, err := Booking.Find(tt.action, tt.status, tt.actors...)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("Find(%s, %s, %v) error = %v, want %v", tt.action, tt.status, tt.actors, err, tt.err)
+			continue
+		}
+		if tt.err != nil {
+			continue
+		}
+		if transition.To != tt.to {
+			t.Errorf("Find(%s, %s, %v) to = %s, want %s", tt.action, tt.status, tt.actors, transition.To, tt.to)
+		}
+		if actor != tt.actor {
+			t.Errorf("Find(%s, %s, %v) actor = %s, want %s", tt.action, tt.status, tt.actors, actor, tt.actor)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	requester := []string{ACTOR_REQUESTER}
+	provider := []string{ACTOR_PROVIDER}
+	admin := []string{ACTOR_ADMIN}
+	system := []string{ACTOR_SYSTEM}
+
+	runFindCases(t, []findCase{
+		// happy path
+		{action: ACTION_SELECT_PROVIDER, status: models.BOOKING_OPEN, actors: requester, to: models.BOOKING_PROVIDER_SELECTED, actor: ACTOR_REQUESTER},
+		{action: ACTION_ACCEPT, status: models.BOOKING_PROVIDER_SELECTED, actors: provider, to: models.BOOKING_ACCEPTED, actor: ACTOR_PROVIDER},
+		{action: ACTION_START, status: models.BOOKING_ACCEPTED, actors: provider, to: models.BOOKING_IN_PROGRESS, actor: ACTOR_PROVIDER},
+		{action: ACTION_START, status: models.BOOKING_ACCEPTED, actors: system, to: models.BOOKING_IN_PROGRESS, actor: ACTOR_SYSTEM},
+		{action: ACTION_COMPLETE, status: models.BOOKING_IN_PROGRESS, actors: requester, to: models.BOOKING_COMPLETED, actor: ACTOR_REQUESTER},
+		{action: ACTION_COMPLETE, status: models.BOOKING_IN_PROGRESS, actors: admin, to: models.BOOKING_COMPLETED, actor: ACTOR_ADMIN},
+
+		// rejection returns the booking to open
+		{action: ACTION_REJECT, status: models.BOOKING_PROVIDER_SELECTED, actors: provider, to: models.BOOKING_OPEN, actor: ACTOR_PROVIDER},
+		{action: ACTION_REJECT, status: models.BOOKING_PROVIDER_SELECTED, actors: requester, err: ErrActorNotAllowed},
+		{action: ACTION_REJECT, status: models.BOOKING_ACCEPTED, actors: provider, err: ErrInvalidTransition},
+
+		// cancellation
+		{action: ACTION_CANCEL, status: models.BOOKING_OPEN, actors: requester, to: models.BOOKING_CANCELED, actor: ACTOR_REQUESTER},
+		{action: ACTION_CANCEL, status: models.BOOKING_PROVIDER_SELECTED, actors: requester, to: models.BOOKING_CANCELED, actor: ACTOR_REQUESTER},
+		{action: ACTION_CANCEL, status: models.BOOKING_ACCEPTED, actors: system, to: models.BOOKING_CANCELED, actor: ACTOR_SYSTEM},
+		{action: ACTION_CANCEL, status: models.BOOKING_ACCEPTED, actors: provider, err: ErrActorNotAllowed},
+		{action: ACTION_CANCEL, status: models.BOOKING_IN_PROGRESS, actors: requester, err: ErrActorNotAllowed},
+		{action: ACTION_CANCEL, status: models.BOOKING_IN_PROGRESS, actors: admin, to: models.BOOKING_CANCELED, actor: ACTOR_ADMIN},
+		{action: ACTION_CANCEL, status: models.BOOKING_DISPUTED, actors: admin, to: models.BOOKING_CANCELED, actor: ACTOR_ADMIN},
+		{action: ACTION_CANCEL, status: models.BOOKING_COMPLETED, actors: admin, err: ErrInvalidTransition},
+		{action: ACTION_CANCEL, status: models.BOOKING_CANCELED, actors: admin, err: ErrInvalidTransition},
+
+		// disputes
+		{action: ACTION_DISPUTE, status: models.BOOKING_IN_PROGRESS, actors: provider, to: models.BOOKING_DISPUTED, actor: ACTOR_PROVIDER},
+		{action: ACTION_DISPUTE, status: models.BOOKING_IN_PROGRESS, actors: admin, err: ErrActorNotAllowed},
+		{action: ACTION_DISPUTE, status: models.BOOKING_COMPLETED, actors: requester, err: ErrInvalidTransition},
+		{action: ACTION_COMPLETE, status: models.BOOKING_DISPUTED, actors: requester, err: ErrActorNotAllowed},
+		{action: ACTION_COMPLETE, status: models.BOOKING_DISPUTED, actors: admin, to: models.BOOKING_COMPLETED, actor: ACTOR_ADMIN},
+
+		// out of order
+		{action: ACTION_ACCEPT, status: models.BOOKING_OPEN, actors: provider, err: ErrInvalidTransition},
+		{action: ACTION_START, status: models.BOOKING_PROVIDER_SELECTED, actors: provider, err: ErrInvalidTransition},
+		{action: ACTION_COMPLETE, status: models.BOOKING_ACCEPTED, actors: requester, err: ErrInvalidTransition},
+		{action: ACTION_SELECT_PROVIDER, status: models.BOOKING_PROVIDER_SELECTED, actors: requester, err: ErrInvalidTransition},
+
+		// actors
+		{action: ACTION_ACCEPT, status: models.BOOKING_PROVIDER_SELECTED, actors: admin, err: ErrActorNotAllowed},
+		{action: ACTION_ACCEPT, status: models.BOOKING_PROVIDER_SELECTED, err: ErrActorNotAllowed},
+		{
+			action: ACTION_COMPLETE,
+			status: models.BOOKING_IN_PROGRESS,
+			actors: []string{ACTOR_PROVIDER, ACTOR_ADMIN},
+			to:     models.BOOKING_COMPLETED,
+			actor:  ACTOR_ADMIN,
+		},
+		{
+			action: ACTION_CANCEL,
+			status: models.BOOKING_OPEN,
+			actors: []string{ACTOR_REQUESTER, ACTOR_ADMIN},
+			to:     models.BOOKING_CANCELED,
+			actor:  ACTOR_REQUESTER,
+		},
+
+		{action: "archive", status: models.BOOKING_OPEN, actors: admin, err: ErrUnknownAction},
+	})
+}
+
+func TestFindExpire(t *testing.T) {
+	system := []string{ACTOR_SYSTEM}
+
+	runFindCases(t, []findCase{
+		{action: ACTION_EXPIRE, status: models.BOOKING_OPEN, actors: system, to: models.BOOKING_CANCELED, actor: ACTOR_SYSTEM},
+		{action: ACTION_EXPIRE, status: models.BOOKING_PROVIDER_SELECTED, actors: system, to: models.BOOKING_CANCELED, actor: ACTOR_SYSTEM},
+		{action: ACTION_EXPIRE, status: models.BOOKING_ACCEPTED, actors: system, err: ErrInvalidTransition},
+		{action: ACTION_EXPIRE, status: models.BOOKING_OPEN, actors: []string{ACTOR_ADMIN}, err: ErrActorNotAllowed},
+	})
+}
+
+func TestEffects(t *testing.T) {
+	tests := []struct {
+		action  string
+		status  string
+		actor   string
+		effects []string
+	}{
+		{
+			action:  ACTION_SELECT_PROVIDER,
+			status:  models.BOOKING_OPEN,
+			actor:   ACTOR_REQUESTER,
+			effects: []string{EFFECT_REQUIRE_PAYMENT, EFFECT_NOTIFY_PROVIDER},
+		},
+		{
+			action:  ACTION_COMPLETE,
+			status:  models.BOOKING_IN_PROGRESS,
+			actor:   ACTOR_REQUESTER,
+			effects: []string{EFFECT_PAY_PROVIDER, EFFECT_NOTIFY_PROVIDER},
+		},
+		{
+			action:  ACTION_COMPLETE,
+			status:  models.BOOKING_DISPUTED,
+			actor:   ACTOR_ADMIN,
+			effects: []string{EFFECT_PAY_PROVIDER, EFFECT_NOTIFY_REQUESTER, EFFECT_NOTIFY_PROVIDER},
+		},
+		{
+			action:  ACTION_CANCEL,
+			status:  models.BOOKING_ACCEPTED,
+			actor:   ACTOR_REQUESTER,
+			effects: []string{EFFECT_REFUND_REQUESTER, EFFECT_NOTIFY_PROVIDER},
+		},
+		{
+			action:  ACTION_CANCEL,
+			status:  models.BOOKING_IN_PROGRESS,
+			actor:   ACTOR_ADMIN,
+			effects: []string{EFFECT_REFUND_REQUESTER, EFFECT_NOTIFY_REQUESTER, EFFECT_NOTIFY_PROVIDER},
+		},
+	}
+
+	all := []string{
+		EFFECT_REQUIRE_PAYMENT,
+		EFFECT_PAY_PROVIDER,
+		EFFECT_REFUND_REQUESTER,
+		EFFECT_NOTIFY_REQUESTER,
+		EFFECT_NOTIFY_PROVIDER,
+	}
+	for _, tt := range tests {
+		transition, _, err := Booking.Find(tt.action, tt.status, tt.actor)
+		if err != nil {
+			t.Fatalf("Find(%s, %s, %s): %v", tt.action, tt.status, tt.actor, err)
+		}
+		for _, effect := range all {
+			want := slices.Contains(tt.effects, effect)
+			if got := transition.HasEffect(effect); got != want {
+				t.Errorf("%s from %s HasEffect(%s) = %v, want %v", tt.action, tt.status, effect, got, want)
+			}
+		}
+	}
+}
+
+func TestActionFor(t *testing.T) {
+	tests := []struct {
+		status string
+		action string
+		ok     bool
+	}{
+		{status: models.BOOKING_COMPLETED, action: ACTION_COMPLETE, ok: true},
+		{status: models.BOOKING_CANCELED, action: ACTION_CANCEL, ok: true},
+		{status: models.BOOKING_DISPUTED, action: ACTION_DISPUTE, ok: true},
+		{status: models.BOOKING_ACCEPTED},
+		{status: models.BOOKING_OPEN},
+		{status: "unknown"},
+	}
+
+	for _, tt := range tests {
+		action, ok := ActionFor(tt.status)
+		if action != tt.action || ok != tt.ok {
+			t.Errorf("ActionFor(%s) = %s, %v, want %s, %v", tt.status, action, ok, tt.action, tt.ok)
+		}
+	}
+}
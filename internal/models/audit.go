@@ -0,0 +1,22 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// AuditLog records a privileged action: who did it, to what, and the
+// fields it changed. Entries are never updated or deleted.
+type AuditLog struct {
+	ID         string          `json:"id"`
+	ActorID    sql.NullString  `json:"actor_id"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	IPAddress  string          `json:"ip_address"`
+	UserAgent  string          `json:"user_agent"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
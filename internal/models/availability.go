@@ -0,0 +1,230 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// TIME_OF_DAY_LAYOUT is the layout of TimeRange bounds. "24:00" is also
+// accepted as the end of a day.
+const TIME_OF_DAY_LAYOUT = "15:04"
+
+const END_OF_DAY = "24:00"
+
+var ErrInvalidAvailability = errors.New("invalid availability")
+
+// TimeRange represents available hours within a day, in the provider's
+// timezone.
+type TimeRange struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// TimeRanges are the available hours for a day. Schedules saved before
+// several ranges were supported hold a single range, which is decoded
+// as a one element list.
+type TimeRanges []TimeRange
+
+// Availability represents weekly availability schedule
+type Availability struct {
+	Timezone  string     `json:"timezone"`
+	Monday    TimeRanges `json:"monday"`
+	Tuesday   TimeRanges `json:"tuesday"`
+	Wednesday TimeRanges `json:"wednesday"`
+	Thursday  TimeRanges `json:"thursday"`
+	Friday    TimeRanges `json:"friday"`
+	Saturday  TimeRanges `json:"saturday"`
+	Sunday    TimeRanges `json:"sunday"`
+}
+
+// AvailabilityException overrides a provider's weekly availability
+// between two instants: a blackout removes availability, e.g. holidays,
+// and an extra exception adds to it.
+type AvailabilityException struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Kind      string    `json:"kind"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Minutes returns the range as minutes since midnight.
+func (t TimeRange) Minutes() (int, int, error) {
+	start, err := parseTimeOfDay(t.Start)
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := parseTimeOfDay(t.End)
+	if err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+func (r *TimeRanges) UnmarshalJSON(data []byte) error {
+	data = []byte(strings.TrimSpace(string(data)))
+	if len(data) > 0 && data[0] == '{' {
+		single := TimeRange{}
+		if err := json.Unmarshal(data, &single); err != nil {
+			return err
+		}
+		*r = TimeRanges{}
+		if single.Start != "" || single.End != "" {
+			*r = append(*r, single)
+		}
+		return nil
+	}
+
+	ranges := []TimeRange{}
+	if err := json.Unmarshal(data, &ranges); err != nil {
+		return err
+	}
+	*r = ranges
+	return nil
+}
+
+// Day returns the ranges for a day of the week.
+func (a Availability) Day(day time.Weekday) TimeRanges {
+	switch day {
+	case time.Monday:
+		return a.Monday
+	case time.Tuesday:
+		return a.Tuesday
+	case time.Wednesday:
+		return a.Wednesday
+	case time.Thursday:
+		return a.Thursday
+	case time.Friday:
+		return a.Friday
+	case time.Saturday:
+		return a.Saturday
+	default:
+		return a.Sunday
+	}
+}
+
+// Location returns the schedule's timezone, DEFAULT_TIMEZONE if unset.
+func (a Availability) Location() (*time.Location, error) {
+	timezone := a.Timezone
+	if timezone == "" {
+		timezone = DEFAULT_TIMEZONE
+	}
+	return time.LoadLocation(timezone)
+}
+
+// Validate checks the timezone and that every range is well formed and
+// does not overlap another range on the same day.
+func (a Availability) Validate() error {
+	if _, err := a.Location(); err != nil {
+		return fmt.Errorf("%w: unknown timezone %q", ErrInvalidAvailability, a.Timezone)
+	}
+
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		type span struct{ start, end int }
+		spans := []span{}
+		for _, timeRange := range a.Day(day) {
+			start, end, err := timeRange.Minutes()
+			if err != nil {
+				return fmt.Errorf("%w: %s: %v", ErrInvalidAvailability, strings.ToLower(day.String()), err)
+			}
+			if start >= end {
+				return fmt.Errorf(
+					"%w: %s: range %s-%s ends before it starts",
+					ErrInvalidAvailability,
+					strings.ToLower(day.String()),
+					timeRange.Start,
+					timeRange.End,
+				)
+			}
+			spans = append(spans, span{start, end})
+		}
+
+		slices.SortFunc(spans, func(x, y span) int { return x.start - y.start })
+		for i := 1; i < len(spans); i++ {
+			if spans[i].start < spans[i-1].end {
+				return fmt.Errorf(
+					"%w: %s: overlapping ranges",
+					ErrInvalidAvailability,
+					strings.ToLower(day.String()),
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Normalize sets the default timezone and replaces missing days with
+// empty lists, so the stored schedule always has every key.
+func (a *Availability) Normalize() {
+	if a.Timezone == "" {
+		a.Timezone = DEFAULT_TIMEZONE
+	}
+	for _, day := range []*TimeRanges{
+		&a.Monday, &a.Tuesday, &a.Wednesday, &a.Thursday,
+		&a.Friday, &a.Saturday, &a.Sunday,
+	} {
+		if *day == nil {
+			*day = TimeRanges{}
+		}
+	}
+}
+
+// Implement sql.Scanner interface for Availability to handle JSONB
+func (a *Availability) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("error parsing service availability.")
+	}
+
+	return json.Unmarshal(bytes, &a)
+}
+
+// Implement driver.Valuer interface for Availability to save to JSONB
+func (a Availability) Value() (driver.Value, error) {
+	return json.Marshal(a)
+}
+
+// Validate checks the kind and that the exception ends after it starts.
+func (e AvailabilityException) Validate() error {
+	switch e.Kind {
+	case AVAILABILITY_BLACKOUT, AVAILABILITY_EXTRA:
+	default:
+		return fmt.Errorf("%w: unknown exception kind %q", ErrInvalidAvailability, e.Kind)
+	}
+	if !e.EndsAt.After(e.StartsAt) {
+		return fmt.Errorf("%w: exception ends before it starts", ErrInvalidAvailability)
+	}
+	if e.EndsAt.Sub(e.StartsAt) > MAX_AVAILABILITY_EXCEPTION_DAYS*24*time.Hour {
+		return fmt.Errorf(
+			"%w: exception longer than %d days",
+			ErrInvalidAvailability,
+			MAX_AVAILABILITY_EXCEPTION_DAYS,
+		)
+	}
+	return nil
+}
+
+// parseTimeOfDay parses "HH:MM" into minutes since midnight.
+func parseTimeOfDay(value string) (int, error) {
+	if value == END_OF_DAY {
+		return 24 * 60, nil
+	}
+	parsed, err := time.Parse(TIME_OF_DAY_LAYOUT, value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
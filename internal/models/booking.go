@@ -6,20 +6,40 @@ import (
 )
 
 type Booking struct {
-	ID            string         `json:"id"`
-	RequesterID   string         `json:"requester_id"`
-	ProviderID    sql.NullString `json:"provider_id"`
-	RequesterAddr string         `json:"requester_addr"`
-	ServiceType   string         `json:"service_type"`
-	BookingType   string         `json:"booking_type"`
-	ServiceDesc   string         `json:"service_desc"`
-	StartTime     time.Time      `json:"start_time"`
-	EndTime       time.Time      `json:"end_time"`
-	StartDate     time.Time      `json:"start_date"`
-	EndDate       time.Time      `json:"end_date"`
-	TotalPrice    float64        `json:"total_price"`
-	ActualPrice   float64        `json:"actual_price"`
-	Status        string         `json:"status"`
-	CreatedAt     time.Time      `json:"created_at"`
-	UpdatedAt     time.Time      `json:"updated_at"`
+	ID                string         `json:"id"`
+	RequesterID       string         `json:"requester_id"`
+	ProviderID        sql.NullString `json:"provider_id"`
+	RequesterAddr     string         `json:"requester_addr"`
+	RequesterLocation Location       `json:"requester_location"`
+	ServiceType       string         `json:"service_type"`
+	BookingType       string         `json:"booking_type"`
+	ServiceDesc       string         `json:"service_desc"`
+	StartTime         time.Time      `json:"start_time"`
+	EndTime           time.Time      `json:"end_time"`
+	StartDate         time.Time      `json:"start_date"`
+	EndDate           time.Time      `json:"end_date"`
+	TotalPrice        Money          `json:"total_price"`
+	ActualPrice       Money          `json:"actual_price"`
+	Status            string         `json:"status"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+}
+
+// Commission returns the platform's share of the booking price, the
+// difference between the total and the provider's actual price.
+func (b Booking) Commission() Money {
+	return b.TotalPrice.Sub(b.ActualPrice)
+}
+
+// BookingEvent records a booking status transition and who triggered it.
+type BookingEvent struct {
+	ID         string         `json:"id"`
+	BookingID  string         `json:"booking_id"`
+	Action     string         `json:"action"`
+	Actor      string         `json:"actor"`
+	ActorID    sql.NullString `json:"actor_id"`
+	FromStatus string         `json:"from_status"`
+	ToStatus   string         `json:"to_status"`
+	ProviderID sql.NullString `json:"provider_id"`
+	CreatedAt  time.Time      `json:"created_at"`
 }
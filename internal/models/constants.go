@@ -12,6 +12,13 @@ const (
 	PHONE_VERIFICATION = "phone"
 )
 
+// third-party sign-in providers
+const (
+	IDENTITY_GOOGLE   = "google"
+	IDENTITY_FACEBOOK = "facebook"
+	IDENTITY_APPLE    = "apple"
+)
+
 // user service roles
 const (
 	SERVICE_PROVIDER  = "service_provider"
@@ -19,6 +26,30 @@ const (
 	SERVICE_BOTH      = "service_both"
 )
 
+// KYC submission statuses
+const (
+	KYC_PENDING            = "pending"
+	KYC_APPROVED           = "approved"
+	KYC_REJECTED           = "rejected"
+	KYC_NEEDS_RESUBMISSION = "needs_resubmission"
+)
+
+// KYC identity document types
+const (
+	KYC_DOCUMENT_PASSPORT        = "passport"
+	KYC_DOCUMENT_NATIONAL_ID     = "national_id"
+	KYC_DOCUMENT_DRIVERS_LICENSE = "drivers_license"
+	KYC_DOCUMENT_VOTERS_CARD     = "voters_card"
+)
+
+// certification statuses
+const (
+	CERTIFICATION_PENDING  = "pending"
+	CERTIFICATION_APPROVED = "approved"
+	CERTIFICATION_REJECTED = "rejected"
+	CERTIFICATION_EXPIRED  = "expired"
+)
+
 // user service types
 const (
 	SERVICE_CLEANING = "cleaning"
@@ -29,21 +60,54 @@ const (
 // bookings
 const (
 	// booking status
-	BOOKING_OPEN        = "open"
-	BOOKING_COMPLETED   = "completed"
-	BOOKING_IN_PROGRESS = "in_progress"
-	BOOKING_CANCELED    = "canceled"
-	BOOKING_ACCEPT      = "accept"
-	BOOKING_REJECT      = "reject"
+	BOOKING_OPEN              = "open"
+	BOOKING_PROVIDER_SELECTED = "provider_selected"
+	BOOKING_ACCEPTED          = "accepted"
+	BOOKING_IN_PROGRESS       = "in_progress"
+	BOOKING_COMPLETED         = "completed"
+	BOOKING_CANCELED          = "canceled"
+	BOOKING_DISPUTED          = "disputed"
 
 	// booking type
 	BOOKING_INSTANT   = "instant"
 	BOOKING_SCHEDULED = "scheduled"
 )
 
+// availability
+const (
+	// timezone of availability schedules saved without one.
+	DEFAULT_TIMEZONE = "Africa/Lagos"
+
+	// availability exception kinds
+	AVAILABILITY_BLACKOUT = "blackout"
+	AVAILABILITY_EXTRA    = "available"
+
+	MAX_AVAILABILITY_EXCEPTION_DAYS = 90
+)
+
+// reviews
+const (
+	// reviewer roles
+	REVIEWER_REQUESTER = "requester"
+	REVIEWER_PROVIDER  = "provider"
+)
+
 // notifications
 const (
 	NOTIFICATION_TYPE_BOOKING = "booking"
+	NOTIFICATION_TYPE_KYC     = "kyc"
+
+	NOTIFICATION_TYPE_CERTIFICATION = "certification"
+
+	// channels
+	NOTIFICATION_CHANNEL_IN_APP = "in_app"
+	NOTIFICATION_CHANNEL_EMAIL  = "email"
+	NOTIFICATION_CHANNEL_SMS    = "sms"
+	NOTIFICATION_CHANNEL_PUSH   = "push"
+
+	// push device platforms
+	PUSH_PLATFORM_ANDROID = "android"
+	PUSH_PLATFORM_IOS     = "ios"
 )
 
 // payment
@@ -57,9 +121,43 @@ const (
 	PAYMENT_STATUS_PENDING  = "pending"
 	PAYMENT_STATUS_CANCELED = "canceled"
 	PAYMENT_STATUS_VERIFIED = "verified"
+	PAYMENT_STATUS_FAILED   = "failed"
+	PAYMENT_STATUS_REVERSED = "reversed"
+)
+
+// ledger account types
+const (
+	LEDGER_ACCOUNT_REQUESTER_WALLET    = "requester_wallet"
+	LEDGER_ACCOUNT_PROVIDER_EARNINGS   = "provider_earnings"
+	LEDGER_ACCOUNT_PLATFORM_COMMISSION = "platform_commission"
+	LEDGER_ACCOUNT_PAYSTACK_CLEARING   = "paystack_clearing"
+	LEDGER_ACCOUNT_BOOKING_ESCROW      = "booking_escrow"
 )
 
 const (
 	DefaultPage      = 1
 	DefaultPageLimit = 10
 )
+
+// outbox
+const (
+	// kinds
+	OUTBOX_KIND_NOTIFICATION = "notification"
+	OUTBOX_KIND_EMAIL        = "email"
+	OUTBOX_KIND_SMS          = "sms"
+	OUTBOX_KIND_PUSH         = "push"
+	OUTBOX_KIND_TRANSFER     = "transfer"
+
+	// statuses
+	OUTBOX_PENDING   = "pending"
+	OUTBOX_DELIVERED = "delivered"
+	OUTBOX_DEAD      = "dead"
+)
+
+// job statuses
+const (
+	JOB_PENDING   = "pending"
+	JOB_RUNNING   = "running"
+	JOB_COMPLETED = "completed"
+	JOB_FAILED    = "failed"
+)
@@ -1,6 +1,6 @@
 package models
 
-// import "time"
+import "time"
 
 type Filter struct {
 	FirstName   string
@@ -38,8 +38,9 @@ type NotificationFilter struct {
 }
 
 type ServiceFilter struct {
-	Page  int
-	Limit int
+	RadiusKm float64
+	Page     int
+	Limit    int
 }
 
 type PaymentFilter struct {
@@ -52,6 +53,63 @@ type PaymentFilter struct {
 	Limit      int
 }
 
+type LedgerFilter struct {
+	AccountType string
+	UserID      string
+	BookingID   string
+	PaymentID   string
+	Page        int
+	Limit       int
+}
+
+type ReviewFilter struct {
+	RevieweeID   string
+	ReviewerRole string
+	Page         int
+	Limit        int
+}
+
+// AuditLogFilter selects audit log entries. Zero From and To times leave
+// the period open.
+type AuditLogFilter struct {
+	ActorID    string
+	Action     string
+	EntityType string
+	EntityID   string
+	From       time.Time
+	To         time.Time
+	Page       int
+	Limit      int
+}
+
+type CertificationFilter struct {
+	UserID string
+	Status string
+	Page   int
+	Limit  int
+}
+
+type KYCFilter struct {
+	UserID string
+	Status string
+	Page   int
+	Limit  int
+}
+
+type OutboxFilter struct {
+	Kind   string
+	Status string
+	Page   int
+	Limit  int
+}
+
+type JobFilter struct {
+	Kind   string
+	Status string
+	Page   int
+	Limit  int
+}
+
 func (f Filter) Offset() int {
 	return (f.Page - 1) * f.Limit
 }
@@ -71,3 +129,31 @@ func (s ServiceFilter) Offset() int {
 func (p PaymentFilter) Offset() int {
 	return (p.Page - 1) * p.Limit
 }
+
+func (l LedgerFilter) Offset() int {
+	return (l.Page - 1) * l.Limit
+}
+
+func (r ReviewFilter) Offset() int {
+	return (r.Page - 1) * r.Limit
+}
+
+func (a AuditLogFilter) Offset() int {
+	return (a.Page - 1) * a.Limit
+}
+
+func (k KYCFilter) Offset() int {
+	return (k.Page - 1) * k.Limit
+}
+
+func (c CertificationFilter) Offset() int {
+	return (c.Page - 1) * c.Limit
+}
+
+func (o OutboxFilter) Offset() int {
+	return (o.Page - 1) * o.Limit
+}
+
+func (j JobFilter) Offset() int {
+	return (j.Page - 1) * j.Limit
+}
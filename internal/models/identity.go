@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// UserIdentity links an account at a third-party provider, such as
+// Google, to a user. Subject is the provider's ID for the account.
+type UserIdentity struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Provider  string    `json:"provider"`
+	Subject   string    `json:"-"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"database/sql"
+	"path"
+	"time"
+)
+
+// KYCSubmission is a service provider's proof of identity: a government
+// issued ID and a selfie, reviewed by an admin. Both files are stored
+// privately, under their keys.
+type KYCSubmission struct {
+	ID           string         `json:"id"`
+	UserID       string         `json:"user_id"`
+	DocumentType string         `json:"document_type"`
+	DocumentKey  string         `json:"-"`
+	SelfieKey    string         `json:"-"`
+	Status       string         `json:"status"`
+	Reason       string         `json:"reason"`
+	ReviewerID   sql.NullString `json:"reviewer_id"`
+	ReviewedAt   sql.NullTime   `json:"reviewed_at"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+}
+
+// DocumentPath returns the private path to the submission's ID document.
+func (k *KYCSubmission) DocumentPath() string {
+	return path.Join("private", "kyc", k.UserID, k.ID, "document")
+}
+
+// SelfiePath returns the private path to the submission's selfie.
+func (k *KYCSubmission) SelfiePath() string {
+	return path.Join("private", "kyc", k.UserID, k.ID, "selfie")
+}
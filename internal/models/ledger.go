@@ -0,0 +1,39 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// LedgerAccount is a balance holder in the double-entry ledger. System
+// accounts (commission, clearing, escrow) have no user.
+type LedgerAccount struct {
+	ID        string         `json:"id"`
+	Type      string         `json:"type"`
+	UserID    sql.NullString `json:"user_id"`
+	Currency  string         `json:"currency"`
+	Balance   Money          `json:"balance"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// LedgerEntry is a journal entry whose postings must sum to zero.
+type LedgerEntry struct {
+	ID          string          `json:"id"`
+	Description string          `json:"description"`
+	PaymentID   sql.NullString  `json:"payment_id"`
+	BookingID   sql.NullString  `json:"booking_id"`
+	Postings    []LedgerPosting `json:"postings"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// LedgerPosting moves Amount into (positive, debit) or out of (negative,
+// credit) the account identified by AccountType and UserID.
+type LedgerPosting struct {
+	ID          string    `json:"id"`
+	EntryID     string    `json:"entry_id"`
+	AccountID   string    `json:"account_id"`
+	AccountType string    `json:"account_type"`
+	UserID      string    `json:"user_id,omitempty"`
+	Amount      Money     `json:"amount"`
+	CreatedAt   time.Time `json:"created_at"`
+}
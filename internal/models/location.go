@@ -0,0 +1,43 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// Location is a geocoded place address. It is stored as JSONB, from which
+// the database derives latitude and longitude columns for proximity
+// queries.
+type Location struct {
+	StreetAddr string   `json:"street_addr"`
+	City       string   `json:"city"`
+	State      string   `json:"state"`
+	Country    string   `json:"country"`
+	Latitude   *float64 `json:"latitude,omitempty"`
+	Longitude  *float64 `json:"longitude,omitempty"`
+}
+
+// HasCoordinates reports whether the location has been geocoded.
+func (l Location) HasCoordinates() bool {
+	return l.Latitude != nil && l.Longitude != nil
+}
+
+// Implement sql.Scanner interface for Location to handle JSONB
+func (l *Location) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("error parsing location.")
+	}
+
+	return json.Unmarshal(bytes, &l)
+}
+
+// Implement driver.Valuer interface for Location to save to JSONB
+func (l Location) Value() (driver.Value, error) {
+	return json.Marshal(l)
+}
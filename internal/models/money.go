@@ -0,0 +1,265 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// currencies
+const (
+	CURRENCY_NGN     = "NGN"
+	DEFAULT_CURRENCY = CURRENCY_NGN
+)
+
+// minor units per major unit, e.g. kobo per naira.
+const MONEY_MINOR_UNITS = 100
+
+var ErrInvalidMoney = errors.New("invalid money amount")
+
+// Money is an amount of a currency held as integer minor units (kobo for
+// NGN) so arithmetic never loses precision. It scans from and writes to
+// DECIMAL(10,2) columns and marshals to JSON as a number with two
+// decimal places.
+type Money struct {
+	minor    int64
+	currency string
+}
+
+// NewMoney returns an amount of minor units in currency.
+func NewMoney(minor int64, currency string) Money {
+	return Money{minor: minor, currency: currency}
+}
+
+// NGN returns an amount of kobo in naira.
+func NGN(kobo int64) Money {
+	return NewMoney(kobo, CURRENCY_NGN)
+}
+
+// ParseMoney parses a decimal string such as "1250.50" into the default
+// currency. At most two decimal places are allowed.
+func ParseMoney(value string) (Money, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return Money{}, ErrInvalidMoney
+	}
+
+	negative := false
+	switch value[0] {
+	case '-':
+		negative = true
+		value = value[1:]
+	case '+':
+		value = value[1:]
+	}
+
+	whole, frac, _ := strings.Cut(value, ".")
+	if whole == "" && frac == "" {
+		return Money{}, ErrInvalidMoney
+	}
+	frac = strings.TrimRight(frac, "0")
+	if len(frac) > 2 {
+		return Money{}, ErrInvalidMoney
+	}
+	frac += strings.Repeat("0", 2-len(frac))
+	if whole == "" {
+		whole = "0"
+	}
+
+	units, err := strconv.ParseUint(whole, 10, 63)
+	if err != nil {
+		return Money{}, ErrInvalidMoney
+	}
+	cents, err := strconv.ParseUint(frac, 10, 8)
+	if err != nil {
+		return Money{}, ErrInvalidMoney
+	}
+	if units > (math.MaxInt64-cents)/MONEY_MINOR_UNITS {
+		return Money{}, ErrInvalidMoney
+	}
+
+	minor := int64(units)*MONEY_MINOR_UNITS + int64(cents)
+	if negative {
+		minor = -minor
+	}
+	return NewMoney(minor, DEFAULT_CURRENCY), nil
+}
+
+// Minor returns the amount in minor units.
+func (m Money) Minor() int64 {
+	return m.minor
+}
+
+// Currency returns the ISO 4217 currency code.
+func (m Money) Currency() string {
+	if m.currency == "" {
+		return DEFAULT_CURRENCY
+	}
+	return m.currency
+}
+
+func (m Money) IsZero() bool {
+	return m.minor == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.minor < 0
+}
+
+// Equal reports whether m and o are the same amount of the same currency.
+func (m Money) Equal(o Money) bool {
+	return m.minor == o.minor && m.Currency() == o.Currency()
+}
+
+// Cmp returns -1, 0 or 1 if m is less than, equal to or greater than o.
+func (m Money) Cmp(o Money) int {
+	m.mustMatch(o)
+	switch {
+	case m.minor < o.minor:
+		return -1
+	case m.minor > o.minor:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (m Money) Add(o Money) Money {
+	m.mustMatch(o)
+	return NewMoney(m.minor+o.minor, m.Currency())
+}
+
+func (m Money) Sub(o Money) Money {
+	m.mustMatch(o)
+	return NewMoney(m.minor-o.minor, m.Currency())
+}
+
+func (m Money) Neg() Money {
+	return NewMoney(-m.minor, m.Currency())
+}
+
+// Mul multiplies the amount by n. It panics if the product overflows,
+// like a currency mismatch, as no price or fee comes close to it.
+func (m Money) Mul(n int64) Money {
+	return NewMoney(mustMul(m.minor, n), m.Currency())
+}
+
+// MulFrac multiplies the amount by num/den, rounding half away from zero
+// to the nearest minor unit. It panics if amount*num overflows.
+func (m Money) MulFrac(num, den int64) Money {
+	product := mustMul(m.minor, num)
+	quotient, remainder := product/den, product%den
+	if remainder != 0 && 2*abs64(remainder) >= abs64(den) {
+		if (product < 0) != (den < 0) {
+			quotient--
+		} else {
+			quotient++
+		}
+	}
+	return NewMoney(quotient, m.Currency())
+}
+
+// String formats the amount as a decimal with two places, e.g. "1250.50".
+func (m Money) String() string {
+	sign := ""
+	minor := m.minor
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+	return fmt.Sprintf(
+		"%s%d.%02d",
+		sign,
+		minor/MONEY_MINOR_UNITS,
+		minor%MONEY_MINOR_UNITS,
+	)
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON accepts the amount as a JSON number or string.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var value string
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+	} else {
+		var number json.Number
+		if err := json.Unmarshal(data, &number); err != nil {
+			return err
+		}
+		value = number.String()
+	}
+
+	parsed, err := ParseMoney(value)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Scan implements sql.Scanner for DECIMAL columns.
+func (m *Money) Scan(src any) error {
+	switch value := src.(type) {
+	case nil:
+		*m = NewMoney(0, DEFAULT_CURRENCY)
+		return nil
+	case []byte:
+		parsed, err := ParseMoney(string(value))
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	case string:
+		parsed, err := ParseMoney(value)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	case int64:
+		*m = NewMoney(value*MONEY_MINOR_UNITS, DEFAULT_CURRENCY)
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+}
+
+// Value implements driver.Valuer, writing the amount as a decimal string.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+func (m Money) mustMatch(o Money) {
+	if m.Currency() != o.Currency() {
+		panic(fmt.Sprintf("money: currency mismatch %s != %s", m.Currency(), o.Currency()))
+	}
+}
+
+// mustMul returns a*b, panicking if it overflows an int64.
+func mustMul(a, b int64) int64 {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	product := a * b
+	if product/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		panic(fmt.Sprintf("money: %d * %d overflows", a, b))
+	}
+	return product
+}
+
+func abs64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,193 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		value string
+		minor int64
+		err   error
+	}{
+		{value: "1250.50", minor: 125050},
+		{value: "1250.5", minor: 125050},
+		{value: "1250", minor: 125000},
+		{value: "0.01", minor: 1},
+		{value: ".5", minor: 50},
+		{value: "5.", minor: 500},
+		{value: " 7.25 ", minor: 725},
+		{value: "+3.10", minor: 310},
+		{value: "-3.10", minor: -310},
+		{value: "-0.01", minor: -1},
+		{value: "1.500", minor: 150},
+		{value: "1.005", err: ErrInvalidMoney},
+		{value: "1.999", err: ErrInvalidMoney},
+		{value: "-1.001", err: ErrInvalidMoney},
+		{value: "", err: ErrInvalidMoney},
+		{value: "-", err: ErrInvalidMoney},
+		{value: ".", err: ErrInvalidMoney},
+		{value: "abc", err: ErrInvalidMoney},
+		{value: "1.2.3", err: ErrInvalidMoney},
+		{value: "--1", err: ErrInvalidMoney},
+		{value: "1e3", err: ErrInvalidMoney},
+		{value: "92233720368547758.07", minor: 9223372036854775807},
+		{value: "92233720368547758.08", err: ErrInvalidMoney},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.value)
+		if tt.err != nil {
+			if !errors.Is(err, tt.err) {
+				t.Errorf("ParseMoney(%q) error = %v, want %v", tt.value, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseMoney(%q) unexpected error: %v", tt.value, err)
+			continue
+		}
+		if got.Minor() != tt.minor {
+			t.Errorf("ParseMoney(%q) = %d, want %d", tt.value, got.Minor(), tt.minor)
+		}
+		if got.Currency() != DEFAULT_CURRENCY {
+			t.Errorf("ParseMoney(%q) currency = %s, want %s", tt.value, got.Currency(), DEFAULT_CURRENCY)
+		}
+	}
+}
+
+func TestMoneyMulFrac(t *testing.T) {
+	tests := []struct {
+		minor    int64
+		num, den int64
+		want     int64
+	}{
+		{minor: 1000, num: 90, den: 60, want: 1500},
+		{minor: 100, num: 1, den: 3, want: 33},
+		{minor: 200, num: 1, den: 3, want: 67},
+		// halves round away from zero.
+		{minor: 1, num: 1, den: 2, want: 1},
+		{minor: 3, num: 1, den: 2, want: 2},
+		{minor: 5, num: 1, den: 10, want: 1},
+		{minor: 4, num: 1, den: 10, want: 0},
+		{minor: -1, num: 1, den: 2, want: -1},
+		{minor: -3, num: 1, den: 2, want: -2},
+		{minor: -4, num: 1, den: 10, want: 0},
+		{minor: 3, num: 1, den: -2, want: -2},
+		{minor: -3, num: 1, den: -2, want: 2},
+		// the percentage of a fee.
+		{minor: 12345, num: 15, den: 100, want: 1852},
+		{minor: 0, num: 7, den: 3, want: 0},
+	}
+
+	for _, tt := range tests {
+		got := NGN(tt.minor).MulFrac(tt.num, tt.den)
+		if got.Minor() != tt.want {
+			t.Errorf("NGN(%d).MulFrac(%d, %d) = %d, want %d", tt.minor, tt.num, tt.den, got.Minor(), tt.want)
+		}
+	}
+}
+
+func TestMoneyMulOverflow(t *testing.T) {
+	tests := []struct {
+		name     string
+		multiply func() Money
+		fails    bool
+	}{
+		{name: "in range", multiply: func() Money { return NGN(math.MaxInt64 / 2).Mul(2) }},
+		{name: "negative in range", multiply: func() Money { return NGN(math.MinInt64 / 2).Mul(2) }},
+		{name: "overflow", multiply: func() Money { return NGN(math.MaxInt64/2 + 1).Mul(2) }, fails: true},
+		{name: "negative overflow", multiply: func() Money { return NGN(math.MinInt64).Mul(-1) }, fails: true},
+		{name: "min by minus one", multiply: func() Money { return NGN(-1).Mul(math.MinInt64) }, fails: true},
+		{name: "fraction overflow", multiply: func() Money { return NGN(math.MaxInt64).MulFrac(3, 4) }, fails: true},
+		{name: "fraction in range", multiply: func() Money { return NGN(math.MaxInt64/4).MulFrac(3, 4) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recovered := recover(); (recovered != nil) != tt.fails {
+					t.Errorf("panicked = %v, want %v", recovered != nil, tt.fails)
+				}
+			}()
+			tt.multiply()
+		})
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tests := []struct {
+		minor int64
+		want  string
+	}{
+		{minor: 0, want: "0.00"},
+		{minor: 5, want: "0.05"},
+		{minor: 125050, want: "1250.50"},
+		{minor: -1, want: "-0.01"},
+		{minor: -125050, want: "-1250.50"},
+	}
+
+	for _, tt := range tests {
+		if got := NGN(tt.minor).String(); got != tt.want {
+			t.Errorf("NGN(%d).String() = %q, want %q", tt.minor, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	type payload struct {
+		Amount Money `json:"amount"`
+	}
+
+	data, err := json.Marshal(payload{Amount: NGN(125050)})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if string(data) != `{"amount":1250.50}` {
+		t.Errorf("Marshal = %s, want a bare number", data)
+	}
+
+	var decoded payload
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal(%s): %v", data, err)
+	}
+	if !decoded.Amount.Equal(NGN(125050)) {
+		t.Errorf("round trip = %s, want 1250.50", decoded.Amount)
+	}
+
+	tests := []struct {
+		data  string
+		minor int64
+		fails bool
+	}{
+		{data: `{"amount":1250.5}`, minor: 125050},
+		{data: `{"amount":"1250.50"}`, minor: 125050},
+		{data: `{"amount":-3}`, minor: -300},
+		{data: `{"amount":"-0.01"}`, minor: -1},
+		{data: `{"amount":1.005}`, fails: true},
+		{data: `{"amount":"1.005"}`, fails: true},
+		{data: `{"amount":"abc"}`, fails: true},
+		{data: `{"amount":true}`, fails: true},
+	}
+
+	for _, tt := range tests {
+		var got payload
+		err := json.Unmarshal([]byte(tt.data), &got)
+		if tt.fails {
+			if err == nil {
+				t.Errorf("Unmarshal(%s) = %s, want an error", tt.data, got.Amount)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unmarshal(%s) unexpected error: %v", tt.data, err)
+			continue
+		}
+		if got.Amount.Minor() != tt.minor {
+			t.Errorf("Unmarshal(%s) = %d, want %d", tt.data, got.Amount.Minor(), tt.minor)
+		}
+	}
+}
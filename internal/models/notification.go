@@ -2,6 +2,8 @@ package models
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
 	"time"
 )
 
@@ -14,3 +16,74 @@ type Notification struct {
 	Seen      bool
 	CreatedAt time.Time
 }
+
+// NotificationPreference is the channels a user receives a type of
+// notification on.
+type NotificationPreference struct {
+	UserID    string    `json:"-"`
+	Type      string    `json:"type"`
+	Channels  []string  `json:"channels"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// QuietHours is the time of day, in the user's timezone, during which
+// they are not interrupted by SMS and push notifications. It may span
+// midnight, e.g. from 22:00 to 07:00.
+type QuietHours struct {
+	UserID    string    `json:"-"`
+	Start     string    `json:"start"`
+	End       string    `json:"end"`
+	Timezone  string    `json:"timezone"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PushDevice is a mobile device registered for push notifications.
+type PushDevice struct {
+	Token     string    `json:"token"`
+	UserID    string    `json:"user_id"`
+	Platform  string    `json:"platform"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Validate checks the bounds and the timezone.
+func (q QuietHours) Validate() error {
+	start, err := parseTimeOfDay(q.Start)
+	if err != nil {
+		return err
+	}
+	end, err := parseTimeOfDay(q.End)
+	if err != nil {
+		return err
+	}
+	if start == end {
+		return errors.New("quiet hours start and end at the same time")
+	}
+	if _, err := time.LoadLocation(q.Timezone); err != nil {
+		return fmt.Errorf("unknown timezone %q", q.Timezone)
+	}
+	return nil
+}
+
+// Contains reports whether t falls within the quiet hours.
+func (q QuietHours) Contains(t time.Time) bool {
+	location, err := time.LoadLocation(q.Timezone)
+	if err != nil {
+		return false
+	}
+	start, err := parseTimeOfDay(q.Start)
+	if err != nil {
+		return false
+	}
+	end, err := parseTimeOfDay(q.End)
+	if err != nil {
+		return false
+	}
+
+	local := t.In(location)
+	minute := local.Hour()*60 + local.Minute()
+	if start < end {
+		return start <= minute && minute < end
+	}
+	// spans midnight
+	return minute >= start || minute < end
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// OutboxMessage is a side effect recorded in the same transaction as the
+// change causing it, and delivered afterwards by the outbox relay, with
+// retries. Messages failing too many times are dead-lettered.
+type OutboxMessage struct {
+	ID          string          `json:"id"`
+	Kind        string          `json:"kind"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      string          `json:"status"`
+	Attempts    int             `json:"attempts"`
+	LastError   string          `json:"last_error"`
+	AvailableAt time.Time       `json:"available_at"`
+	CreatedAt   time.Time       `json:"created_at"`
+	DeliveredAt sql.NullTime    `json:"delivered_at"`
+}
+
+// NewOutboxMessage returns a message of kind with the JSON encoded
+// payload.
+func NewOutboxMessage(kind string, payload any) (OutboxMessage, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return OutboxMessage{}, err
+	}
+	return OutboxMessage{Kind: kind, Payload: data}, nil
+}
+
+// OutboxEmail is the payload of an email message.
+type OutboxEmail struct {
+	Recipient string `json:"recipient"`
+	Template  string `json:"template"`
+	Data      any    `json:"data"`
+}
+
+// OutboxSMS is the payload of an SMS message.
+type OutboxSMS struct {
+	Recipient string `json:"recipient"`
+	Template  string `json:"template"`
+	Data      any    `json:"data"`
+}
+
+// OutboxPush is the payload of a push notification to one of a user's
+// devices.
+type OutboxPush struct {
+	UserID string            `json:"user_id"`
+	Token  string            `json:"token"`
+	Title  string            `json:"title"`
+	Body   string            `json:"body"`
+	Data   map[string]string `json:"data"`
+}
+
+// OutboxTransfer is the payload of a payout to a service provider.
+type OutboxTransfer struct {
+	Reference     string `json:"reference"`
+	RecipientCode string `json:"recipient_code"`
+	Reason        string `json:"reason"`
+	Amount        Money  `json:"amount"`
+}
+
+// LedgerPayment is a payment recorded along with its ledger entries.
+type LedgerPayment struct {
+	Payment *Payment
+	Entries []LedgerEntry
+}
+
+// TransitionEffects are recorded in the same transaction as a booking's
+// change of status.
+type TransitionEffects struct {
+	Payments []LedgerPayment
+	Outbox   []OutboxMessage
+}
@@ -8,8 +8,9 @@ import (
 type UserWallet struct {
 	ID        string    `json:"id"`
 	UserID    string    `json:"user_id"`
-	Balance   float64   `json:"balance"`
-	Debits    float64   `json:"debits"`
+	Balance   Money     `json:"balance"`
+	Debits    Money     `json:"debits"`
+	Earnings  Money     `json:"earnings"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -18,7 +19,7 @@ type Payment struct {
 	ID         string         `json:"id"`
 	Type       string         `json:"type"`
 	BookingID  sql.NullString `json:"booking_id"`
-	Amount     float64        `json:"amount"`
+	Amount     Money          `json:"amount"`
 	PaymentRef string         `json:"payment_ref"`
 	Status     string         `json:"status"`
 	CreatedAt  time.Time      `json:"created_at"`
@@ -12,7 +12,7 @@ type ServiceCommission struct {
 type ServicePricing struct {
 	ID          string    `json:"id"`
 	ServiceType string    `json:"service_type"`
-	RatePerHour float64   `json:"rate_per_hour"`
+	RatePerHour Money     `json:"rate_per_hour"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
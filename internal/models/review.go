@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Review is a rating left by one side of a completed booking for the
+// other.
+type Review struct {
+	ID           string    `json:"id"`
+	BookingID    string    `json:"booking_id"`
+	ReviewerID   string    `json:"reviewer_id"`
+	RevieweeID   string    `json:"reviewee_id"`
+	ReviewerRole string    `json:"reviewer_role"`
+	Rating       int       `json:"rating"`
+	Comment      string    `json:"comment"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// RatingSummary aggregates the reviews a user has received.
+type RatingSummary struct {
+	Rating      float64 `json:"rating"`
+	ReviewCount int64   `json:"review_count"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// Role groups the permissions granted to the users assigned to it.
+// Built-in roles can not be deleted.
+type Role struct {
+	Name              string    `json:"name"`
+	Description       string    `json:"description"`
+	RequiresTwoFactor bool      `json:"requires_two_factor"`
+	Builtin           bool      `json:"builtin"`
+	Permissions       []string  `json:"permissions"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+type Permission struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
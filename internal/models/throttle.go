@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// AuthThrottle tracks failed authentication attempts for an account or an
+// IP address.
+type AuthThrottle struct {
+	Key         string     `json:"key"`
+	Failures    int        `json:"failures"`
+	Lockouts    int        `json:"lockouts"`
+	LockedUntil *time.Time `json:"locked_until"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// Locked reports whether the key is currently locked out.
+func (t AuthThrottle) Locked() bool {
+	return t.LockedUntil != nil && time.Now().Before(*t.LockedUntil)
+}
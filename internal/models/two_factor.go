@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// TwoFactor is a user's TOTP enrollment. It is enabled once the user has
+// confirmed a code from their authenticator app.
+type TwoFactor struct {
+	UserID       string     `json:"user_id"`
+	Secret       string     `json:"-"`
+	Enabled      bool       `json:"enabled"`
+	LastUsedStep int64      `json:"-"`
+	ConfirmedAt  *time.Time `json:"confirmed_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"database/sql"
 	"path"
 	"time"
 )
@@ -32,10 +33,33 @@ type UserVerificationCode struct {
 	UserID      string    `json:"user_id"`
 	Code        int       `json:"code"`
 	ContactType string    `json:"contact_type"`
+	Attempts    int       `json:"attempts"`
 	CreatedAt   time.Time `json:"created_at"`
 	ExpiresAt   time.Time `json:"expires_at"`
 }
 
+// PasswordReset is a pending forgot-password request. Only the hash of
+// its token is stored.
+type PasswordReset struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	TokenHash string    `json:"-"`
+	Channel   string    `json:"channel"`
+	Attempts  int       `json:"attempts"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// AccountDeletion is a user's request to delete their account. The
+// account is anonymized once ScheduledFor has passed, unless the user
+// cancels first.
+type AccountDeletion struct {
+	UserID       string       `json:"user_id"`
+	RequestedAt  time.Time    `json:"requested_at"`
+	ScheduledFor time.Time    `json:"scheduled_for"`
+	CompletedAt  sql.NullTime `json:"completed_at"`
+}
+
 type UserBankInfo struct {
 	UserID      string    `json:"user_id"`
 	BankName    string    `json:"bank_name"`
@@ -46,21 +70,50 @@ type UserBankInfo struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
-type UserEducationInfo struct {
-	UserID     string    `json:"user_id"`
-	Institute  string    `json:"institute"`
-	Degree     string    `json:"degree"`
-	Discipline string    `json:"discipline"`
-	Start      time.Time `json:"start"`
-	Finish     time.Time `json:"finish"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+// UserEducation is an entry of a user's education history. Finish is
+// unset while they are still studying.
+type UserEducation struct {
+	ID         string       `json:"id"`
+	UserID     string       `json:"user_id"`
+	Institute  string       `json:"institute"`
+	Degree     string       `json:"degree"`
+	Discipline string       `json:"discipline"`
+	Start      time.Time    `json:"start"`
+	Finish     sql.NullTime `json:"finish"`
+	CreatedAt  time.Time    `json:"created_at"`
+	UpdatedAt  time.Time    `json:"updated_at"`
+}
+
+// UserExperience is an entry of a user's work history. Finish is unset
+// while they are still employed.
+type UserExperience struct {
+	ID          string       `json:"id"`
+	UserID      string       `json:"user_id"`
+	Employer    string       `json:"employer"`
+	Role        string       `json:"role"`
+	Start       time.Time    `json:"start"`
+	Finish      sql.NullTime `json:"finish"`
+	Description string       `json:"description"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
 }
 
+// UserCertification is a credential uploaded by a user. It is shown on
+// their public profile once approved, until it expires.
 type UserCertification struct {
-	ID     string `json:"id"`
-	UserID string `json:"user_id"`
-	URL    string `json:"url"`
+	ID          string         `json:"id"`
+	UserID      string         `json:"user_id"`
+	URL         string         `json:"url"`
+	Title       string         `json:"title"`
+	Issuer      string         `json:"issuer"`
+	IssuedOn    sql.NullTime   `json:"issued_on"`
+	ExpiresOn   sql.NullTime   `json:"expires_on"`
+	Status      string         `json:"status"`
+	ReviewNotes string         `json:"review_notes"`
+	ReviewerID  sql.NullString `json:"reviewer_id"`
+	ReviewedAt  sql.NullTime   `json:"reviewed_at"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
 }
 
 // AvatarPath returns the relative path to user's avatar.
@@ -0,0 +1,310 @@
+// Package notify routes notifications to the channels each user chose
+// for their type: in-app, email, SMS and mobile push. Deliveries on
+// external channels go through the outbox, and are retried until sent.
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lokatalent/backend_go/internal/mailer"
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/push"
+	"github.com/lokatalent/backend_go/internal/repository"
+	"github.com/lokatalent/backend_go/internal/sms"
+)
+
+const (
+	emailTmpl = "notification.gotmpl"
+	smsTmpl   = "notification.gotmpl"
+)
+
+// Channels notifications are sent on.
+var Channels = []string{
+	models.NOTIFICATION_CHANNEL_IN_APP,
+	models.NOTIFICATION_CHANNEL_EMAIL,
+	models.NOTIFICATION_CHANNEL_SMS,
+	models.NOTIFICATION_CHANNEL_PUSH,
+}
+
+// DefaultChannels are the channels of each type of notification, for
+// users who have not changed them.
+var DefaultChannels = map[string][]string{
+	models.NOTIFICATION_TYPE_BOOKING: {
+		models.NOTIFICATION_CHANNEL_IN_APP,
+		models.NOTIFICATION_CHANNEL_EMAIL,
+		models.NOTIFICATION_CHANNEL_PUSH,
+	},
+	models.NOTIFICATION_TYPE_KYC: {
+		models.NOTIFICATION_CHANNEL_IN_APP,
+		models.NOTIFICATION_CHANNEL_EMAIL,
+	},
+	models.NOTIFICATION_TYPE_CERTIFICATION: {
+		models.NOTIFICATION_CHANNEL_IN_APP,
+		models.NOTIFICATION_CHANNEL_EMAIL,
+	},
+}
+
+// channels not used during a user's quiet hours.
+var interruptingChannels = []string{
+	models.NOTIFICATION_CHANNEL_SMS,
+	models.NOTIFICATION_CHANNEL_PUSH,
+}
+
+// titles of each type of notification, for email subjects and push
+// notifications.
+var titles = map[string]string{
+	models.NOTIFICATION_TYPE_BOOKING:       "Booking update",
+	models.NOTIFICATION_TYPE_KYC:           "Identity verification",
+	models.NOTIFICATION_TYPE_CERTIFICATION: "Certification update",
+}
+
+// Dispatcher sends notifications on the channels their recipient chose.
+type Dispatcher struct {
+	repos  *repository.Repositories
+	mailer *mailer.Mailer
+	sms    *sms.SMSSender
+	// push is nil when push notifications are disabled.
+	push *push.FCM
+}
+
+func New(repos *repository.Repositories, mailer *mailer.Mailer, sms *sms.SMSSender, push *push.FCM) *Dispatcher {
+	return &Dispatcher{
+		repos:  repos,
+		mailer: mailer,
+		sms:    sms,
+		push:   push,
+	}
+}
+
+// UserChannels returns the channels the user receives the type of
+// notification on.
+func (d *Dispatcher) UserChannels(userID, notificationType string) ([]string, error) {
+	preferences, err := d.repos.Notification.GetPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, preference := range preferences {
+		if preference.Type == notificationType {
+			return preference.Channels, nil
+		}
+	}
+	return DefaultChannels[notificationType], nil
+}
+
+// Dispatch saves the notification if its recipient receives it in-app,
+// and enqueues its delivery on their other channels in the outbox, in the
+// same transaction. SMS and push notifications are skipped during the
+// recipient's quiet hours. Outbox messages get IDs derived from the
+// notification's, so dispatching a notification again enqueues nothing
+// new.
+func (d *Dispatcher) Dispatch(notification *models.Notification) error {
+	if notification.ID == "" {
+		notification.ID = uuid.NewString()
+	}
+
+	channels, err := d.UserChannels(notification.UserID, notification.Type)
+	if err != nil {
+		return err
+	}
+
+	quietHours, err := d.repos.Notification.GetQuietHours(notification.UserID)
+	if err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
+		return err
+	}
+	quiet := err == nil && quietHours.Contains(time.Now())
+
+	external := []string{}
+	for _, channel := range channels {
+		if channel == models.NOTIFICATION_CHANNEL_IN_APP {
+			continue
+		}
+		if quiet && slices.Contains(interruptingChannels, channel) {
+			continue
+		}
+		external = append(external, channel)
+	}
+	messages, err := d.outboxMessages(notification, external)
+	if err != nil {
+		return err
+	}
+
+	if slices.Contains(channels, models.NOTIFICATION_CHANNEL_IN_APP) {
+		err := d.repos.Notification.Create(notification, messages...)
+		if errors.Is(err, repository.ErrNotificationExists) {
+			return nil
+		}
+		return err
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	return d.repos.Outbox.Enqueue(messages...)
+}
+
+// Deliver sends an email, SMS or push outbox message.
+func (d *Dispatcher) Deliver(message models.OutboxMessage) error {
+	switch message.Kind {
+	case models.OUTBOX_KIND_EMAIL:
+		email := models.OutboxEmail{}
+		if err := json.Unmarshal(message.Payload, &email); err != nil {
+			return err
+		}
+		return d.mailer.Send(email.Recipient, email.Template, email.Data)
+	case models.OUTBOX_KIND_SMS:
+		sms := models.OutboxSMS{}
+		if err := json.Unmarshal(message.Payload, &sms); err != nil {
+			return err
+		}
+		return d.sms.Send(sms.Recipient, sms.Template, sms.Data)
+	case models.OUTBOX_KIND_PUSH:
+		pushMessage := models.OutboxPush{}
+		if err := json.Unmarshal(message.Payload, &pushMessage); err != nil {
+			return err
+		}
+		return d.sendPush(pushMessage)
+	default:
+		return fmt.Errorf("notify: can not deliver %s messages", message.Kind)
+	}
+}
+
+// outboxMessages returns the messages delivering the notification on the
+// external channels. Email and SMS are only sent to verified contacts,
+// and push notifications to each of the recipient's devices in a message
+// of its own, so that a failing device doesn't resend to the others.
+func (d *Dispatcher) outboxMessages(notification *models.Notification, channels []string) ([]models.OutboxMessage, error) {
+	if len(channels) == 0 {
+		return nil, nil
+	}
+
+	user, err := d.repos.User.GetByID(notification.UserID)
+	if err != nil {
+		return nil, err
+	}
+	data := struct {
+		FirstName string
+		Title     string
+		Message   string
+		Year      int
+	}{
+		FirstName: user.FirstName,
+		Title:     typeTitle(notification.Type),
+		Message:   notification.Message,
+		Year:      time.Now().Year(),
+	}
+
+	messages := []models.OutboxMessage{}
+	add := func(kind, key string, payload any) error {
+		message, err := models.NewOutboxMessage(kind, payload)
+		if err != nil {
+			return err
+		}
+		message.ID = outboxMessageID(notification.ID, key)
+		messages = append(messages, message)
+		return nil
+	}
+
+	for _, channel := range channels {
+		switch channel {
+		case models.NOTIFICATION_CHANNEL_EMAIL:
+			if user.Email == "" || !user.EmailVerified {
+				continue
+			}
+			err = add(
+				models.OUTBOX_KIND_EMAIL,
+				channel,
+				models.OutboxEmail{Recipient: user.Email, Template: emailTmpl, Data: data},
+			)
+		case models.NOTIFICATION_CHANNEL_SMS:
+			if user.PhoneNum == "" || !user.PhoneVerified {
+				continue
+			}
+			err = add(
+				models.OUTBOX_KIND_SMS,
+				channel,
+				models.OutboxSMS{Recipient: user.PhoneNum, Template: smsTmpl, Data: data},
+			)
+		case models.NOTIFICATION_CHANNEL_PUSH:
+			if d.push == nil {
+				continue
+			}
+			err = d.addPushMessages(notification, add)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return messages, nil
+}
+
+// addPushMessages adds a push message for each of the recipient's
+// devices.
+func (d *Dispatcher) addPushMessages(notification *models.Notification, add func(kind, key string, payload any) error) error {
+	devices, err := d.repos.Notification.GetPushDevices(notification.UserID)
+	if err != nil {
+		return err
+	}
+
+	for _, device := range devices {
+		err := add(
+			models.OUTBOX_KIND_PUSH,
+			models.NOTIFICATION_CHANNEL_PUSH+":"+device.Token,
+			models.OutboxPush{
+				UserID: device.UserID,
+				Token:  device.Token,
+				Title:  typeTitle(notification.Type),
+				Body:   notification.Message,
+				Data: map[string]string{
+					"notification_id": notification.ID,
+					"type":            notification.Type,
+					"booking_id":      notification.BookingID.String,
+				},
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendPush sends a push notification to a device, forgetting the device
+// if it is no longer registered.
+func (d *Dispatcher) sendPush(message models.OutboxPush) error {
+	if d.push == nil {
+		return nil
+	}
+
+	err := d.push.Send(message.Token, push.Message{
+		Title: message.Title,
+		Body:  message.Body,
+		Data:  message.Data,
+	})
+	if errors.Is(err, push.ErrUnregistered) {
+		err = d.repos.Notification.DeletePushDevice(message.UserID, message.Token)
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil
+		}
+	}
+	return err
+}
+
+// typeTitle returns the title of a type of notification.
+func typeTitle(notificationType string) string {
+	if title, ok := titles[notificationType]; ok {
+		return title
+	}
+	return "LOKATALENT"
+}
+
+// outboxMessageID returns the ID of the outbox message delivering a
+// notification, derived from the notification's ID and the key of the
+// delivery within it.
+func outboxMessageID(notificationID, key string) string {
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(notificationID+"/"+key)).String()
+}
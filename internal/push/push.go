@@ -0,0 +1,202 @@
+// Package push sends mobile push notifications through Firebase Cloud
+// Messaging, which delivers to both Android and iOS devices.
+package push
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	fcmScope   = "https://www.googleapis.com/auth/firebase.messaging"
+	fcmSendURL = "https://fcm.googleapis.com/v1/projects/%s/messages:send"
+
+	requestTimeout = 10 * time.Second
+	// access tokens are renewed this long before they expire.
+	tokenExpiryMargin = time.Minute
+)
+
+// ErrUnregistered is returned for a device token that is no longer
+// valid, which should be forgotten.
+var ErrUnregistered = errors.New("device token is no longer registered")
+
+// Credentials of the Firebase service account sending notifications.
+type Credentials struct {
+	ProjectID   string
+	ClientEmail string
+	TokenURI    string
+	PrivateKey  *rsa.PrivateKey
+}
+
+// ParseCredentials parses the JSON key of a service account, as
+// downloaded from the Firebase console.
+func ParseCredentials(data []byte) (Credentials, error) {
+	account := struct {
+		ProjectID   string `json:"project_id"`
+		ClientEmail string `json:"client_email"`
+		TokenURI    string `json:"token_uri"`
+		PrivateKey  string `json:"private_key"`
+	}{}
+	if err := json.Unmarshal(data, &account); err != nil {
+		return Credentials{}, err
+	}
+	if account.ProjectID == "" || account.ClientEmail == "" || account.TokenURI == "" {
+		return Credentials{}, errors.New("incomplete service account key")
+	}
+
+	block, _ := pem.Decode([]byte(account.PrivateKey))
+	if block == nil {
+		return Credentials{}, errors.New("no PEM data found")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return Credentials{}, err
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return Credentials{}, errors.New("expected an RSA private key")
+	}
+
+	return Credentials{
+		ProjectID:   account.ProjectID,
+		ClientEmail: account.ClientEmail,
+		TokenURI:    account.TokenURI,
+		PrivateKey:  rsaKey,
+	}, nil
+}
+
+// Message is shown on the device. Data is passed to the app.
+type Message struct {
+	Title string
+	Body  string
+	Data  map[string]string
+}
+
+// FCM sends push notifications with the Firebase Cloud Messaging HTTP v1
+// API.
+type FCM struct {
+	credentials Credentials
+	client      *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+func NewFCM(credentials Credentials) *FCM {
+	return &FCM{
+		credentials: credentials,
+		client:      &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Send pushes the message to the device.
+func (f *FCM) Send(deviceToken string, msg Message) error {
+	accessToken, err := f.token()
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"message": map[string]any{
+			"token": deviceToken,
+			"notification": map[string]string{
+				"title": msg.Title,
+				"body":  msg.Body,
+			},
+			"data": msg.Data,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(
+		http.MethodPost,
+		fmt.Sprintf(fcmSendURL, f.credentials.ProjectID),
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return nil
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrUnregistered
+	default:
+		return fmt.Errorf("fcm: unexpected status %s", resp.Status)
+	}
+}
+
+// token returns an access token for the FCM API, exchanging a JWT signed
+// with the service account's key once the previous one expires.
+func (f *FCM) token() (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.accessToken != "" && time.Now().Before(f.expiresAt) {
+		return f.accessToken, nil
+	}
+
+	now := time.Now()
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   f.credentials.ClientEmail,
+		"scope": fcmScope,
+		"aud":   f.credentials.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(f.credentials.PrivateKey)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	resp, err := f.client.Post(
+		f.credentials.TokenURI,
+		"application/x-www-form-urlencoded",
+		strings.NewReader(form.Encode()),
+	)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fcm: token exchange failed with status %s", resp.Status)
+	}
+
+	token := struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", err
+	}
+
+	f.accessToken = token.AccessToken
+	f.expiresAt = now.Add(time.Duration(token.ExpiresIn)*time.Second - tokenExpiryMargin)
+	return f.accessToken, nil
+}
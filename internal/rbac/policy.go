@@ -0,0 +1,35 @@
+package rbac
+
+import "github.com/lokatalent/backend_go/internal/models"
+
+// IsBookingRequester reports whether the principal placed the booking.
+func IsBookingRequester(p *Principal, booking *models.Booking) bool {
+	return booking.RequesterID == p.ID
+}
+
+// IsBookingProvider reports whether the principal is the booking's
+// selected provider.
+func IsBookingProvider(p *Principal, booking *models.Booking) bool {
+	return booking.ProviderID.Valid && booking.ProviderID.String == p.ID
+}
+
+// CanViewBooking reports whether the principal may see the booking and
+// its history: its requester, its provider, or staff who read any
+// booking.
+func CanViewBooking(p *Principal, booking *models.Booking) bool {
+	return IsBookingRequester(p, booking) ||
+		IsBookingProvider(p, booking) ||
+		p.Can(BOOKING_READ_ANY)
+}
+
+// CanListBookings reports whether the principal may list the bookings
+// matching a requester and provider filter. Users may only list their
+// own.
+func CanListBookings(p *Principal, requesterID, providerID string) bool {
+	return requesterID == p.ID || providerID == p.ID || p.Can(BOOKING_READ_ANY)
+}
+
+// CanPayBooking reports whether the principal may pay for the booking.
+func CanPayBooking(p *Principal, booking *models.Booking) bool {
+	return IsBookingRequester(p, booking)
+}
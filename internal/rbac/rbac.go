@@ -0,0 +1,53 @@
+// Package rbac defines the permissions roles grant and the ownership
+// policies deciding who may act on a resource. Roles, and the permissions
+// each of them grants, are stored in the database, so staff roles can be
+// created without code changes.
+package rbac
+
+import (
+	"slices"
+
+	"github.com/lokatalent/backend_go/internal/models"
+)
+
+// permissions
+const (
+	USERS_READ_ANY    = "users:read:any"
+	USERS_ROLE_CHANGE = "users:role:change"
+	ROLES_MANAGE      = "roles:manage"
+
+	BOOKING_READ_ANY = "booking:read:any"
+	// BOOKING_MANAGE lets a user act on any booking as an admin.
+	BOOKING_MANAGE = "booking:manage"
+
+	PRICING_WRITE    = "pricing:write"
+	COMMISSION_WRITE = "commission:write"
+	LEDGER_READ      = "ledger:read"
+
+	AUDIT_READ = "audit:read"
+	KYC_REVIEW = "kyc:review"
+
+	CERTIFICATIONS_REVIEW = "certifications:review"
+	OUTBOX_MANAGE         = "outbox:manage"
+	JOBS_MANAGE           = "jobs:manage"
+)
+
+// Principal is an authenticated user, along with the permissions their
+// role grants.
+type Principal struct {
+	models.User
+	Permissions []string
+	// RequiresTwoFactor is set for staff roles, whose users must sign in
+	// with two-factor authentication.
+	RequiresTwoFactor bool
+}
+
+// Can reports whether the principal has the permission.
+func (p *Principal) Can(permission string) bool {
+	return slices.Contains(p.Permissions, permission)
+}
+
+// IsStaff reports whether the principal's role grants any permission.
+func (p *Principal) IsStaff() bool {
+	return len(p.Permissions) > 0
+}
@@ -0,0 +1,120 @@
+package rbac
+
+import (
+	"testing"
+
+	"github.com/lokatalent/backend_go/internal/models"
+)
+
+func TestCan(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []string
+		permission  string
+		can         bool
+		staff       bool
+	}{
+		{name: "no role", permission: BOOKING_READ_ANY},
+		{name: "granted", permissions: []string{BOOKING_READ_ANY}, permission: BOOKING_READ_ANY, can: true, staff: true},
+		{name: "not granted", permissions: []string{BOOKING_READ_ANY}, permission: BOOKING_MANAGE, staff: true},
+		{
+			name:        "one of several",
+			permissions: []string{USERS_READ_ANY, ROLES_MANAGE, LEDGER_READ},
+			permission:  ROLES_MANAGE,
+			can:         true,
+			staff:       true,
+		},
+		{name: "prefix only", permissions: []string{"booking:read"}, permission: BOOKING_READ_ANY, staff: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Principal{Permissions: tt.permissions}
+			if got := p.Can(tt.permission); got != tt.can {
+				t.Errorf("Can(%s) = %v, want %v", tt.permission, got, tt.can)
+			}
+			if got := p.IsStaff(); got != tt.staff {
+				t.Errorf("IsStaff() = %v, want %v", got, tt.staff)
+			}
+		})
+	}
+}
+
+func TestBookingPolicies(t *testing.T) {
+	booking := &models.Booking{RequesterID: "requester"}
+	booking.ProviderID.String = "provider"
+	booking.ProviderID.Valid = true
+	unassigned := &models.Booking{RequesterID: "requester"}
+
+	principal := func(id string, permissions ...string) *Principal {
+		p := &Principal{Permissions: permissions}
+		p.ID = id
+		return p
+	}
+
+	tests := []struct {
+		name      string
+		principal *Principal
+		booking   *models.Booking
+		requester bool
+		provider  bool
+		view      bool
+		pay       bool
+	}{
+		{name: "requester", principal: principal("requester"), booking: booking, requester: true, view: true, pay: true},
+		{name: "provider", principal: principal("provider"), booking: booking, provider: true, view: true},
+		{name: "stranger", principal: principal("stranger"), booking: booking},
+		{name: "staff", principal: principal("staff", BOOKING_READ_ANY), booking: booking, view: true},
+		{name: "manager without read", principal: principal("staff", BOOKING_MANAGE), booking: booking},
+		{name: "no provider", principal: principal(""), booking: unassigned},
+		{name: "requester of unassigned", principal: principal("requester"), booking: unassigned, requester: true, view: true, pay: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsBookingRequester(tt.principal, tt.booking); got != tt.requester {
+				t.Errorf("IsBookingRequester = %v, want %v", got, tt.requester)
+			}
+			if got := IsBookingProvider(tt.principal, tt.booking); got != tt.provider {
+				t.Errorf("IsBookingProvider = %v, want %v", got, tt.provider)
+			}
+			if got := CanViewBooking(tt.principal, tt.booking); got != tt.view {
+				t.Errorf("CanViewBooking = %v, want %v", got, tt.view)
+			}
+			if got := CanPayBooking(tt.principal, tt.booking); got != tt.pay {
+				t.Errorf("CanPayBooking = %v, want %v", got, tt.pay)
+			}
+		})
+	}
+}
+
+func TestCanListBookings(t *testing.T) {
+	user := &Principal{}
+	user.ID = "user"
+	staff := &Principal{Permissions: []string{BOOKING_READ_ANY}}
+	staff.ID = "staff"
+
+	tests := []struct {
+		name        string
+		principal   *Principal
+		requesterID string
+		providerID  string
+		can         bool
+	}{
+		{name: "own requests", principal: user, requesterID: "user", can: true},
+		{name: "own jobs", principal: user, providerID: "user", can: true},
+		{name: "other user", principal: user, requesterID: "other"},
+		{name: "everyone", principal: user},
+		{name: "staff for other user", principal: staff, requesterID: "other", can: true},
+		{name: "staff for everyone", principal: staff, can: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CanListBookings(tt.principal, tt.requesterID, tt.providerID)
+			if got != tt.can {
+				t.Errorf("CanListBookings(%q, %q) = %v, want %v", tt.requesterID, tt.providerID, got, tt.can)
+			}
+		})
+	}
+}
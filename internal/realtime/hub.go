@@ -0,0 +1,104 @@
+// Package realtime pushes events to connected users. The hub delivers
+// events to the subscriptions of this instance; events published on
+// another instance reach it through Postgres LISTEN/NOTIFY.
+package realtime
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// event types
+const (
+	EVENT_NOTIFICATION = "notification"
+	EVENT_UNREAD_COUNT = "unread_count"
+	// EVENT_RESYNC tells clients that events may have been missed, and
+	// that they should fetch their notifications again.
+	EVENT_RESYNC = "resync"
+)
+
+// number of events buffered for a subscription. Events for a
+// subscription whose buffer is full are dropped.
+const subscriptionBuffer = 16
+
+// Event is pushed to a user.
+type Event struct {
+	UserID string          `json:"user_id"`
+	Type   string          `json:"type"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// Subscription receives the events of a user.
+type Subscription struct {
+	userID string
+	events chan Event
+}
+
+// Events returns the channel events are received on.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Hub delivers events to the subscriptions of this instance.
+type Hub struct {
+	mu            sync.RWMutex
+	subscriptions map[string]map[*Subscription]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{subscriptions: map[string]map[*Subscription]struct{}{}}
+}
+
+// Subscribe returns a subscription to the user's events. It must be
+// closed with Unsubscribe.
+func (h *Hub) Subscribe(userID string) *Subscription {
+	sub := &Subscription{
+		userID: userID,
+		events: make(chan Event, subscriptionBuffer),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.subscriptions[userID] == nil {
+		h.subscriptions[userID] = map[*Subscription]struct{}{}
+	}
+	h.subscriptions[userID][sub] = struct{}{}
+	return sub
+}
+
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.subscriptions[sub.userID], sub)
+	if len(h.subscriptions[sub.userID]) == 0 {
+		delete(h.subscriptions, sub.userID)
+	}
+}
+
+// Publish delivers the event to its user's subscriptions.
+func (h *Hub) Publish(event Event) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for sub := range h.subscriptions[event.UserID] {
+		deliver(sub, event)
+	}
+}
+
+// Broadcast delivers the event to every subscription.
+func (h *Hub) Broadcast(event Event) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, subs := range h.subscriptions {
+		for sub := range subs {
+			deliver(sub, event)
+		}
+	}
+}
+
+// deliver sends the event without blocking on a slow subscriber.
+func deliver(sub *Subscription, event Event) {
+	select {
+	case sub.events <- event:
+	default:
+	}
+}
@@ -0,0 +1,8 @@
+package repository
+
+import "github.com/lokatalent/backend_go/internal/models"
+
+type AuditRepository interface {
+	Create(entry *models.AuditLog) error
+	GetAll(filter models.AuditLogFilter) ([]models.AuditLog, error)
+}
@@ -1,17 +1,27 @@
 package repository
 
-import "github.com/lokatalent/backend_go/internal/models"
+import (
+	"time"
+
+	"github.com/lokatalent/backend_go/internal/models"
+)
 
 type BookingRepository interface {
 	Create(booking *models.Booking) error
 	GetByID(id string) (models.Booking, error)
 	GetAll(filter models.BookingFilter) ([]models.Booking, error)
+	// GetExpired returns up to limit bookings still waiting for a provider
+	// after their start time.
+	GetExpired(limit int) ([]models.Booking, error)
 
-	UpdateStatus(id, status string) (models.Booking, error)
-	AssignProvider(id, userID string) (models.Booking, error)
+	// status transitions are recorded as booking events, along with the
+	// payments and outbox messages they cause.
+	TransitionStatus(id string, event *models.BookingEvent, effects models.TransitionEffects) (models.Booking, error)
+	GetEvents(bookingID string) ([]models.BookingEvent, error)
 
 	RejectBooking(id, userID string) error
 	CheckRejected(id, userID string) (bool, error)
 
-	MatchServices(booking *models.Booking, filter models.ServiceFilter) ([]models.UserService, error)
+	HasOverlapping(providerID string, start, end time.Time) (bool, error)
+	MatchServices(booking *models.Booking, filter models.ServiceFilter) ([]models.ServiceMatch, error)
 }
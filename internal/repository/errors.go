@@ -7,5 +7,21 @@ var (
 	ErrDuplicateDetails     = errors.New("User with email or phone number already exist")
 	ErrDuplicateService     = errors.New("Service already exist.")
 	ErrDuplicateBankDetails = errors.New("Bank account number already exists.")
-	ErrInvalidWalletBalance = errors.New("Invalid user wallet balance.")
+	ErrInsufficientFunds    = errors.New("Insufficient account balance.")
+	ErrBookingStatusChanged = errors.New("Booking status has changed.")
+	ErrDuplicateReview      = errors.New("Booking already reviewed.")
+	ErrMissingCoordinates   = errors.New("Address has not been geocoded.")
+	ErrRefreshTokenReused   = errors.New("Refresh token has already been used.")
+	ErrTwoFactorEnabled     = errors.New("Two-factor authentication is already enabled.")
+	ErrDuplicateRole        = errors.New("Role already exists.")
+	ErrRoleInUse            = errors.New("Role is assigned to users.")
+	ErrUnknownPermission    = errors.New("Unknown permission.")
+	ErrIdentityLinked       = errors.New("Account is already linked.")
+	ErrActiveBookings       = errors.New("Account has unfinished bookings.")
+	ErrKYCPending           = errors.New("Identity verification is already awaiting review.")
+	ErrKYCReviewed          = errors.New("Identity verification has already been reviewed.")
+	ErrCertReviewed         = errors.New("Certification has already been reviewed.")
+	ErrNotificationExists   = errors.New("Notification already exists.")
+	ErrOutboxNotDead        = errors.New("Outbox message is not dead-lettered.")
+	ErrJobNotFailed         = errors.New("Job has not failed.")
 )
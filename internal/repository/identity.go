@@ -0,0 +1,12 @@
+package repository
+
+import "github.com/lokatalent/backend_go/internal/models"
+
+type IdentityRepository interface {
+	// Create links the identity, failing with ErrIdentityLinked if it, or
+	// another identity at the same provider, is already linked.
+	Create(identity *models.UserIdentity) error
+	GetBySubject(provider, subject string) (models.UserIdentity, error)
+	GetAll(userID string) ([]models.UserIdentity, error)
+	Delete(userID, provider string) error
+}
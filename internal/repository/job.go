@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/lokatalent/backend_go/internal/models"
+)
+
+type JobRepository interface {
+	Enqueue(job *models.Job) error
+	// EnqueueScheduled enqueues the job if the schedule named is due,
+	// moving its next run to next. It reports whether the job was
+	// enqueued, which happens on one instance only.
+	EnqueueScheduled(name string, next time.Time, job *models.Job) (bool, error)
+	// Claim marks the oldest job due of one of kinds as running, for up
+	// to the lease, after which it may be claimed again unless it was on
+	// its last attempt, in which case it is marked failed. It returns
+	// ErrRecordNotFound when no job is due.
+	Claim(kinds []string, lease time.Duration) (models.Job, error)
+	// Complete and Fail record the outcome of an attempt of a running
+	// job. They return ErrRecordNotFound when the attempt lost its lease
+	// to a newer one, whose outcome is kept.
+	Complete(id string, attempt int) error
+	// Fail records a failed attempt, running the job again at retryAt,
+	// or marking it failed when final is set.
+	Fail(id string, attempt int, lastError string, retryAt time.Time, final bool) error
+	GetAll(filter models.JobFilter) ([]models.Job, error)
+	// Retry runs a failed job again, resetting its attempts.
+	Retry(id string) (models.Job, error)
+	// Prune deletes the jobs completed before the time, returning how
+	// many were deleted.
+	Prune(before time.Time) (int64, error)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import "github.com/lokatalent/backend_go/internal/models"
+
+type KYCRepository interface {
+	// Create fails with ErrKYCPending if the user has a submission
+	// awaiting review.
+	Create(submission *models.KYCSubmission) error
+	GetByID(id string) (models.KYCSubmission, error)
+	// GetLatest returns the user's most recent submission.
+	GetLatest(userID string) (models.KYCSubmission, error)
+	GetAll(filter models.KYCFilter) ([]models.KYCSubmission, error)
+	// Review decides a pending submission, failing with ErrKYCReviewed if
+	// it has already been decided.
+	Review(submission *models.KYCSubmission) error
+}
@@ -0,0 +1,9 @@
+package repository
+
+import "github.com/lokatalent/backend_go/internal/models"
+
+type LedgerRepository interface {
+	PostEntry(entry *models.LedgerEntry) error
+	GetAccounts(filter models.LedgerFilter) ([]models.LedgerAccount, error)
+	GetEntries(filter models.LedgerFilter) ([]models.LedgerEntry, error)
+}
@@ -3,9 +3,27 @@ package repository
 import "github.com/lokatalent/backend_go/internal/models"
 
 type NotificationRepository interface {
-	Create(notification *models.Notification) error
+	// Create saves the notification and enqueues the outbox messages
+	// delivering it on other channels in the same transaction. It
+	// returns ErrNotificationExists if a notification with its ID was
+	// already saved.
+	Create(notification *models.Notification, messages ...models.OutboxMessage) error
 	GetForUser(filter models.NotificationFilter) ([]models.Notification, error)
 	MarkAsRead(userID, notificationID string) error
 	MarkAllAsRead(userID string) error
 	CountUnseen(userID string) (int64, error)
+
+	// GetPreferences returns the preferences the user changed from the
+	// defaults.
+	GetPreferences(userID string) ([]models.NotificationPreference, error)
+	SetPreference(preference *models.NotificationPreference) error
+	GetQuietHours(userID string) (models.QuietHours, error)
+	SetQuietHours(quietHours *models.QuietHours) error
+	DeleteQuietHours(userID string) error
+
+	// AddPushDevice registers the device to the user, taking it over
+	// from any user it was registered to before.
+	AddPushDevice(device *models.PushDevice) error
+	GetPushDevices(userID string) ([]models.PushDevice, error)
+	DeletePushDevice(userID, token string) error
 }
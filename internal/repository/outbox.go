@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/lokatalent/backend_go/internal/models"
+)
+
+type OutboxRepository interface {
+	// Enqueue adds messages to the outbox, skipping those whose ID was
+	// enqueued before.
+	Enqueue(messages ...models.OutboxMessage) error
+	// Claim returns up to limit pending messages due for delivery,
+	// counting the attempt and hiding them from other claims for the
+	// lease, so that each is delivered by one instance at a time.
+	Claim(limit int, lease time.Duration) ([]models.OutboxMessage, error)
+	MarkDelivered(id string) error
+	// MarkFailed records a failed attempt, retrying the message at
+	// retryAt, or dead-lettering it when dead is set.
+	MarkFailed(id, lastError string, retryAt time.Time, dead bool) error
+	GetAll(filter models.OutboxFilter) ([]models.OutboxMessage, error)
+	// Retry returns a dead message to pending, resetting its attempts.
+	Retry(id string) (models.OutboxMessage, error)
+}
@@ -6,13 +6,17 @@ type PaymentRepository interface {
 	// users wallets
 	CreateWallet(wallet *models.UserWallet) error
 	GetWallet(userID string) (models.UserWallet, error)
-	GetUserDebits(userID string) (float64, error)
-	UpdateWallet(userID, paymentType string, amount float64) error
+	GetUserDebits(userID string) (models.Money, error)
 
-	// transactions
-	CreatePayment(payment *models.Payment) error
+	// transactions, entries are posted to the ledger atomically with the
+	// payment change.
+	CreatePayment(payment *models.Payment, entries ...models.LedgerEntry) error
 	GetPayment(filter models.PaymentFilter) (models.Payment, error)
 	UpdatePaymentStatus(id, status string) (models.Payment, error)
+	TransitionPaymentStatus(id string, from []string, to string, entries ...models.LedgerEntry) (models.Payment, error)
+	// GetUserPayments returns the payments for the user's bookings and
+	// those posted to their ledger accounts.
+	GetUserPayments(userID string) ([]models.Payment, error)
 
 	// paystack transaction utilities
 	CreateAccessCode(id, paymentID, accessCode string) error
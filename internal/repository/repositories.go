@@ -9,4 +9,15 @@ type Repositories struct {
 	Booking        BookingRepository
 	Notification   NotificationRepository
 	Payment        PaymentRepository
+	Ledger         LedgerRepository
+	Review         ReviewRepository
+	Session        SessionRepository
+	Throttle       ThrottleRepository
+	TwoFactor      TwoFactorRepository
+	Role           RoleRepository
+	Audit          AuditRepository
+	Identity       IdentityRepository
+	KYC            KYCRepository
+	Outbox         OutboxRepository
+	Job            JobRepository
 }
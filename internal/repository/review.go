@@ -0,0 +1,14 @@
+package repository
+
+import "github.com/lokatalent/backend_go/internal/models"
+
+type ReviewRepository interface {
+	Create(review *models.Review) error
+	GetForBooking(bookingID string) ([]models.Review, error)
+	GetForUser(filter models.ReviewFilter) ([]models.Review, error)
+
+	// rating summaries only count reviews left by requesters, i.e. the
+	// rating of a user as a provider.
+	GetSummary(userID string) (models.RatingSummary, error)
+	GetSummaries(userIDs []string) (map[string]models.RatingSummary, error)
+}
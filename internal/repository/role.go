@@ -0,0 +1,14 @@
+package repository
+
+import "github.com/lokatalent/backend_go/internal/models"
+
+type RoleRepository interface {
+	Create(role *models.Role) error
+	Get(name string) (models.Role, error)
+	GetAll() ([]models.Role, error)
+	Update(role *models.Role) error
+	// Delete removes a role no user is assigned to.
+	Delete(name string) error
+
+	GetPermissions() ([]models.Permission, error)
+}
@@ -0,0 +1,18 @@
+package repository
+
+import "github.com/lokatalent/backend_go/internal/models"
+
+type SessionRepository interface {
+	// Create stores a new session along with its first refresh token.
+	Create(session *models.Session, token *models.RefreshToken) error
+	GetByID(id string) (models.Session, error)
+	GetActive(userID string) ([]models.Session, error)
+
+	GetRefreshToken(tokenHash string) (models.RefreshToken, error)
+	// Rotate marks the previous token as used and stores the next one.
+	// An empty previousID supersedes every unused token of the session.
+	Rotate(sessionID, previousID string, next *models.RefreshToken) error
+
+	Revoke(id, userID string) error
+	RevokeAll(userID string) error
+}
@@ -2,9 +2,13 @@ package repository
 
 import (
 	"io"
+	"time"
 )
 
 type StorageRepository interface {
 	UploadFile(file io.Reader, key string, contentType string) (string, error)
 	DeleteFile(key string) error
+	// GetFileURL returns a URL granting temporary access to a private
+	// file.
+	GetFileURL(key string, expires time.Duration) (string, error)
 }
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/lokatalent/backend_go/internal/models"
+)
+
+type ThrottleRepository interface {
+	Get(key string) (models.AuthThrottle, error)
+	// RecordFailure counts a failed attempt, forgetting failures older
+	// than window.
+	RecordFailure(key string, window time.Duration) (models.AuthThrottle, error)
+	Lock(key string, until time.Time, lockouts int) error
+	Reset(key string) error
+}
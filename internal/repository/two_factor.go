@@ -0,0 +1,19 @@
+package repository
+
+import "github.com/lokatalent/backend_go/internal/models"
+
+type TwoFactorRepository interface {
+	Get(userID string) (models.TwoFactor, error)
+	// Enroll stores a new, not yet enabled, secret for the user.
+	Enroll(twoFactor *models.TwoFactor) error
+	// Enable confirms the enrollment with the step of its first code,
+	// replacing the user's recovery codes.
+	Enable(userID string, step int64, recoveryCodeHashes []string) error
+	Delete(userID string) error
+
+	// UseStep records the step of a code, failing with
+	// ErrRecordNotFound if it, or a later one, was already used.
+	UseStep(userID string, step int64) error
+	UseRecoveryCode(userID, codeHash string) error
+	ReplaceRecoveryCodes(userID string, codeHashes []string) error
+}
@@ -1,6 +1,10 @@
 package repository
 
-import "github.com/lokatalent/backend_go/internal/models"
+import (
+	"time"
+
+	"github.com/lokatalent/backend_go/internal/models"
+)
 
 type UserRepository interface {
 	Create(user *models.User) error
@@ -19,17 +23,46 @@ type UserRepository interface {
 	CreateVerificationCode(verCode *models.UserVerificationCode) error
 	DeleteVerificationCode(id, verificationType string) error
 	GetVerificationCode(id, verificationType string) (models.UserVerificationCode, error)
+	// DeleteExpiredVerificationCodes returns the number of codes deleted.
+	DeleteExpiredVerificationCodes() (int64, error)
+	IncrementVerificationAttempts(id string) error
+
+	UpdatePassword(id, hashedPassword string) error
+	CreatePasswordReset(reset *models.PasswordReset) error
+	GetPasswordReset(userID string) (models.PasswordReset, error)
+	IncrementPasswordResetAttempts(id string) error
+	// ConsumePasswordReset deletes the reset, failing with
+	// ErrRecordNotFound if it has already been used.
+	ConsumePasswordReset(id string) error
+	DeletePasswordReset(userID string) error
 
 	CreateBankInfo(bankInfo *models.UserBankInfo) error
 	GetBankInfo(userID string) (models.UserBankInfo, error)
 	UpdateBankInfo(bankInfo *models.UserBankInfo) error
 
-	CreateEducationInfo(eduInfo *models.UserEducationInfo) error
-	GetEducationInfo(userID string) (models.UserEducationInfo, error)
-	UpdateEducationInfo(eduInfo *models.UserEducationInfo) error
+	CreateEducation(education *models.UserEducation) error
+	GetEducation(userID string) ([]models.UserEducation, error)
+	UpdateEducation(education *models.UserEducation) error
+	DeleteEducation(id, userID string) error
+
+	CreateExperience(experience *models.UserExperience) error
+	GetExperience(userID string) ([]models.UserExperience, error)
+	UpdateExperience(experience *models.UserExperience) error
+	DeleteExperience(id, userID string) error
 
 	CreateCertification(cert *models.UserCertification) error
+	GetCertification(id string) (models.UserCertification, error)
 	GetCertifications(userID string) ([]models.UserCertification, error)
+	// GetApprovedCertifications returns the user's approved certifications
+	// that have not expired.
+	GetApprovedCertifications(userID string) ([]models.UserCertification, error)
+	GetAllCertifications(filter models.CertificationFilter) ([]models.UserCertification, error)
+	// ReviewCertification decides a pending certification, failing with
+	// ErrCertReviewed if it has already been decided.
+	ReviewCertification(cert *models.UserCertification) error
+	// ExpireCertifications marks approved certifications past their
+	// expiry date as expired, returning them.
+	ExpireCertifications() ([]models.UserCertification, error)
 	DeleteCertification(id, userID string) error
 
 	CreateService(service *models.UserService) error
@@ -37,11 +70,38 @@ type UserRepository interface {
 	GetAllServices(userID string) ([]models.UserService, error)
 	UpdateService(service *models.UserService) error
 	DeleteService(userID, serviceType string) error
+	// GetUngeocodedServices returns up to limit services with an address
+	// but no coordinates, ordered by id after afterID.
+	GetUngeocodedServices(afterID string, limit int) ([]models.UserService, error)
+	UpdateServiceLocation(id string, location models.Location) error
 
 	CreateServiceImage(seviceImg *models.ServiceImage) error
 	GetServiceImages(userID, serviceType string) ([]models.ServiceImage, error)
 	DeleteServiceImage(id, userID, serviceType string) error
 
+	UpdateServiceAvailability(userID, serviceType string, availability models.Availability) (models.UserService, error)
+	CreateAvailabilityException(exception *models.AvailabilityException) error
+	GetAvailabilityException(id, userID string) (models.AvailabilityException, error)
+	GetAvailabilityExceptions(userID string, from, to time.Time) ([]models.AvailabilityException, error)
+	UpdateAvailabilityException(exception *models.AvailabilityException) error
+	DeleteAvailabilityException(id, userID string) error
+
+	// account deletion
+	ScheduleDeletion(deletion *models.AccountDeletion) error
+	GetDeletion(userID string) (models.AccountDeletion, error)
+	// CancelDeletion fails with ErrRecordNotFound unless a deletion is
+	// pending.
+	CancelDeletion(userID string) error
+	GetDueDeletions(limit int) ([]models.AccountDeletion, error)
+	// RescheduleDeletion moves a pending deletion to scheduledFor, so that
+	// a failing deletion does not hold back the ones due after it.
+	RescheduleDeletion(userID string, scheduledFor time.Time) error
+	HasActiveBookings(userID string) (bool, error)
+	// Anonymize erases the user's personal data and completes their
+	// deletion, keeping their bookings and payments. It fails with
+	// ErrActiveBookings while any of their bookings is unfinished.
+	Anonymize(userID string) error
+
 	// waitlist
 	JoinWaitlist(email string) error
 }
@@ -3,6 +3,7 @@ package s3
 import (
 	"context"
 	"io"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
@@ -58,3 +59,20 @@ func (s storage) DeleteFile(key string) error {
 
 	return nil
 }
+
+func (s storage) GetFileURL(key string, expires time.Duration) (string, error) {
+	presigner := s3.NewPresignClient(s.client)
+	request, err := presigner.PresignGetObject(
+		context.Background(),
+		&s3.GetObjectInput{
+			Bucket: &s.bucket,
+			Key:    &key,
+		},
+		s3.WithPresignExpires(expires),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	return request.URL, nil
+}
@@ -0,0 +1,267 @@
+// Package apple implements Sign in with Apple as a goth provider. Unlike
+// other providers Apple has no profile endpoint: the user is identified by
+// the ID token returned with the access token, and the app authenticates
+// with a client secret it signs itself with a key issued by Apple.
+package apple
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/markbates/goth"
+	"golang.org/x/oauth2"
+
+	"github.com/lokatalent/backend_go/internal/keyset"
+)
+
+const (
+	authURL  = "https://appleid.apple.com/auth/authorize"
+	tokenURL = "https://appleid.apple.com/auth/token"
+	keysURL  = "https://appleid.apple.com/auth/keys"
+	issuer   = "https://appleid.apple.com"
+
+	clientSecretDuration = 5 * time.Minute
+
+	// Apple rotates its keys rarely. An unknown key ID triggers a refetch,
+	// at most once per refetch interval.
+	keysCacheDuration   = 24 * time.Hour
+	keysRefetchInterval = time.Minute
+)
+
+var ErrNoIDToken = errors.New("apple: no ID token, session is not authorized")
+
+// Provider authenticates users with their Apple ID.
+type Provider struct {
+	ClientID    string // services ID
+	TeamID      string
+	KeyID       string
+	PrivateKey  *ecdsa.PrivateKey
+	CallbackURL string
+	HTTPClient  *http.Client
+
+	providerName string
+
+	mu            sync.Mutex
+	keys          map[string]crypto.PublicKey
+	keysFetchedAt time.Time
+}
+
+// New returns a provider for the services ID, authenticating with the
+// private key (a .p8 file) identified by keyID.
+func New(clientID, teamID, keyID string, privateKey *ecdsa.PrivateKey, callbackURL string) *Provider {
+	return &Provider{
+		ClientID:     clientID,
+		TeamID:       teamID,
+		KeyID:        keyID,
+		PrivateKey:   privateKey,
+		CallbackURL:  callbackURL,
+		providerName: "apple",
+	}
+}
+
+// ParsePrivateKey parses the PEM encoded PKCS #8 key downloaded from the
+// Apple developer account.
+func ParsePrivateKey(data []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	ecKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("expected an ECDSA private key")
+	}
+	return ecKey, nil
+}
+
+func (p *Provider) Name() string {
+	return p.providerName
+}
+
+func (p *Provider) SetName(name string) {
+	p.providerName = name
+}
+
+func (p *Provider) Client() *http.Client {
+	return goth.HTTPClientWithFallBack(p.HTTPClient)
+}
+
+func (p *Provider) Debug(debug bool) {}
+
+// BeginAuth asks Apple to post the authorization code, and the user's
+// name on their first sign-in, back to the callback URL.
+func (p *Provider) BeginAuth(state string) (goth.Session, error) {
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("response_mode", "form_post")
+	params.Set("client_id", p.ClientID)
+	params.Set("redirect_uri", p.CallbackURL)
+	params.Set("scope", "name email")
+	params.Set("state", state)
+
+	return &Session{AuthURL: authURL + "?" + params.Encode()}, nil
+}
+
+func (p *Provider) UnmarshalSession(data string) (goth.Session, error) {
+	sess := &Session{}
+	err := json.Unmarshal([]byte(data), sess)
+	return sess, err
+}
+
+// FetchUser returns the user identified by the session's ID token.
+func (p *Provider) FetchUser(session goth.Session) (goth.User, error) {
+	sess := session.(*Session)
+	user := goth.User{
+		Provider:     p.Name(),
+		AccessToken:  sess.AccessToken,
+		RefreshToken: sess.RefreshToken,
+		ExpiresAt:    sess.ExpiresAt,
+		IDToken:      sess.IDToken,
+		FirstName:    sess.FirstName,
+		LastName:     sess.LastName,
+	}
+	if sess.IDToken == "" {
+		return user, ErrNoIDToken
+	}
+
+	claims := &idTokenClaims{}
+	_, err := jwt.ParseWithClaims(
+		sess.IDToken,
+		claims,
+		p.keyfunc,
+		jwt.WithValidMethods([]string{keyset.ALG_RS256}),
+		jwt.WithIssuer(issuer),
+		jwt.WithAudience(p.ClientID),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return user, fmt.Errorf("apple: invalid ID token: %w", err)
+	}
+
+	user.UserID = claims.Subject
+	user.Email = claims.Email
+	user.Name = sess.FirstName + " " + sess.LastName
+	user.RawData = map[string]interface{}{
+		"email_verified":   bool(claims.EmailVerified),
+		"is_private_email": bool(claims.IsPrivateEmail),
+	}
+	return user, nil
+}
+
+func (p *Provider) RefreshTokenAvailable() bool {
+	return false
+}
+
+func (p *Provider) RefreshToken(refreshToken string) (*oauth2.Token, error) {
+	return nil, errors.New("apple: refresh tokens are not supported")
+}
+
+// clientSecret returns the short-lived JWT authenticating the app to
+// Apple's token endpoint.
+func (p *Provider) clientSecret() (string, error) {
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.RegisteredClaims{
+		Issuer:    p.TeamID,
+		Subject:   p.ClientID,
+		Audience:  jwt.ClaimStrings{issuer},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(clientSecretDuration)),
+	})
+	token.Header["kid"] = p.KeyID
+	return token.SignedString(p.PrivateKey)
+}
+
+// keyfunc selects the Apple key an ID token is signed with.
+func (p *Provider) keyfunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key, ok := p.keys[kid]
+	if ok && time.Since(p.keysFetchedAt) < keysCacheDuration {
+		return key, nil
+	}
+	if !ok && time.Since(p.keysFetchedAt) < keysRefetchInterval {
+		return nil, keyset.ErrUnknownKey
+	}
+	if err := p.fetchKeys(); err != nil {
+		return nil, err
+	}
+	key, ok = p.keys[kid]
+	if !ok {
+		return nil, keyset.ErrUnknownKey
+	}
+	return key, nil
+}
+
+func (p *Provider) fetchKeys() error {
+	resp, err := p.Client().Get(keysURL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("apple: fetching keys: %s", resp.Status)
+	}
+
+	set := keyset.JWKSet{}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return err
+	}
+	keys := map[string]crypto.PublicKey{}
+	for _, jwk := range set.Keys {
+		key, err := jwk.PublicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	p.keys = keys
+	p.keysFetchedAt = time.Now()
+	return nil
+}
+
+type idTokenClaims struct {
+	Email          string       `json:"email"`
+	EmailVerified  boolOrString `json:"email_verified"`
+	IsPrivateEmail boolOrString `json:"is_private_email"`
+	jwt.RegisteredClaims
+}
+
+// boolOrString decodes booleans Apple sends either as JSON booleans or as
+// "true" and "false" strings.
+type boolOrString bool
+
+func (b *boolOrString) UnmarshalJSON(data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case bool:
+		*b = boolOrString(v)
+	case string:
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			return err
+		}
+		*b = boolOrString(parsed)
+	}
+	return nil
+}
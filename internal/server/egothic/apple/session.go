@@ -0,0 +1,84 @@
+package apple
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/markbates/goth"
+	"golang.org/x/oauth2"
+)
+
+// Session stores data during the auth process with Apple.
+type Session struct {
+	AuthURL      string
+	AccessToken  string
+	RefreshToken string
+	IDToken      string
+	ExpiresAt    time.Time
+
+	// only sent on the user's first sign-in.
+	FirstName string
+	LastName  string
+}
+
+func (s Session) GetAuthURL() (string, error) {
+	if s.AuthURL == "" {
+		return "", errors.New(goth.NoAuthUrlErrorMessage)
+	}
+	return s.AuthURL, nil
+}
+
+// Authorize exchanges the authorization code posted to the callback for
+// the user's tokens.
+func (s *Session) Authorize(provider goth.Provider, params goth.Params) (string, error) {
+	p := provider.(*Provider)
+
+	secret, err := p.clientSecret()
+	if err != nil {
+		return "", err
+	}
+	config := oauth2.Config{
+		ClientID:     p.ClientID,
+		ClientSecret: secret,
+		RedirectURL:  p.CallbackURL,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:   authURL,
+			TokenURL:  tokenURL,
+			AuthStyle: oauth2.AuthStyleInParams,
+		},
+	}
+	token, err := config.Exchange(goth.ContextForClient(p.Client()), params.Get("code"))
+	if err != nil {
+		return "", err
+	}
+	idToken, _ := token.Extra("id_token").(string)
+	if idToken == "" {
+		return "", ErrNoIDToken
+	}
+
+	s.AccessToken = token.AccessToken
+	s.RefreshToken = token.RefreshToken
+	s.ExpiresAt = token.Expiry
+	s.IDToken = idToken
+
+	if data := params.Get("user"); data != "" {
+		user := struct {
+			Name struct {
+				FirstName string `json:"firstName"`
+				LastName  string `json:"lastName"`
+			} `json:"name"`
+		}{}
+		if err := json.Unmarshal([]byte(data), &user); err == nil {
+			s.FirstName = user.Name.FirstName
+			s.LastName = user.Name.LastName
+		}
+	}
+
+	return token.AccessToken, nil
+}
+
+func (s Session) Marshal() string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
@@ -237,3 +237,41 @@ func StoreInSession(e echo.Context, key string, value string) error {
 func GetFromSession(e echo.Context, key string) (string, error) {
 	return gothic.GetFromSession(key, e.Request())
 }
+
+// linkSessionName is the session carrying the token of a user linking
+// their account with a provider. It is kept apart from the gothic session,
+// which is replaced whenever the provider's session data is stored.
+const linkSessionName = "_egothic_link"
+
+const linkTokenKey = "link_token"
+
+// SetLinkToken stores the token of the user linking their account for the
+// rest of the authentication process.
+func SetLinkToken(e echo.Context, token string) error {
+	session, err := Store().New(e.Request(), linkSessionName)
+	if err != nil {
+		return err
+	}
+	session.Values[linkTokenKey] = token
+	return session.Save(e.Request(), e.Response())
+}
+
+// PopLinkToken returns the token stored with SetLinkToken, if any, and
+// removes it from the session.
+func PopLinkToken(e echo.Context) (string, error) {
+	session, err := Store().Get(e.Request(), linkSessionName)
+	if err != nil {
+		return "", err
+	}
+	token, ok := session.Values[linkTokenKey].(string)
+	if !ok {
+		return "", nil
+	}
+
+	session.Options.MaxAge = -1
+	session.Values = make(map[interface{}]interface{})
+	if err := session.Save(e.Request(), e.Response()); err != nil {
+		return "", err
+	}
+	return token, nil
+}
@@ -0,0 +1,303 @@
+package handlers
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/lokatalent/backend_go/cmd/api/models/response"
+	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/repository"
+)
+
+const (
+	accountDeletionTmpl = "account_deletion_scheduled.gotmpl"
+
+	// number of records fetched at a time while exporting a user's data.
+	dataExportBatchSize = 500
+)
+
+// ExportData returns a ZIP archive of the current user's personal data,
+// with a JSON file for each kind of record.
+func (u UserHandler) ExportData(ctx echo.Context) error {
+	authenticatedUser := util.ContextGetUser(ctx)
+
+	user, err := u.app.Repositories.User.GetByID(authenticatedUser.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	files, err := u.exportFiles(&user)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	resp := ctx.Response()
+	resp.Header().Set(echo.HeaderContentType, "application/zip")
+	resp.Header().Set(
+		echo.HeaderContentDisposition,
+		fmt.Sprintf(`attachment; filename="lokatalent-data-%s.zip"`, time.Now().UTC().Format(time.DateOnly)),
+	)
+	resp.WriteHeader(http.StatusOK)
+
+	archive := zip.NewWriter(resp)
+	for _, file := range files {
+		writer, err := archive.Create(file.name)
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.data); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// GetAccountDeletion returns the current user's pending account deletion.
+func (u UserHandler) GetAccountDeletion(ctx echo.Context) error {
+	authenticatedUser := util.ContextGetUser(ctx)
+
+	deletion, err := u.app.Repositories.User.GetDeletion(authenticatedUser.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, deletion)
+}
+
+// RequestAccountDeletion schedules the current user's account to be
+// anonymized once the grace period ends. Users with a password confirm
+// it, and users with unfinished bookings or a wallet balance must settle
+// them first.
+func (u UserHandler) RequestAccountDeletion(ctx echo.Context) error {
+	reqData := struct {
+		Password string `json:"password"`
+	}{}
+
+	if err := ctx.Bind(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	authenticatedUser := util.ContextGetUser(ctx)
+	user, err := u.app.Repositories.User.GetByID(authenticatedUser.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+	if user.Password != "" {
+		if err := util.ValidatePassword(reqData.Password, user.Password); err != nil {
+			return echo.NewHTTPError(http.StatusUnauthorized, ErrInvalidPassword)
+		}
+	}
+
+	active, err := u.app.Repositories.User.HasActiveBookings(user.ID)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+	if active {
+		return echo.NewHTTPError(http.StatusConflict, repository.ErrActiveBookings)
+	}
+
+	wallet, err := u.app.Repositories.Payment.GetWallet(user.ID)
+	if err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
+		return util.ErrInternalServer(ctx, err)
+	}
+	if err == nil && !wallet.Balance.IsZero() {
+		return echo.NewHTTPError(http.StatusConflict, ErrWalletNotEmpty)
+	}
+
+	deletion := models.AccountDeletion{
+		UserID:       user.ID,
+		ScheduledFor: time.Now().Add(util.AccountDeletionGracePeriod),
+	}
+	err = u.app.Repositories.User.ScheduleDeletion(&deletion)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	if user.Email != "" {
+		err := u.app.Mailer.Send(
+			user.Email,
+			accountDeletionTmpl,
+			struct {
+				FirstName    string
+				ScheduledFor string
+				Year         int
+			}{
+				FirstName:    user.FirstName,
+				ScheduledFor: deletion.ScheduledFor.UTC().Format("Jan 2, 2006"),
+				Year:         time.Now().Year(),
+			},
+		)
+		if err != nil {
+			_ = util.ErrInternalServer(ctx, err)
+		}
+	}
+
+	return ctx.JSON(http.StatusAccepted, deletion)
+}
+
+// CancelAccountDeletion cancels the current user's pending account
+// deletion.
+func (u UserHandler) CancelAccountDeletion(ctx echo.Context) error {
+	authenticatedUser := util.ContextGetUser(ctx)
+
+	err := u.app.Repositories.User.CancelDeletion(authenticatedUser.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, echo.Map{})
+}
+
+// helpers
+
+type exportFile struct {
+	name string
+	data any
+}
+
+// exportFiles gathers the user's data for ExportData.
+func (u UserHandler) exportFiles(user *models.User) ([]exportFile, error) {
+	repos := u.app.Repositories
+
+	education, err := repos.User.GetEducation(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	experience, err := repos.User.GetExperience(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	bankInfo, err := repos.User.GetBankInfo(user.ID)
+	if err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
+		return nil, err
+	}
+	var bankData any
+	if err == nil {
+		bankInfo.AccountNum = maskAccountNum(bankInfo.AccountNum)
+		bankData = bankInfo
+	}
+
+	certifications, err := repos.User.GetCertifications(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	services, err := repos.User.GetAllServices(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	identities, err := repos.Identity.GetAll(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	kycSubmissions, err := repos.KYC.GetAll(models.KYCFilter{
+		UserID: user.ID,
+		Page:   1,
+		Limit:  dataExportBatchSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bookings := []response.BookingResponse{}
+	for _, filter := range []models.BookingFilter{
+		{RequesterID: user.ID},
+		{ProviderID: user.ID},
+	} {
+		filter.Page = 1
+		filter.Limit = dataExportBatchSize
+		for {
+			page, err := repos.Booking.GetAll(filter)
+			if err != nil {
+				return nil, err
+			}
+			for _, booking := range page {
+				bookings = append(bookings, response.BookingResponseFromModel(booking))
+			}
+			if len(page) < filter.Limit {
+				break
+			}
+			filter.Page++
+		}
+	}
+
+	notificationPreferences, err := repos.Notification.GetPreferences(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	pushDevices, err := repos.Notification.GetPushDevices(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := repos.Payment.GetUserPayments(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	notifications := []response.NotificationResponse{}
+	notificationFilter := models.NotificationFilter{
+		UserID: user.ID,
+		Page:   1,
+		Limit:  dataExportBatchSize,
+	}
+	for {
+		page, err := repos.Notification.GetForUser(notificationFilter)
+		if err != nil {
+			return nil, err
+		}
+		for _, notification := range page {
+			notifications = append(notifications, response.NotificationResponseFromModel(notification))
+		}
+		if len(page) < notificationFilter.Limit {
+			break
+		}
+		notificationFilter.Page++
+	}
+
+	return []exportFile{
+		{name: "profile.json", data: response.UserResponseFromModel(user)},
+		{name: "education.json", data: education},
+		{name: "experience.json", data: experience},
+		{name: "bank_info.json", data: bankData},
+		{name: "certifications.json", data: certifications},
+		{name: "services.json", data: services},
+		{name: "identities.json", data: identities},
+		{name: "kyc_submissions.json", data: kycSubmissions},
+		{name: "bookings.json", data: bookings},
+		{name: "payments.json", data: payments},
+		{name: "notifications.json", data: notifications},
+		{name: "notification_preferences.json", data: notificationPreferences},
+		{name: "push_devices.json", data: pushDevices},
+	}, nil
+}
+
+// maskAccountNum hides all but the last four digits of an account number.
+func maskAccountNum(accountNum string) string {
+	if len(accountNum) <= 4 {
+		return accountNum
+	}
+	return strings.Repeat("*", len(accountNum)-4) + accountNum[len(accountNum)-4:]
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/audit"
+	"github.com/lokatalent/backend_go/internal/models"
+)
+
+// number of entries fetched at a time while exporting the audit log.
+const auditExportBatchSize = 500
+
+type AuditHandler struct {
+	app *util.Application
+}
+
+func NewAuditHandler(app *util.Application) AuditHandler {
+	return AuditHandler{app: app}
+}
+
+func (a AuditHandler) GetAuditLog(ctx echo.Context) error {
+	filter, err := auditFilterFromQuery(ctx)
+	if err != nil {
+		return err
+	}
+
+	entries, err := a.app.Repositories.Audit.GetAll(filter)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, entries)
+}
+
+// ExportAuditLog writes every entry matching the filter as CSV, ignoring
+// pagination.
+func (a AuditHandler) ExportAuditLog(ctx echo.Context) error {
+	filter, err := auditFilterFromQuery(ctx)
+	if err != nil {
+		return err
+	}
+	filter.Page = 1
+	filter.Limit = auditExportBatchSize
+
+	resp := ctx.Response()
+	resp.Header().Set(echo.HeaderContentType, "text/csv")
+	resp.Header().Set(
+		echo.HeaderContentDisposition,
+		fmt.Sprintf(`attachment; filename="audit-log-%s.csv"`, time.Now().UTC().Format(time.DateOnly)),
+	)
+
+	writer := csv.NewWriter(resp)
+	header := false
+	for {
+		entries, err := a.app.Repositories.Audit.GetAll(filter)
+		if err != nil {
+			// the response has started once the header is written
+			if !header {
+				return util.ErrInternalServer(ctx, err)
+			}
+			ctx.Logger().Error(err)
+			return nil
+		}
+
+		if !header {
+			resp.WriteHeader(http.StatusOK)
+			err := writer.Write([]string{
+				"id", "created_at", "actor_id", "action", "entity_type",
+				"entity_id", "before", "after", "ip_address", "user_agent",
+			})
+			if err != nil {
+				return err
+			}
+			header = true
+		}
+		for _, entry := range entries {
+			err := writer.Write([]string{
+				entry.ID,
+				entry.CreatedAt.UTC().Format(time.RFC3339),
+				entry.ActorID.String,
+				entry.Action,
+				entry.EntityType,
+				entry.EntityID,
+				string(entry.Before),
+				string(entry.After),
+				entry.IPAddress,
+				entry.UserAgent,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return err
+		}
+
+		if len(entries) < filter.Limit {
+			return nil
+		}
+		filter.Page++
+	}
+}
+
+// helpers
+
+// recordAudit adds a privileged action by the current user to the audit
+// log, along with the fields it changed. before is nil for created
+// entities and after is nil for deleted ones. The action has already
+// taken effect, so failures are logged rather than returned.
+func recordAudit(ctx echo.Context, app *util.Application, action, entityType, entityID string, before, after any) {
+	beforeJSON, afterJSON, err := audit.Diff(before, after)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return
+	}
+
+	actorID := util.ContextGetUser(ctx).ID
+	entry := models.AuditLog{
+		ActorID:    sql.NullString{String: actorID, Valid: actorID != ""},
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     beforeJSON,
+		After:      afterJSON,
+		IPAddress:  ctx.RealIP(),
+		UserAgent:  ctx.Request().UserAgent(),
+	}
+	if err := app.Repositories.Audit.Create(&entry); err != nil {
+		ctx.Logger().Error(err)
+	}
+}
+
+func auditFilterFromQuery(ctx echo.Context) (models.AuditLogFilter, error) {
+	filter := models.AuditLogFilter{
+		ActorID:    ctx.QueryParam("actor_id"),
+		Action:     ctx.QueryParam("action"),
+		EntityType: ctx.QueryParam("entity_type"),
+		EntityID:   ctx.QueryParam("entity_id"),
+		Page:       models.DefaultPage,
+		Limit:      models.DefaultPageLimit,
+	}
+
+	if filter.ActorID != "" && !util.IsValidUUID(filter.ActorID) {
+		return filter, echo.NewHTTPError(http.StatusBadRequest, "invalid actor id")
+	}
+	if from := ctx.QueryParam("from"); from != "" {
+		fromTime, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return filter, echo.NewHTTPError(http.StatusBadRequest, "invalid from time, expected RFC 3339")
+		}
+		filter.From = fromTime
+	}
+	if to := ctx.QueryParam("to"); to != "" {
+		toTime, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return filter, echo.NewHTTPError(http.StatusBadRequest, "invalid to time, expected RFC 3339")
+		}
+		filter.To = toTime
+	}
+	if page := ctx.QueryParam("page"); page != "" {
+		reqPage, err := strconv.Atoi(page)
+		if err != nil || reqPage < 1 {
+			return filter, echo.NewHTTPError(http.StatusBadRequest, "invalid page value")
+		}
+		filter.Page = reqPage
+	}
+	if size := ctx.QueryParam("size"); size != "" {
+		reqSize, err := strconv.Atoi(size)
+		if err != nil || reqSize < 1 {
+			return filter, echo.NewHTTPError(http.StatusBadRequest, "invalid size value")
+		}
+		filter.Limit = reqSize
+	}
+
+	return filter, nil
+}
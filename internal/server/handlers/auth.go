@@ -11,22 +11,27 @@ import (
 	"github.com/gorilla/sessions"
 	"github.com/labstack/echo/v4"
 	"github.com/markbates/goth"
+	"github.com/markbates/goth/providers/facebook"
 	"github.com/markbates/goth/providers/google"
 
 	"github.com/lokatalent/backend_go/cmd/api/models/response"
 	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/audit"
 	"github.com/lokatalent/backend_go/internal/models"
 	"github.com/lokatalent/backend_go/internal/repository"
 	"github.com/lokatalent/backend_go/internal/server/egothic"
+	"github.com/lokatalent/backend_go/internal/server/egothic/apple"
+	"github.com/lokatalent/backend_go/internal/throttle"
 )
 
 const (
-	verificationCodeMin   = 100000
-	verificationCodeMax   = 999999
-	emailVerificationTmpl = "email_verification.gotmpl"
-	phoneVerificationTmpl = "phone_verification.gotmpl"
-	registrationTmpl      = "registration_confirmation.gotmpl"
-	waitlistTmpl          = "waitlist_confirmation.gotmpl"
+	verificationCodeMin     = 100000
+	verificationCodeMax     = 999999
+	maxVerificationAttempts = 5
+	emailVerificationTmpl   = "email_verification.gotmpl"
+	phoneVerificationTmpl   = "phone_verification.gotmpl"
+	registrationTmpl        = "registration_confirmation.gotmpl"
+	waitlistTmpl            = "waitlist_confirmation.gotmpl"
 )
 
 type AuthHandler struct {
@@ -54,6 +59,33 @@ func NewAuthHandler(app *util.Application) AuthHandler {
 			"openid",
 		),
 	)
+	if app.Config.Facebook.ClientID != "" {
+		goth.UseProviders(
+			facebook.New(
+				app.Config.Facebook.ClientID,
+				app.Config.Facebook.ClientSecret,
+				fmt.Sprintf(
+					"%s/auth/facebook/callback",
+					app.Config.Origin,
+				),
+				"email",
+			),
+		)
+	}
+	if app.Config.Apple.ClientID != "" {
+		goth.UseProviders(
+			apple.New(
+				app.Config.Apple.ClientID,
+				app.Config.Apple.TeamID,
+				app.Config.Apple.KeyID,
+				app.Config.Apple.PrivateKey,
+				fmt.Sprintf(
+					"%s/auth/apple/callback",
+					app.Config.Origin,
+				),
+			),
+		)
+	}
 	return AuthHandler{app: app}
 }
 
@@ -147,6 +179,7 @@ func (a AuthHandler) SignIn(ctx echo.Context) error {
 		Email    string `json:"email"`
 		PhoneNum string `json:"phone_num"`
 		Password string `json:"password" validate:"required,min=8"`
+		Device   string `json:"device" validate:"max=100"`
 	}{}
 
 	if err := ctx.Bind(&reqData); err != nil {
@@ -156,6 +189,12 @@ func (a AuthHandler) SignIn(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err)
 	}
 
+	// reject addresses locked out by repeated failures
+	ipKey := throttle.IPKey(ctx.RealIP())
+	if err := a.checkThrottle(ctx, ipKey); err != nil {
+		return err
+	}
+
 	var user models.User
 	var err error
 	switch {
@@ -171,6 +210,9 @@ func (a AuthHandler) SignIn(ctx echo.Context) error {
 	}
 	if err != nil {
 		if errors.Is(err, repository.ErrRecordNotFound) {
+			if _, err := a.recordFailure(ipKey, throttle.IP); err != nil {
+				return util.ErrInternalServer(ctx, err)
+			}
 			return echo.NewHTTPError(
 				http.StatusNotFound,
 				repository.ErrRecordNotFound)
@@ -178,33 +220,50 @@ func (a AuthHandler) SignIn(ctx echo.Context) error {
 		return util.ErrInternalServer(ctx, err)
 	}
 
+	accountKey := throttle.AccountKey(user.ID)
+	if err := a.checkThrottle(ctx, accountKey); err != nil {
+		return err
+	}
+
 	// validate password
 	err = util.ValidatePassword(reqData.Password, user.Password)
 	if err != nil {
+		if _, err := a.recordFailure(ipKey, throttle.IP); err != nil {
+			return util.ErrInternalServer(ctx, err)
+		}
+		lockedUntil, err := a.recordFailure(accountKey, throttle.Account)
+		if err != nil {
+			return util.ErrInternalServer(ctx, err)
+		}
+		if lockedUntil != nil {
+			a.notifyLockout(ctx, &user, *lockedUntil)
+		}
 		return echo.NewHTTPError(
 			http.StatusUnauthorized,
 			ErrInvalidPassword)
 	}
 
-	// generate access and refresh tokens
-	accessToken, refreshToken, expiration, err := util.GenerateTokens(a.app, &user)
+	err = a.app.Repositories.Throttle.Reset(accountKey)
 	if err != nil {
 		return util.ErrInternalServer(ctx, err)
 	}
 
-	resp := response.AuthResponse{
-		TokensResponse: response.TokensResponse{
-			AccessToken:  accessToken,
-			RefreshToken: refreshToken,
-			ExpiresAt:    expiration,
-		},
-		UserResponse: response.UserResponseFromModel(&user),
-	}
-	return ctx.JSON(http.StatusOK, resp)
+	return a.signIn(ctx, &user, reqData.Device)
 }
 
-// ProviderAuthentication handles third-party authentication.
+// ProviderAuthentication handles third-party authentication. A signed-in
+// user linking their account passes the token from LinkIdentity.
 func (a AuthHandler) ProviderAuthentication(ctx echo.Context) error {
+	if linkToken := ctx.QueryParam("link_token"); linkToken != "" {
+		_, err := util.ValidateLinkToken(a.app, linkToken, ctx.Param("provider"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusUnauthorized, ErrInvalidToken)
+		}
+		if err := egothic.SetLinkToken(ctx, linkToken); err != nil {
+			return util.ErrInternalServer(ctx, err)
+		}
+	}
+
 	err := egothic.BeginAuthHandler(ctx)
 	if err != nil {
 		return util.ErrInternalServer(ctx, err)
@@ -212,11 +271,43 @@ func (a AuthHandler) ProviderAuthentication(ctx echo.Context) error {
 	return nil
 }
 
+// ProviderAuthCallback signs in the user linked to the provider's
+// identity. New users get an account, and existing users are linked only
+// when both the provider and the account have verified the email, so that
+// a provider account using someone else's email can't take over theirs.
 func (a AuthHandler) ProviderAuthCallback(ctx echo.Context) error {
+	linkToken, err := egothic.PopLinkToken(ctx)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
 	user, err := egothic.CompleteUserAuth(ctx)
 	if err != nil {
 		return util.ErrInternalServer(ctx, err)
 	}
+	if linkToken != "" {
+		return a.linkIdentity(ctx, linkToken, user)
+	}
+
+	identity, err := a.app.Repositories.Identity.GetBySubject(user.Provider, user.UserID)
+	if err == nil {
+		fetchedUser, err := a.app.Repositories.User.GetByID(identity.UserID)
+		if err != nil {
+			return util.ErrInternalServer(ctx, err)
+		}
+		return a.signIn(ctx, &fetchedUser, "")
+	}
+	if !errors.Is(err, repository.ErrRecordNotFound) {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	if user.Email == "" {
+		return echo.NewHTTPError(
+			http.StatusBadRequest,
+			"the provider did not share an email address.",
+		)
+	}
+	emailVerified := providerEmailVerified(user)
 
 	// retrieve an existing user, or create an account for a new user
 	fetchedUser, err := a.app.Repositories.User.GetByEmail(user.Email)
@@ -240,6 +331,14 @@ func (a AuthHandler) ProviderAuthCallback(ctx echo.Context) error {
 			if err != nil {
 				return util.ErrInternalServer(ctx, err)
 			}
+			if emailVerified {
+				err = a.app.Repositories.User.VerifyContact(
+					fetchedUser.ID, models.EMAIL_VERIFICATION, true)
+				if err != nil {
+					return util.ErrInternalServer(ctx, err)
+				}
+				fetchedUser.EmailVerified = true
+			}
 			// send registration confirmation email
 			err = a.app.Mailer.Send(
 				fetchedUser.Email,
@@ -258,26 +357,29 @@ func (a AuthHandler) ProviderAuthCallback(ctx echo.Context) error {
 		} else {
 			return util.ErrInternalServer(ctx, err)
 		}
+	} else if !emailVerified || (!fetchedUser.EmailVerified && fetchedUser.Password != "") {
+		// the account's owner must sign in and link the provider themselves
+		return echo.NewHTTPError(http.StatusConflict, ErrUnverifiedAccountExists)
 	}
 
-	// generate access and refresh tokens
-	accessToken, refreshToken, expiration, err := util.GenerateTokens(a.app, &fetchedUser)
+	err = a.app.Repositories.Identity.Create(&models.UserIdentity{
+		UserID:   fetchedUser.ID,
+		Provider: user.Provider,
+		Subject:  user.UserID,
+		Email:    user.Email,
+	})
 	if err != nil {
+		if errors.Is(err, repository.ErrIdentityLinked) {
+			return echo.NewHTTPError(http.StatusConflict, err)
+		}
 		return util.ErrInternalServer(ctx, err)
 	}
 
-	resp := response.AuthResponse{
-		TokensResponse: response.TokensResponse{
-			AccessToken:  accessToken,
-			RefreshToken: refreshToken,
-			ExpiresAt:    expiration,
-		},
-		UserResponse: response.UserResponseFromModel(&fetchedUser),
-	}
-	return ctx.JSON(http.StatusOK, resp)
+	return a.signIn(ctx, &fetchedUser, "")
 }
 
-// RefreshToken refreshes JWT access token attached to a user session.
+// RefreshToken rotates the refresh token of a user session, issuing a new
+// access token with it.
 func (a AuthHandler) RefreshToken(ctx echo.Context) error {
 	reqData := struct {
 		RefreshToken string `json:"refresh_token" validate:"required"`
@@ -290,16 +392,40 @@ func (a AuthHandler) RefreshToken(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err)
 	}
 
-	// validate refresh token
-	claims, err := util.ValidateRefreshToken(a.app.Config.JWT.Refresh, reqData.RefreshToken)
+	// look up the session the refresh token belongs to
+	token, err := a.app.Repositories.Session.GetRefreshToken(
+		util.HashToken(a.app.Config.TokenHashKey, reqData.RefreshToken))
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.NewHTTPError(
+				http.StatusBadRequest,
+				ErrInvalidToken)
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+	session, err := a.app.Repositories.Session.GetByID(token.SessionID)
 	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+	if !session.Active() {
+		return echo.NewHTTPError(
+			http.StatusBadRequest,
+			ErrInvalidToken)
+	}
+
+	// a rotated token being presented again means it has leaked, so the
+	// whole session is revoked.
+	if token.UsedAt != nil {
+		return a.revokeReusedSession(ctx, &session)
+	}
+	if time.Now().After(token.ExpiresAt) {
 		return echo.NewHTTPError(
 			http.StatusBadRequest,
 			ErrInvalidToken)
 	}
 
 	// retrieve user details to generate new access token
-	user, err := a.app.Repositories.User.GetByID(claims.ID)
+	user, err := a.app.Repositories.User.GetByID(session.UserID)
 	if err != nil {
 		if errors.Is(err, repository.ErrRecordNotFound) {
 			return echo.ErrNotFound
@@ -307,15 +433,18 @@ func (a AuthHandler) RefreshToken(ctx echo.Context) error {
 		return util.ErrInternalServer(ctx, err)
 	}
 
-	accessToken, _, expiration, err := util.GenerateTokens(a.app, &user)
+	resp, err := a.rotateSession(session.ID, token.ID, &user)
 	if err != nil {
-		return util.ErrInternalServer(ctx, err)
-	}
-
-	resp := response.TokensResponse{
-		AccessToken:  accessToken,
-		RefreshToken: reqData.RefreshToken,
-		ExpiresAt:    expiration,
+		switch {
+		case errors.Is(err, repository.ErrRefreshTokenReused):
+			return a.revokeReusedSession(ctx, &session)
+		case errors.Is(err, repository.ErrRecordNotFound):
+			return echo.NewHTTPError(
+				http.StatusBadRequest,
+				ErrInvalidToken)
+		default:
+			return util.ErrInternalServer(ctx, err)
+		}
 	}
 
 	return ctx.JSON(http.StatusCreated, resp)
@@ -368,20 +497,26 @@ func (a AuthHandler) VerifyUser(ctx echo.Context) error {
 	} else {
 		fetchedUser.IsVerified = true
 	}
+	recordAudit(
+		ctx, a.app,
+		audit.ACTION_USER_VERIFY, audit.ENTITY_USER, fetchedUser.ID,
+		echo.Map{"is_verified": false}, echo.Map{"is_verified": true},
+	)
 
-	// generate new access and refresh tokens
-	accessToken, refreshToken, expiration, err := util.GenerateTokens(a.app, &fetchedUser)
+	// generate new access and refresh tokens for the session
+	tokens, err := a.reissueTokens(ctx, &fetchedUser)
 	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.NewHTTPError(
+				http.StatusUnauthorized,
+				ErrInvalidToken)
+		}
 		return util.ErrInternalServer(ctx, err)
 	}
 
 	resp := response.AuthResponse{
-		TokensResponse: response.TokensResponse{
-			AccessToken:  accessToken,
-			RefreshToken: refreshToken,
-			ExpiresAt:    expiration,
-		},
-		UserResponse: response.UserResponseFromModel(&fetchedUser),
+		TokensResponse: tokens,
+		UserResponse:   response.UserResponseFromModel(&fetchedUser),
 	}
 
 	return ctx.JSON(http.StatusOK, resp)
@@ -441,7 +576,10 @@ func (a AuthHandler) VerifyContact(ctx echo.Context) error {
 		}
 	}
 
-	verificationCode := util.RandomInt(verificationCodeMin, verificationCodeMax)
+	verificationCode, err := util.RandomInt(verificationCodeMin, verificationCodeMax)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
 
 	// send verification code
 	switch reqVerificationType {
@@ -545,8 +683,22 @@ func (a AuthHandler) VerifyContactCallback(ctx echo.Context) error {
 	} else {
 		switch time.Now().Compare(activeCode.ExpiresAt) {
 		case -1:
-			// code is still active
+			// code is still active, unless guessed too many times
+			if activeCode.Attempts >= maxVerificationAttempts {
+				err = a.app.Repositories.User.DeleteVerificationCode(
+					fetchedUser.ID, reqVerificationType)
+				if err != nil {
+					return util.ErrInternalServer(ctx, err)
+				}
+				return echo.NewHTTPError(
+					http.StatusForbidden,
+					ErrTooManyVerificationAttempts)
+			}
 			if activeCode.Code != reqData.Code {
+				err = a.app.Repositories.User.IncrementVerificationAttempts(activeCode.ID)
+				if err != nil {
+					return util.ErrInternalServer(ctx, err)
+				}
 				return echo.NewHTTPError(
 					http.StatusForbidden,
 					ErrInvalidVerificationCode)
@@ -576,9 +728,14 @@ func (a AuthHandler) VerifyContactCallback(ctx echo.Context) error {
 		}
 	}
 
-	// generate new access and refresh tokens
-	accessToken, refreshToken, expiration, err := util.GenerateTokens(a.app, &fetchedUser)
+	// generate new access and refresh tokens for the session
+	tokens, err := a.reissueTokens(ctx, &fetchedUser)
 	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.NewHTTPError(
+				http.StatusUnauthorized,
+				ErrInvalidToken)
+		}
 		return util.ErrInternalServer(ctx, err)
 	}
 
@@ -589,12 +746,8 @@ func (a AuthHandler) VerifyContactCallback(ctx echo.Context) error {
 	}
 
 	resp := response.AuthResponse{
-		TokensResponse: response.TokensResponse{
-			AccessToken:  accessToken,
-			RefreshToken: refreshToken,
-			ExpiresAt:    expiration,
-		},
-		UserResponse: response.UserResponseFromModel(&fetchedUser),
+		TokensResponse: tokens,
+		UserResponse:   response.UserResponseFromModel(&fetchedUser),
 	}
 
 	return ctx.JSON(http.StatusOK, resp)
@@ -12,7 +12,11 @@ import (
 
 	"github.com/lokatalent/backend_go/cmd/api/models/response"
 	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/audit"
+	"github.com/lokatalent/backend_go/internal/ledger"
+	"github.com/lokatalent/backend_go/internal/lifecycle"
 	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/rbac"
 	"github.com/lokatalent/backend_go/internal/repository"
 )
 
@@ -30,16 +34,15 @@ func NewBookingHandler(app *util.Application) BookingHandler {
 
 func (b BookingHandler) CreateBooking(ctx echo.Context) error {
 	reqData := struct {
-		RequesterID   string  `json:"requester_id" validate:"required"`
-		RequesterAddr string  `json:"requester_addr" validate:"required"`
-		ServiceType   string  `json:"service_type" validate:"required"`
-		BookingType   string  `json:"booking_type" validate:"required"`
-		ServiceDesc   string  `json:"service_desc" validate:"required"`
-		StartTime     string  `json:"start_time" validate:"required"`
-		EndTime       string  `json:"end_time" validate:"required"`
-		StartDate     string  `json:"start_date" validate:"required"`
-		EndDate       string  `json:"end_date" validate:"required"`
-		TotalPrice    float64 `json:"total_price" validate:"required"`
+		RequesterID   string `json:"requester_id" validate:"required"`
+		RequesterAddr string `json:"requester_addr" validate:"required"`
+		ServiceType   string `json:"service_type" validate:"required"`
+		BookingType   string `json:"booking_type" validate:"required"`
+		ServiceDesc   string `json:"service_desc" validate:"required"`
+		StartTime     string `json:"start_time" validate:"required"`
+		EndTime       string `json:"end_time" validate:"required"`
+		StartDate     string `json:"start_date" validate:"required"`
+		EndDate       string `json:"end_date" validate:"required"`
 	}{}
 
 	if err := ctx.Bind(&reqData); err != nil {
@@ -112,6 +115,11 @@ func (b BookingHandler) CreateBooking(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
+	location, err := geocodeAddress(ctx, b.app, reqData.RequesterAddr)
+	if err != nil {
+		return err
+	}
+
 	calculatedTotalPrice, err := calculateBookingPrice(
 		b.app,
 		reqData.ServiceType,
@@ -126,18 +134,19 @@ func (b BookingHandler) CreateBooking(ctx echo.Context) error {
 	}
 
 	newBooking := models.Booking{
-		ID:            uuid.NewString(),
-		RequesterID:   authUser.ID,
-		RequesterAddr: reqData.RequesterAddr,
-		ServiceType:   reqData.ServiceType,
-		BookingType:   reqData.BookingType,
-		ServiceDesc:   reqData.ServiceDesc,
-		StartTime:     parsedStartTime,
-		EndTime:       parsedEndTime,
-		StartDate:     parsedStartDate,
-		EndDate:       parsedEndDate,
-		TotalPrice:    calculatedTotalPrice,
-		Status:        models.BOOKING_OPEN,
+		ID:                uuid.NewString(),
+		RequesterID:       authUser.ID,
+		RequesterAddr:     reqData.RequesterAddr,
+		RequesterLocation: location,
+		ServiceType:       reqData.ServiceType,
+		BookingType:       reqData.BookingType,
+		ServiceDesc:       reqData.ServiceDesc,
+		StartTime:         parsedStartTime,
+		EndTime:           parsedEndTime,
+		StartDate:         parsedStartDate,
+		EndDate:           parsedEndDate,
+		TotalPrice:        calculatedTotalPrice,
+		Status:            models.BOOKING_OPEN,
 	}
 
 	err = b.app.Repositories.Booking.Create(&newBooking)
@@ -159,8 +168,7 @@ func (b BookingHandler) GetBooking(ctx echo.Context) error {
 		return util.ErrInternalServer(ctx, err)
 	}
 
-	authenticatedUser := util.ContextGetUser(ctx)
-	authUser, err := b.app.Repositories.User.GetByID(authenticatedUser.ID)
+	principal, err := util.ContextGetPrincipal(ctx, b.app)
 	if err != nil {
 		if errors.Is(err, repository.ErrRecordNotFound) {
 			return echo.ErrNotFound
@@ -168,9 +176,7 @@ func (b BookingHandler) GetBooking(ctx echo.Context) error {
 		return util.ErrInternalServer(ctx, err)
 	}
 
-	if (booking.RequesterID != authUser.ID &&
-		booking.ProviderID.String != authUser.ID) &&
-		authUser.Role == models.USER_REGULAR {
+	if !rbac.CanViewBooking(principal, &booking) {
 		return echo.NewHTTPError(
 			http.StatusUnauthorized,
 			"restricted from view booking.",
@@ -222,10 +228,8 @@ func (b BookingHandler) GetAllBookings(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, ErrInvalidServiceType)
 	}
 
-	// ensure that there is at lease an ID, whether requester or provider,
-	// else assume request from admin.
-	authenticatedUser := util.ContextGetUser(ctx)
-	authUser, err := b.app.Repositories.User.GetByID(authenticatedUser.ID)
+	// users may only list their own bookings, as requester or provider.
+	principal, err := util.ContextGetPrincipal(ctx, b.app)
 	if err != nil {
 		if errors.Is(err, repository.ErrRecordNotFound) {
 			return echo.ErrNotFound
@@ -233,7 +237,7 @@ func (b BookingHandler) GetAllBookings(ctx echo.Context) error {
 		return util.ErrInternalServer(ctx, err)
 	}
 
-	if (reqData.RequesterID != authUser.ID && reqData.ProviderID != authUser.ID) && authUser.Role == models.USER_REGULAR {
+	if !rbac.CanListBookings(principal, reqData.RequesterID, reqData.ProviderID) {
 		return echo.NewHTTPError(
 			http.StatusUnauthorized,
 			"restricted from view bookings.",
@@ -312,6 +316,13 @@ func (b BookingHandler) UpdateBookingStatus(ctx echo.Context) error {
 	if !util.IsValidBookingStatus(status) {
 		return echo.ErrBadRequest
 	}
+	action, ok := lifecycle.ActionFor(status)
+	if !ok {
+		return echo.NewHTTPError(
+			http.StatusBadRequest,
+			"can only change to 'canceled', 'completed' or 'disputed'",
+		)
+	}
 
 	booking, err := b.app.Repositories.Booking.GetByID(bookingID)
 	if err != nil {
@@ -322,8 +333,7 @@ func (b BookingHandler) UpdateBookingStatus(ctx echo.Context) error {
 		}
 		return util.ErrInternalServer(ctx, err)
 	}
-	authenticatedUser := util.ContextGetUser(ctx)
-	authUser, err := b.app.Repositories.User.GetByID(authenticatedUser.ID)
+	principal, err := util.ContextGetPrincipal(ctx, b.app)
 	if err != nil {
 		if errors.Is(err, repository.ErrRecordNotFound) {
 			return echo.ErrNotFound
@@ -331,16 +341,9 @@ func (b BookingHandler) UpdateBookingStatus(ctx echo.Context) error {
 		return util.ErrInternalServer(ctx, err)
 	}
 
-	if booking.RequesterID != authUser.ID {
-		return echo.NewHTTPError(
-			http.StatusUnauthorized,
-			"only requester can update booking status.",
-		)
-	}
-
-	var notification models.Notification
-	switch status {
-	case models.BOOKING_COMPLETED:
+	// requesters can only complete a booking once its end time is
+	// reached; admins resolve bookings at any time.
+	if action == lifecycle.ACTION_COMPLETE && !principal.Can(rbac.BOOKING_MANAGE) {
 		inProgress, err := util.VerifyCompletionDateTime(
 			booking.EndTime.UTC(),
 			booking.EndDate,
@@ -354,54 +357,11 @@ func (b BookingHandler) UpdateBookingStatus(ctx echo.Context) error {
 				"booking still in progress.",
 			)
 		}
-		if booking.Status != models.BOOKING_IN_PROGRESS {
-			return echo.NewHTTPError(
-				http.StatusForbidden,
-				"can only change 'in_progress' booking.",
-			)
-		}
-		// send payment to service provider
-		err = payServiceProvider(ctx, b.app, &booking)
-		if err != nil {
-			return err
-		}
-		notification = models.Notification{
-			Type:    models.NOTIFICATION_TYPE_BOOKING,
-			UserID:  booking.ProviderID.String,
-			Message: "booking completed.",
-		}
-		notification.BookingID.String = booking.ID
-		notification.BookingID.Valid = true
-	case models.BOOKING_CANCELED:
-		// refund service requester for booking fee
-		err = refundServiceRequester(ctx, b.app, &booking)
-		if err != nil {
-			return err
-		}
-		notification = models.Notification{
-			Type:    models.NOTIFICATION_TYPE_BOOKING,
-			UserID:  booking.ProviderID.String,
-			Message: "booking completed.",
-		}
-		notification.BookingID.String = booking.ID
-		notification.BookingID.Valid = true
-	default:
-		return echo.NewHTTPError(
-			http.StatusBadRequest,
-			"can only change to 'canceled' or 'completed'",
-		)
 	}
 
-	_, err = b.app.Repositories.Booking.UpdateStatus(booking.ID, status)
+	notification, err := applyBookingAction(ctx, b.app, &booking, action, principal, "")
 	if err != nil {
-		return util.ErrInternalServer(ctx, err)
-	}
-
-	if booking.ProviderID.String != "" {
-		err = b.app.Repositories.Notification.Create(&notification)
-		if err != nil {
-			return util.ErrInternalServer(ctx, err)
-		}
+		return err
 	}
 	return ctx.JSON(
 		http.StatusOK,
@@ -420,86 +380,61 @@ func (b BookingHandler) AcceptBooking(ctx echo.Context) error {
 		return util.ErrInternalServer(ctx, err)
 	}
 
-	if booking.Status != models.BOOKING_OPEN {
-		return echo.NewHTTPError(
-			http.StatusForbidden,
-			"can only accept open booking.",
-		)
-	}
-	authenticatedUser := util.ContextGetUser(ctx)
-	authUser, err := b.app.Repositories.User.GetByID(authenticatedUser.ID)
+	principal, err := util.ContextGetPrincipal(ctx, b.app)
 	if err != nil {
 		if errors.Is(err, repository.ErrRecordNotFound) {
 			return echo.ErrNotFound
 		}
 		return util.ErrInternalServer(ctx, err)
 	}
-	if authUser.ServiceRole == models.SERVICE_REQUESTER {
-		return echo.NewHTTPError(
-			http.StatusForbidden,
-			"only providers can accept booking.",
-		)
-	}
 
-	rejected, err := b.app.Repositories.Booking.CheckRejected(booking.ID, authUser.ID)
+	notification, err := applyBookingAction(ctx, b.app, &booking, lifecycle.ACTION_ACCEPT, principal, "")
 	if err != nil {
-		return util.ErrInternalServer(ctx, err)
-	}
-	if rejected {
-		return echo.NewHTTPError(
-			http.StatusForbidden,
-			"booking has been previously rejected.",
-		)
+		return err
 	}
+	return ctx.JSON(
+		http.StatusOK,
+		response.NotificationResponseFromModel(notification))
+}
 
-	// check if user has notification about the booking
-	notifications, err := b.app.Repositories.Notification.GetForUser(
-		models.NotificationFilter{
-			Type:      models.NOTIFICATION_TYPE_BOOKING,
-			UserID:    authUser.ID,
-			BookingID: bookingID,
-			Page:      1,
-			Limit:     1,
-		},
-	)
+func (b BookingHandler) RejectBooking(ctx echo.Context) error {
+	bookingID := ctx.Param("id")
+	booking, err := b.app.Repositories.Booking.GetByID(bookingID)
 	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.NewHTTPError(
+				http.StatusNotFound,
+				repository.ErrRecordNotFound)
+		}
 		return util.ErrInternalServer(ctx, err)
 	}
-	if len(notifications) < 1 {
-		return echo.NewHTTPError(
-			http.StatusForbidden,
-			"user does not have access to booking.",
-		)
-	}
 
-	_, err = b.app.Repositories.Booking.AssignProvider(booking.ID, authUser.ID)
+	principal, err := util.ContextGetPrincipal(ctx, b.app)
 	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
+		}
 		return util.ErrInternalServer(ctx, err)
 	}
 
-	_, err = b.app.Repositories.Booking.UpdateStatus(
-		booking.ID, models.BOOKING_IN_PROGRESS)
+	notification, err := applyBookingAction(ctx, b.app, &booking, lifecycle.ACTION_REJECT, principal, principal.ID)
 	if err != nil {
-		return util.ErrInternalServer(ctx, err)
+		return err
 	}
 
-	notification := models.Notification{
-		Type:    models.NOTIFICATION_TYPE_BOOKING,
-		UserID:  booking.RequesterID,
-		Message: "booking accepted.",
-	}
-	notification.BookingID.String = booking.ID
-	notification.BookingID.Valid = true
-	err = b.app.Repositories.Notification.Create(&notification)
+	// add entry to rejected booking so the provider is not selected
+	// again.
+	err = b.app.Repositories.Booking.RejectBooking(booking.ID, principal.ID)
 	if err != nil {
 		return util.ErrInternalServer(ctx, err)
 	}
+
 	return ctx.JSON(
 		http.StatusOK,
 		response.NotificationResponseFromModel(notification))
 }
 
-func (b BookingHandler) RejectBooking(ctx echo.Context) error {
+func (b BookingHandler) StartBooking(ctx echo.Context) error {
 	bookingID := ctx.Param("id")
 	booking, err := b.app.Repositories.Booking.GetByID(bookingID)
 	if err != nil {
@@ -510,14 +445,8 @@ func (b BookingHandler) RejectBooking(ctx echo.Context) error {
 		}
 		return util.ErrInternalServer(ctx, err)
 	}
-	if booking.Status != models.BOOKING_OPEN {
-		return echo.NewHTTPError(
-			http.StatusForbidden,
-			"can only reject open booking.",
-		)
-	}
-	authenticatedUser := util.ContextGetUser(ctx)
-	authUser, err := b.app.Repositories.User.GetByID(authenticatedUser.ID)
+
+	principal, err := util.ContextGetPrincipal(ctx, b.app)
 	if err != nil {
 		if errors.Is(err, repository.ErrRecordNotFound) {
 			return echo.ErrNotFound
@@ -525,64 +454,50 @@ func (b BookingHandler) RejectBooking(ctx echo.Context) error {
 		return util.ErrInternalServer(ctx, err)
 	}
 
-	if authUser.ServiceRole == models.SERVICE_REQUESTER {
-		return echo.NewHTTPError(
-			http.StatusForbidden,
-			"only providers can reject booking.",
-		)
+	notification, err := applyBookingAction(ctx, b.app, &booking, lifecycle.ACTION_START, principal, "")
+	if err != nil {
+		return err
 	}
+	return ctx.JSON(
+		http.StatusOK,
+		response.NotificationResponseFromModel(notification))
+}
 
-	rejected, err := b.app.Repositories.Booking.CheckRejected(booking.ID, authUser.ID)
+func (b BookingHandler) GetBookingEvents(ctx echo.Context) error {
+	bookingID := ctx.Param("id")
+	booking, err := b.app.Repositories.Booking.GetByID(bookingID)
 	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
+		}
 		return util.ErrInternalServer(ctx, err)
 	}
-	if rejected {
-		return echo.NewHTTPError(
-			http.StatusForbidden,
-			"booking has been previously rejected.",
-		)
-	}
 
-	// check if user has notification about the booking
-	notifications, err := b.app.Repositories.Notification.GetForUser(
-		models.NotificationFilter{
-			Type:      models.NOTIFICATION_TYPE_BOOKING,
-			UserID:    authUser.ID,
-			BookingID: bookingID,
-			Page:      1,
-			Limit:     1,
-		},
-	)
+	principal, err := util.ContextGetPrincipal(ctx, b.app)
 	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
+		}
 		return util.ErrInternalServer(ctx, err)
 	}
-	if len(notifications) < 1 {
+	if !rbac.CanViewBooking(principal, &booking) {
 		return echo.NewHTTPError(
-			http.StatusForbidden,
-			"user does not have access to booking.",
+			http.StatusUnauthorized,
+			"restricted from view booking.",
 		)
 	}
 
-	// add entry to rejected booking
-	err = b.app.Repositories.Booking.RejectBooking(booking.ID, authUser.ID)
+	events, err := b.app.Repositories.Booking.GetEvents(booking.ID)
 	if err != nil {
 		return util.ErrInternalServer(ctx, err)
 	}
 
-	notification := models.Notification{
-		Type:    models.NOTIFICATION_TYPE_BOOKING,
-		UserID:  booking.RequesterID,
-		Message: "booking rejected.",
+	resp := []response.BookingEventResponse{}
+	for _, event := range events {
+		resp = append(resp, response.BookingEventResponseFromModel(event))
 	}
-	notification.BookingID.String = booking.ID
-	notification.BookingID.Valid = true
-	err = b.app.Repositories.Notification.Create(&notification)
-	if err != nil {
-		return util.ErrInternalServer(ctx, err)
-	}
-	return ctx.JSON(
-		http.StatusOK,
-		response.NotificationResponseFromModel(notification))
+
+	return ctx.JSON(http.StatusOK, resp)
 }
 
 func (b BookingHandler) FindProviders(ctx echo.Context) error {
@@ -628,48 +543,43 @@ func (b BookingHandler) FindProviders(ctx echo.Context) error {
 		)
 	}
 
+	// bookings created before addresses were geocoded.
+	if !booking.RequesterLocation.HasCoordinates() {
+		booking.RequesterLocation, err = geocodeAddress(ctx, b.app, booking.RequesterAddr)
+		if err != nil {
+			return err
+		}
+	}
+
 	services, err := b.app.Repositories.Booking.MatchServices(
 		&booking,
 		models.ServiceFilter{
-			Page:  reqPage,
-			Limit: reqSize,
+			RadiusKm: b.app.Config.MatchRadiusKm,
+			Page:     reqPage,
+			Limit:    reqSize,
 		},
 	)
 	if err != nil {
 		return util.ErrInternalServer(ctx, err)
 	}
 
-	distEstimations := []response.DistanceEstimation{}
+	providerIDs := []string{}
 	for _, service := range services {
-		distance, err := fetchDistanceInfo(
-			service.Address,
-			booking.RequesterAddr,
-			b.app.Config.Google.MapSecret,
-		)
-		if err != nil || distance.Status != "OK" {
-			distEstimations = append(
-				distEstimations,
-				response.DistanceEstimation{
-					Distance: "unknown",
-					Duration: "unknown",
-				},
-			)
-		} else {
-			distEstimations = append(
-				distEstimations,
-				response.DistanceEstimation{
-					Distance: distance.Rows[0].Elements[0].Distance.Text,
-					Duration: distance.Rows[0].Elements[0].Duration.Text,
-				},
-			)
-		}
+		providerIDs = append(providerIDs, service.UserID)
+	}
+	ratings, err := b.app.Repositories.Review.GetSummaries(providerIDs)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
 	}
 
 	resp := []response.ServiceDistanceResponse{}
-	for idx, service := range services {
+	for _, service := range services {
 		resp = append(
 			resp,
-			response.BookingServiceProviderResponse(service, distEstimations[idx]),
+			response.BookingServiceProviderResponse(
+				service,
+				ratings[service.UserID],
+			),
 		)
 	}
 
@@ -698,22 +608,15 @@ func (b BookingHandler) SelectProvider(ctx echo.Context) error {
 		}
 		return util.ErrInternalServer(ctx, err)
 	}
-	if booking.Status != models.BOOKING_OPEN {
-		return echo.NewHTTPError(
-			http.StatusForbidden,
-			"can only select provider for open booking.",
-		)
-	}
 
-	authenticatedUser := util.ContextGetUser(ctx)
-	authUser, err := b.app.Repositories.User.GetByID(authenticatedUser.ID)
+	principal, err := util.ContextGetPrincipal(ctx, b.app)
 	if err != nil {
 		if errors.Is(err, repository.ErrRecordNotFound) {
 			return echo.ErrNotFound
 		}
 		return util.ErrInternalServer(ctx, err)
 	}
-	if authUser.ID == reqData.ProviderID {
+	if principal.ID == reqData.ProviderID {
 		return echo.NewHTTPError(
 			http.StatusForbidden,
 			"attempting to select self as provider.",
@@ -733,26 +636,27 @@ func (b BookingHandler) SelectProvider(ctx echo.Context) error {
 		)
 	}
 
-	// check that the authenticated have enough funds to place booking,
-	// and deduct from wallet or return status code to prompt payment.
-	err = checkPaymentRequirement(ctx, b.app, &booking, &authUser)
+	rejected, err := b.app.Repositories.Booking.CheckRejected(booking.ID, serviceProvider.ID)
 	if err != nil {
-		return err
+		return util.ErrInternalServer(ctx, err)
 	}
-
-	notification := models.Notification{
-		Type:   models.NOTIFICATION_TYPE_BOOKING,
-		UserID: reqData.ProviderID,
-		Message: fmt.Sprintf(
-			BOOKING_PROVIDER_SELECTION_MESSAGE,
-			booking.ID,
-		),
+	if rejected {
+		return echo.NewHTTPError(
+			http.StatusForbidden,
+			"booking has been previously rejected by provider.",
+		)
 	}
-	notification.BookingID.String = booking.ID
-	notification.BookingID.Valid = true
-	err = b.app.Repositories.Notification.Create(&notification)
+
+	notification, err := applyBookingAction(
+		ctx,
+		b.app,
+		&booking,
+		lifecycle.ACTION_SELECT_PROVIDER,
+		principal,
+		serviceProvider.ID,
+	)
 	if err != nil {
-		return util.ErrInternalServer(ctx, err)
+		return err
 	}
 	return ctx.JSON(
 		http.StatusOK,
@@ -761,9 +665,244 @@ func (b BookingHandler) SelectProvider(ctx echo.Context) error {
 
 // helpers
 
-// checkPaymentRequirement checks if payment has been made,
-// else make payment from wallet.
-func checkPaymentRequirement(ctx echo.Context, app *util.Application, booking *models.Booking, user *models.User) error {
+// bookingActors returns the lifecycle actors principal may act as on
+// booking.
+func bookingActors(booking *models.Booking, principal *rbac.Principal) []string {
+	actors := []string{}
+	if rbac.IsBookingRequester(principal, booking) {
+		actors = append(actors, lifecycle.ACTOR_REQUESTER)
+	}
+	if rbac.IsBookingProvider(principal, booking) {
+		actors = append(actors, lifecycle.ACTOR_PROVIDER)
+	}
+	if principal.Can(rbac.BOOKING_MANAGE) {
+		actors = append(actors, lifecycle.ACTOR_ADMIN)
+	}
+	return actors
+}
+
+// bookingActionMessage returns the notification message for action.
+func bookingActionMessage(action string, booking *models.Booking) string {
+	switch action {
+	case lifecycle.ACTION_SELECT_PROVIDER:
+		return fmt.Sprintf(BOOKING_PROVIDER_SELECTION_MESSAGE, booking.ID)
+	case lifecycle.ACTION_ACCEPT:
+		return "booking accepted."
+	case lifecycle.ACTION_REJECT:
+		return "booking rejected."
+	case lifecycle.ACTION_START:
+		return "booking started."
+	case lifecycle.ACTION_COMPLETE:
+		return "booking completed."
+	case lifecycle.ACTION_CANCEL:
+		return "booking canceled."
+	case lifecycle.ACTION_DISPUTE:
+		return "booking disputed."
+	case lifecycle.ACTION_EXPIRE:
+		return "booking expired before a provider accepted it."
+	default:
+		return fmt.Sprintf("booking %s.", action)
+	}
+}
+
+// applyBookingAction moves booking through the lifecycle on behalf of
+// principal and records the side effects of the transition with it.
+// providerID is the provider being selected or rejecting the booking, if
+// any. It returns the first notification queued.
+func applyBookingAction(ctx echo.Context, app *util.Application, booking *models.Booking, action string, principal *rbac.Principal, providerID string) (models.Notification, error) {
+	user := &principal.User
+	transition, actor, err := lifecycle.Booking.Find(
+		action,
+		booking.Status,
+		bookingActors(booking, principal)...,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, lifecycle.ErrUnknownAction):
+			return models.Notification{}, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		case errors.Is(err, lifecycle.ErrActorNotAllowed):
+			return models.Notification{}, echo.NewHTTPError(
+				http.StatusForbidden,
+				fmt.Sprintf("not allowed to %s booking.", action),
+			)
+		case errors.Is(err, lifecycle.ErrInvalidTransition):
+			return models.Notification{}, echo.NewHTTPError(
+				http.StatusForbidden,
+				fmt.Sprintf("cannot %s '%s' booking.", action, booking.Status),
+			)
+		default:
+			return models.Notification{}, util.ErrInternalServer(ctx, err)
+		}
+	}
+
+	event := models.BookingEvent{
+		Action:     action,
+		Actor:      actor,
+		FromStatus: booking.Status,
+		ToStatus:   transition.To,
+	}
+	event.ActorID.String = user.ID
+	event.ActorID.Valid = true
+	if providerID != "" {
+		event.ProviderID.String = providerID
+		event.ProviderID.Valid = true
+	}
+
+	effects, notifications, err := bookingEffects(app, transition, action, booking, providerID, user.ID)
+	if err != nil {
+		return models.Notification{}, util.ErrInternalServer(ctx, err)
+	}
+	if transition.HasEffect(lifecycle.EFFECT_REQUIRE_PAYMENT) {
+		// check that the requester has enough funds to place booking,
+		// and deduct from wallet or return status code to prompt payment.
+		err = checkPaymentRequirement(ctx, app, booking, user, &effects)
+		if err != nil {
+			return models.Notification{}, err
+		}
+	}
+
+	updated, err := app.Repositories.Booking.TransitionStatus(booking.ID, &event, effects)
+	if err != nil {
+		if errors.Is(err, repository.ErrBookingStatusChanged) {
+			return models.Notification{}, echo.NewHTTPError(http.StatusConflict, err.Error())
+		}
+		if errors.Is(err, repository.ErrInsufficientFunds) {
+			// the wallet was debited by another booking meanwhile.
+			return models.Notification{}, echo.NewHTTPError(
+				http.StatusPaymentRequired,
+				"wallet balance is low.",
+			)
+		}
+		return models.Notification{}, util.ErrInternalServer(ctx, err)
+	}
+	if !rbac.IsBookingRequester(principal, booking) && !rbac.IsBookingProvider(principal, booking) {
+		// an admin overriding the parties of the booking.
+		recordAudit(
+			ctx, app,
+			bookingAuditAction(action), audit.ENTITY_BOOKING, booking.ID,
+			*booking, updated,
+		)
+	}
+	*booking = updated
+
+	if len(notifications) == 0 {
+		return models.Notification{}, nil
+	}
+	return notifications[0], nil
+}
+
+// bookingAuditAction returns the audit log action of a booking action.
+func bookingAuditAction(action string) string {
+	switch action {
+	case lifecycle.ACTION_SELECT_PROVIDER:
+		return audit.ACTION_BOOKING_SELECT_PROVIDER
+	case lifecycle.ACTION_ACCEPT:
+		return audit.ACTION_BOOKING_ACCEPT
+	case lifecycle.ACTION_REJECT:
+		return audit.ACTION_BOOKING_REJECT
+	case lifecycle.ACTION_START:
+		return audit.ACTION_BOOKING_START
+	case lifecycle.ACTION_COMPLETE:
+		return audit.ACTION_BOOKING_COMPLETE
+	case lifecycle.ACTION_CANCEL:
+		return audit.ACTION_BOOKING_CANCEL
+	default:
+		return audit.ACTION_BOOKING_DISPUTE
+	}
+}
+
+// bookingEffects returns the payments and outbox messages of the
+// transition, to be recorded in the same transaction so that a booking is
+// only ever settled once, and the notifications queued. The actor is not
+// notified of their own action.
+func bookingEffects(app *util.Application, transition lifecycle.Transition, action string, booking *models.Booking, providerID, actorID string) (models.TransitionEffects, []models.Notification, error) {
+	effects := models.TransitionEffects{}
+	if transition.HasEffect(lifecycle.EFFECT_PAY_PROVIDER) {
+		if err := payServiceProvider(app, booking, &effects); err != nil {
+			return effects, nil, err
+		}
+	}
+	if transition.HasEffect(lifecycle.EFFECT_REFUND_REQUESTER) {
+		if err := refundServiceRequester(app, booking, &effects); err != nil {
+			return effects, nil, err
+		}
+	}
+
+	recipients := []string{}
+	if transition.HasEffect(lifecycle.EFFECT_NOTIFY_PROVIDER) {
+		// the selected provider is only assigned by the transition, and a
+		// rejected or canceled booking may no longer have one assigned
+		// after it.
+		if transition.To == models.BOOKING_PROVIDER_SELECTED && providerID != "" {
+			recipients = append(recipients, providerID)
+		} else if booking.ProviderID.Valid {
+			recipients = append(recipients, booking.ProviderID.String)
+		}
+	}
+	if transition.HasEffect(lifecycle.EFFECT_NOTIFY_REQUESTER) {
+		recipients = append(recipients, booking.RequesterID)
+	}
+
+	notifications := []models.Notification{}
+	for _, recipient := range recipients {
+		if recipient == actorID {
+			continue
+		}
+		notification := models.Notification{
+			ID:        uuid.NewString(),
+			Type:      models.NOTIFICATION_TYPE_BOOKING,
+			UserID:    recipient,
+			Message:   bookingActionMessage(action, booking),
+			CreatedAt: time.Now(),
+		}
+		notification.BookingID.String = booking.ID
+		notification.BookingID.Valid = true
+		message, err := models.NewOutboxMessage(models.OUTBOX_KIND_NOTIFICATION, notification)
+		if err != nil {
+			return effects, nil, err
+		}
+		effects.Outbox = append(effects.Outbox, message)
+		notifications = append(notifications, notification)
+	}
+
+	return effects, notifications, nil
+}
+
+// ExpireBooking cancels a booking nobody accepted before its start time,
+// refunding its requester. A booking whose status changed meanwhile is
+// left as is.
+func ExpireBooking(app *util.Application, booking *models.Booking) error {
+	action := lifecycle.ACTION_EXPIRE
+	transition, actor, err := lifecycle.Booking.Find(action, booking.Status, lifecycle.ACTOR_SYSTEM)
+	if err != nil {
+		return err
+	}
+
+	effects, _, err := bookingEffects(app, transition, action, booking, "", "")
+	if err != nil {
+		return err
+	}
+	event := models.BookingEvent{
+		Action:     action,
+		Actor:      actor,
+		FromStatus: booking.Status,
+		ToStatus:   transition.To,
+	}
+	updated, err := app.Repositories.Booking.TransitionStatus(booking.ID, &event, effects)
+	if err != nil {
+		if errors.Is(err, repository.ErrBookingStatusChanged) {
+			return nil
+		}
+		return err
+	}
+	*booking = updated
+	return nil
+}
+
+// checkPaymentRequirement checks if payment has been made, else adds the
+// payment of the booking from the requester's wallet to effects, so that
+// the wallet is only debited if the transition is applied.
+func checkPaymentRequirement(ctx echo.Context, app *util.Application, booking *models.Booking, user *models.User, effects *models.TransitionEffects) error {
 	payment, err := app.Repositories.Payment.GetPayment(models.PaymentFilter{
 		Type:      models.PAYMENT_TYPE_CREDIT,
 		BookingID: booking.ID,
@@ -790,49 +929,45 @@ func checkPaymentRequirement(ctx echo.Context, app *util.Application, booking *m
 				"payment status not yet verified.",
 			)
 		}
-	} else { // no payment entry found
-		// check wallet balance and attempt to pay from requester's
-		// wallet.
-		wallet, err := app.Repositories.Payment.GetWallet(user.ID)
-		if err != nil {
-			return util.ErrInternalServer(ctx, err)
-		}
-		if wallet.Balance >= booking.TotalPrice {
-			newPayment := models.Payment{
-				ID:         uuid.NewString(),
-				Type:       models.PAYMENT_TYPE_CREDIT,
-				PaymentRef: uuid.NewString(),
-				Amount:     booking.TotalPrice,
-				Status:     models.PAYMENT_STATUS_VERIFIED,
-			}
-			newPayment.BookingID.String = booking.ID
-			newPayment.BookingID.Valid = true
-			err = app.Repositories.Payment.CreatePayment(&newPayment)
-			if err != nil {
-				return util.ErrInternalServer(ctx, err)
-			}
-
-			// update wallet
-			err = app.Repositories.Payment.UpdateWallet(
-				wallet.UserID,
-				models.PAYMENT_TYPE_DEBIT,
-				booking.TotalPrice,
-			)
-			if err != nil {
-				return util.ErrInternalServer(ctx, err)
-			}
-		} else {
-			return echo.NewHTTPError(
-				http.StatusPaymentRequired,
-				"wallet balance is low.",
-			)
-		}
 	}
 
+	// no payment entry found, check wallet balance and pay from the
+	// requester's wallet. The balance is checked again when the payment
+	// is posted.
+	wallet, err := app.Repositories.Payment.GetWallet(user.ID)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+	if wallet.Balance.Cmp(booking.TotalPrice) < 0 {
+		return echo.NewHTTPError(
+			http.StatusPaymentRequired,
+			"wallet balance is low.",
+		)
+	}
+
+	newPayment := models.Payment{
+		ID:         uuid.NewString(),
+		Type:       models.PAYMENT_TYPE_CREDIT,
+		PaymentRef: uuid.NewString(),
+		Amount:     booking.TotalPrice,
+		Status:     models.PAYMENT_STATUS_VERIFIED,
+	}
+	newPayment.BookingID.String = booking.ID
+	newPayment.BookingID.Valid = true
+	effects.Payments = append(effects.Payments, models.LedgerPayment{
+		Payment: &newPayment,
+		Entries: []models.LedgerEntry{
+			ledger.WalletPayment(*booking, booking.TotalPrice),
+		},
+	})
+
 	return nil
 }
 
-func payServiceProvider(ctx echo.Context, app *util.Application, booking *models.Booking) error {
+// payServiceProvider adds the payout of the booking to its provider to
+// the effects: a pending payment releasing escrow to the provider's
+// earnings, and the transfer paying them out.
+func payServiceProvider(app *util.Application, booking *models.Booking, effects *models.TransitionEffects) error {
 	newPayment := models.Payment{
 		ID:         uuid.NewString(),
 		Type:       models.PAYMENT_TYPE_DEBIT,
@@ -847,37 +982,39 @@ func payServiceProvider(ctx echo.Context, app *util.Application, booking *models
 		booking.ProviderID.String,
 	)
 	if err != nil {
-		return util.ErrInternalServer(ctx, err)
-	}
-
-	_, err = initTransfer(
-		newPayment.PaymentRef,
-		recipientCode,
-		"booking payment.",
-		newPayment.Amount,
-		app.Config.Paystack.APIKey,
-	)
-	if err != nil {
-		return util.ErrInternalServer(ctx, err)
+		return err
 	}
 
-	err = app.Repositories.Payment.CreatePayment(&newPayment)
+	transfer, err := models.NewOutboxMessage(models.OUTBOX_KIND_TRANSFER, models.OutboxTransfer{
+		Reference:     newPayment.PaymentRef,
+		RecipientCode: recipientCode,
+		Reason:        "booking payment.",
+		Amount:        newPayment.Amount,
+	})
 	if err != nil {
-		return util.ErrInternalServer(ctx, err)
+		return err
 	}
 
+	effects.Payments = append(effects.Payments, models.LedgerPayment{
+		Payment: &newPayment,
+		Entries: []models.LedgerEntry{
+			ledger.BookingSettlement(*booking),
+			ledger.Payout(*booking, newPayment.Amount),
+		},
+	})
+	effects.Outbox = append(effects.Outbox, transfer)
 	return nil
 }
 
-func refundServiceRequester(ctx echo.Context, app *util.Application, booking *models.Booking) error {
+// refundServiceRequester adds the refund of the booking's verified
+// payment to the effects.
+func refundServiceRequester(app *util.Application, booking *models.Booking, effects *models.TransitionEffects) error {
 	payment, err := app.Repositories.Payment.GetPayment(models.PaymentFilter{
 		Type:      models.PAYMENT_TYPE_CREDIT,
 		BookingID: booking.ID,
 	})
-	if err != nil {
-		if !errors.Is(err, repository.ErrRecordNotFound) {
-			return util.ErrInternalServer(ctx, err)
-		}
+	if err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
+		return err
 	}
 
 	if payment.Status == models.PAYMENT_STATUS_VERIFIED {
@@ -890,20 +1027,12 @@ func refundServiceRequester(ctx echo.Context, app *util.Application, booking *mo
 		}
 		newPayment.BookingID.String = booking.ID
 		newPayment.BookingID.Valid = true
-		err = app.Repositories.Payment.CreatePayment(&newPayment)
-		if err != nil {
-			return util.ErrInternalServer(ctx, err)
-		}
-
-		// update requester wallet
-		err = app.Repositories.Payment.UpdateWallet(
-			booking.RequesterID,
-			models.PAYMENT_TYPE_REFUND,
-			booking.TotalPrice,
-		)
-		if err != nil {
-			return util.ErrInternalServer(ctx, err)
-		}
+		effects.Payments = append(effects.Payments, models.LedgerPayment{
+			Payment: &newPayment,
+			Entries: []models.LedgerEntry{
+				ledger.Refund(*booking, booking.TotalPrice),
+			},
+		})
 	}
 
 	return nil
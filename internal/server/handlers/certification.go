@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/audit"
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/repository"
+)
+
+var certificationDecisions = []string{
+	models.CERTIFICATION_APPROVED,
+	models.CERTIFICATION_REJECTED,
+}
+
+type CertificationHandler struct {
+	app *util.Application
+}
+
+func NewCertificationHandler(app *util.Application) CertificationHandler {
+	return CertificationHandler{app: app}
+}
+
+// GetCertificationQueue lists certifications for review, oldest first.
+// Pending certifications are listed unless another status is requested.
+func (c CertificationHandler) GetCertificationQueue(ctx echo.Context) error {
+	filter := models.CertificationFilter{
+		UserID: ctx.QueryParam("user_id"),
+		Status: ctx.QueryParam("status"),
+		Page:   models.DefaultPage,
+		Limit:  models.DefaultPageLimit,
+	}
+	if filter.Status == "" {
+		filter.Status = models.CERTIFICATION_PENDING
+	}
+	if filter.UserID != "" && !util.IsValidUUID(filter.UserID) {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid user id")
+	}
+	if page := ctx.QueryParam("page"); page != "" {
+		reqPage, err := strconv.Atoi(page)
+		if err != nil || reqPage < 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid page value")
+		}
+		filter.Page = reqPage
+	}
+	if size := ctx.QueryParam("size"); size != "" {
+		reqSize, err := strconv.Atoi(size)
+		if err != nil || reqSize < 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid size value")
+		}
+		filter.Limit = reqSize
+	}
+
+	certs, err := c.app.Repositories.User.GetAllCertifications(filter)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, certs)
+}
+
+// ReviewCertification approves or rejects a pending certification. Notes
+// are required for rejections, and the user is notified of the decision.
+func (c CertificationHandler) ReviewCertification(ctx echo.Context) error {
+	reqData := struct {
+		Status string `json:"status" validate:"required"`
+		Notes  string `json:"notes" validate:"max=500"`
+	}{}
+
+	if err := ctx.Bind(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	if err := ctx.Validate(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	if !slices.Contains(certificationDecisions, reqData.Status) {
+		return echo.NewHTTPError(http.StatusBadRequest, ErrInvalidCertificationDecision)
+	}
+	if reqData.Status == models.CERTIFICATION_REJECTED && reqData.Notes == "" {
+		return echo.NewHTTPError(
+			http.StatusBadRequest,
+			"notes are required when rejecting a certification.",
+		)
+	}
+
+	cert, err := c.app.Repositories.User.GetCertification(ctx.Param("id"))
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+	reviewer := util.ContextGetUser(ctx)
+	if cert.UserID == reviewer.ID {
+		return echo.NewHTTPError(
+			http.StatusForbidden,
+			"can not review your own certification.",
+		)
+	}
+
+	previous := cert
+	cert.Status = reqData.Status
+	cert.ReviewNotes = reqData.Notes
+	cert.ReviewerID.String = reviewer.ID
+	cert.ReviewerID.Valid = true
+	err = c.app.Repositories.User.ReviewCertification(&cert)
+	if err != nil {
+		if errors.Is(err, repository.ErrCertReviewed) {
+			return echo.NewHTTPError(http.StatusConflict, err)
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+	recordAudit(
+		ctx, c.app,
+		audit.ACTION_CERTIFICATION_REVIEW, audit.ENTITY_CERTIFICATION, cert.ID,
+		previous, cert,
+	)
+
+	notification := models.Notification{
+		Type:    models.NOTIFICATION_TYPE_CERTIFICATION,
+		UserID:  cert.UserID,
+		Message: CertificationMessage(&cert),
+	}
+	if err := c.app.Notifier.Dispatch(&notification); err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, cert)
+}
+
+// helpers
+
+// CertificationMessage describes a change of the certification's status
+// to its owner.
+func CertificationMessage(cert *models.UserCertification) string {
+	name := cert.Title
+	if name == "" {
+		name = "certification"
+	}
+
+	switch cert.Status {
+	case models.CERTIFICATION_APPROVED:
+		return fmt.Sprintf("Your %s has been approved.", name)
+	case models.CERTIFICATION_EXPIRED:
+		return fmt.Sprintf("Your %s has expired and is no longer shown on your profile.", name)
+	default:
+		return fmt.Sprintf("Your %s has been rejected: %s", name, cert.ReviewNotes)
+	}
+}
+
+// certificationFromForm reads the details of the i-th certification
+// uploaded with the form. Each detail is a repeated form field, in the
+// same order as the files.
+func certificationFromForm(form *multipart.Form, i int) (models.UserCertification, error) {
+	value := func(key string) string {
+		if values := form.Value[key]; i < len(values) {
+			return values[i]
+		}
+		return ""
+	}
+
+	cert := models.UserCertification{
+		ID:     uuid.NewString(),
+		Title:  value("title"),
+		Issuer: value("issuer"),
+	}
+	if cert.Title == "" {
+		return cert, errors.New("title is required for each certification")
+	}
+
+	if issuedOn := value("issued_on"); issuedOn != "" {
+		date, err := time.Parse(time.DateOnly, issuedOn)
+		if err != nil {
+			return cert, errors.New("invalid issued_on date, expected YYYY-MM-DD")
+		}
+		cert.IssuedOn.Time = date
+		cert.IssuedOn.Valid = true
+	}
+	if expiresOn := value("expires_on"); expiresOn != "" {
+		date, err := time.Parse(time.DateOnly, expiresOn)
+		if err != nil {
+			return cert, errors.New("invalid expires_on date, expected YYYY-MM-DD")
+		}
+		if date.Before(time.Now()) {
+			return cert, errors.New("certification has already expired")
+		}
+		if cert.IssuedOn.Valid && date.Before(cert.IssuedOn.Time) {
+			return cert, errors.New("expires_on is before issued_on")
+		}
+		cert.ExpiresOn.Time = date
+		cert.ExpiresOn.Valid = true
+	}
+
+	return cert, nil
+}
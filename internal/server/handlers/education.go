@@ -0,0 +1,291 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/repository"
+)
+
+type educationRequest struct {
+	Institute  string `json:"institute" validate:"required,max=200"`
+	Degree     string `json:"degree" validate:"required,max=200"`
+	Discipline string `json:"discipline" validate:"required,max=200"`
+	Start      string `json:"start" validate:"required"`
+	// Finish is left empty while still studying.
+	Finish string `json:"finish"`
+}
+
+type experienceRequest struct {
+	Employer    string `json:"employer" validate:"required,max=200"`
+	Role        string `json:"role" validate:"required,max=200"`
+	Start       string `json:"start" validate:"required"`
+	Finish      string `json:"finish"` // left empty while still employed.
+	Description string `json:"description" validate:"max=2000"`
+}
+
+// GetEducation returns a user's education history, latest first.
+func (u UserHandler) GetEducation(ctx echo.Context) error {
+	id := ctx.Param("id")
+	if !util.IsValidUUID(id) {
+		return echo.ErrBadRequest
+	}
+
+	entries, err := u.app.Repositories.User.GetEducation(id)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, entries)
+}
+
+// CreateEducation adds an entry to the current user's education history.
+func (u UserHandler) CreateEducation(ctx echo.Context) error {
+	if err := ownProfile(ctx); err != nil {
+		return err
+	}
+
+	reqData := educationRequest{}
+	if err := ctx.Bind(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	if err := ctx.Validate(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	start, finish, err := parsePeriod(reqData.Start, reqData.Finish)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	education := models.UserEducation{
+		UserID:     util.ContextGetUser(ctx).ID,
+		Institute:  reqData.Institute,
+		Degree:     reqData.Degree,
+		Discipline: reqData.Discipline,
+		Start:      start,
+		Finish:     finish,
+	}
+	if err := u.app.Repositories.User.CreateEducation(&education); err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, education)
+}
+
+// UpdateEducation replaces an entry of the current user's education
+// history.
+func (u UserHandler) UpdateEducation(ctx echo.Context) error {
+	if err := ownProfile(ctx); err != nil {
+		return err
+	}
+
+	reqData := educationRequest{}
+	if err := ctx.Bind(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	if err := ctx.Validate(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	start, finish, err := parsePeriod(reqData.Start, reqData.Finish)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	education := models.UserEducation{
+		ID:         ctx.Param("entry_id"),
+		UserID:     util.ContextGetUser(ctx).ID,
+		Institute:  reqData.Institute,
+		Degree:     reqData.Degree,
+		Discipline: reqData.Discipline,
+		Start:      start,
+		Finish:     finish,
+	}
+	if !util.IsValidUUID(education.ID) {
+		return echo.ErrBadRequest
+	}
+	err = u.app.Repositories.User.UpdateEducation(&education)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, education)
+}
+
+// DeleteEducation removes an entry of the current user's education
+// history.
+func (u UserHandler) DeleteEducation(ctx echo.Context) error {
+	if err := ownProfile(ctx); err != nil {
+		return err
+	}
+
+	id := ctx.Param("entry_id")
+	if !util.IsValidUUID(id) {
+		return echo.ErrBadRequest
+	}
+	err := u.app.Repositories.User.DeleteEducation(id, util.ContextGetUser(ctx).ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, echo.Map{})
+}
+
+// GetExperience returns a user's work history, latest first.
+func (u UserHandler) GetExperience(ctx echo.Context) error {
+	id := ctx.Param("id")
+	if !util.IsValidUUID(id) {
+		return echo.ErrBadRequest
+	}
+
+	entries, err := u.app.Repositories.User.GetExperience(id)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, entries)
+}
+
+// CreateExperience adds an entry to the current user's work history.
+func (u UserHandler) CreateExperience(ctx echo.Context) error {
+	if err := ownProfile(ctx); err != nil {
+		return err
+	}
+
+	reqData := experienceRequest{}
+	if err := ctx.Bind(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	if err := ctx.Validate(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	start, finish, err := parsePeriod(reqData.Start, reqData.Finish)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	experience := models.UserExperience{
+		UserID:      util.ContextGetUser(ctx).ID,
+		Employer:    reqData.Employer,
+		Role:        reqData.Role,
+		Start:       start,
+		Finish:      finish,
+		Description: reqData.Description,
+	}
+	if err := u.app.Repositories.User.CreateExperience(&experience); err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, experience)
+}
+
+// UpdateExperience replaces an entry of the current user's work history.
+func (u UserHandler) UpdateExperience(ctx echo.Context) error {
+	if err := ownProfile(ctx); err != nil {
+		return err
+	}
+
+	reqData := experienceRequest{}
+	if err := ctx.Bind(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	if err := ctx.Validate(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	start, finish, err := parsePeriod(reqData.Start, reqData.Finish)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	experience := models.UserExperience{
+		ID:          ctx.Param("entry_id"),
+		UserID:      util.ContextGetUser(ctx).ID,
+		Employer:    reqData.Employer,
+		Role:        reqData.Role,
+		Start:       start,
+		Finish:      finish,
+		Description: reqData.Description,
+	}
+	if !util.IsValidUUID(experience.ID) {
+		return echo.ErrBadRequest
+	}
+	err = u.app.Repositories.User.UpdateExperience(&experience)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, experience)
+}
+
+// DeleteExperience removes an entry of the current user's work history.
+func (u UserHandler) DeleteExperience(ctx echo.Context) error {
+	if err := ownProfile(ctx); err != nil {
+		return err
+	}
+
+	id := ctx.Param("entry_id")
+	if !util.IsValidUUID(id) {
+		return echo.ErrBadRequest
+	}
+	err := u.app.Repositories.User.DeleteExperience(id, util.ContextGetUser(ctx).ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, echo.Map{})
+}
+
+// helpers
+
+// ownProfile fails unless the user in the path is the current user.
+func ownProfile(ctx echo.Context) error {
+	if ctx.Param("id") != util.ContextGetUser(ctx).ID {
+		return echo.NewHTTPError(
+			http.StatusForbidden,
+			"can only change your own profile.",
+		)
+	}
+	return nil
+}
+
+// parsePeriod parses the start and optional finish dates of an entry.
+// The finish date, if given, must not be before the start date, and
+// neither may be in the future.
+func parsePeriod(start, finish string) (time.Time, sql.NullTime, error) {
+	startDate, err := util.ParseDate(start)
+	if err != nil {
+		return time.Time{}, sql.NullTime{}, err
+	}
+	if startDate.After(time.Now()) {
+		return time.Time{}, sql.NullTime{}, errors.New("start date is in the future.")
+	}
+	if finish == "" {
+		return startDate, sql.NullTime{}, nil
+	}
+
+	finishDate, err := util.ParseDate(finish)
+	if err != nil {
+		return time.Time{}, sql.NullTime{}, err
+	}
+	if finishDate.Before(startDate) {
+		return time.Time{}, sql.NullTime{}, errors.New("finish date is before start date.")
+	}
+	return startDate, sql.NullTime{Time: finishDate, Valid: true}, nil
+}
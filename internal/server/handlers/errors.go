@@ -3,16 +3,34 @@ package handlers
 import "errors"
 
 var (
-	ErrInvalidPassword         = errors.New("Invalid password!")
-	ErrInvalidToken            = errors.New("Invalid or expired Token")
-	ErrInvalidEmail            = errors.New("Invalid email address")
-	ErrInvalidPhone            = errors.New("Invalid phone number")
-	ErrAlreadyVerified         = errors.New("Already verified")
-	ErrVerificationDependency  = errors.New("email or phone number needs to be verified!")
-	ErrExpiredVerificationCode = errors.New("verification code has expired.")
-	ErrInvalidVerificationCode = errors.New("invalid verification code.")
-	ErrInvalidServiceType      = errors.New("invalid service type.")
-	ErrInvalidBookingType      = errors.New("invalid booking type.")
+	ErrInvalidPassword              = errors.New("Invalid password!")
+	ErrInvalidToken                 = errors.New("Invalid or expired Token")
+	ErrRefreshTokenReused           = errors.New("Refresh token reuse detected, session revoked.")
+	ErrInvalidEmail                 = errors.New("Invalid email address")
+	ErrInvalidPhone                 = errors.New("Invalid phone number")
+	ErrAlreadyVerified              = errors.New("Already verified")
+	ErrVerificationDependency       = errors.New("email or phone number needs to be verified!")
+	ErrExpiredVerificationCode      = errors.New("verification code has expired.")
+	ErrInvalidVerificationCode      = errors.New("invalid verification code.")
+	ErrTooManyVerificationAttempts  = errors.New("too many invalid attempts, request a new verification code.")
+	ErrInvalidTwoFactorCode         = errors.New("invalid two-factor authentication code.")
+	ErrTwoFactorNotEnabled          = errors.New("two-factor authentication is not enabled.")
+	ErrTooManyAttempts              = errors.New("too many failed attempts, try again later.")
+	ErrInvalidResetToken            = errors.New("invalid password reset token.")
+	ErrExpiredResetToken            = errors.New("password reset token has expired.")
+	ErrInvalidServiceType           = errors.New("invalid service type.")
+	ErrInvalidBookingType           = errors.New("invalid booking type.")
+	ErrInvalidRole                  = errors.New("invalid role.")
+	ErrUnverifiedAccountExists      = errors.New("an account with this email already exists. sign in with your password and link the provider from your account.")
+	ErrLastSignInMethod             = errors.New("can not unlink the only sign-in method. set a password first.")
+	ErrWalletNotEmpty               = errors.New("withdraw your wallet balance before deleting your account.")
+	ErrInvalidDocumentType          = errors.New("invalid document type. expected passport, national_id, drivers_license or voters_card.")
+	ErrInvalidKYCDecision           = errors.New("invalid status. expected approved, rejected or needs_resubmission.")
+	ErrInvalidCertificationDecision = errors.New("invalid status. expected approved or rejected.")
+	ErrInvalidNotificationType      = errors.New("invalid notification type.")
+	ErrInvalidNotificationChannel   = errors.New("invalid channel. expected in_app, email, sms or push.")
+	ErrInvalidPushPlatform          = errors.New("invalid platform. expected android or ios.")
 
 	ErrInvalidPlaceAddress = errors.New("Invalid address. Expected street_addr, city, state, country")
+	ErrUnknownPlaceAddress = errors.New("Address could not be located.")
 )
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/labstack/echo/v4"
+	"github.com/markbates/goth"
+
+	"github.com/lokatalent/backend_go/cmd/api/models/response"
+	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/repository"
+)
+
+// GetIdentities returns the providers linked to the current user's account.
+func (a AuthHandler) GetIdentities(ctx echo.Context) error {
+	user := util.ContextGetUser(ctx)
+
+	identities, err := a.app.Repositories.Identity.GetAll(user.ID)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, identities)
+}
+
+// LinkIdentity starts linking the current user's account with a provider,
+// returning the URL the user signs in to the provider at. The provider's
+// callback then links the identity instead of signing in.
+func (a AuthHandler) LinkIdentity(ctx echo.Context) error {
+	user := util.ContextGetUser(ctx)
+	provider := ctx.Param("provider")
+	if _, err := goth.GetProvider(provider); err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "unknown provider.")
+	}
+
+	token, expiresAt, err := util.GenerateLinkToken(a.app, &user, provider)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, response.LinkIdentityResponse{
+		LinkURL: fmt.Sprintf(
+			"%s/auth/%s?link_token=%s",
+			a.app.Config.Origin,
+			url.PathEscape(provider),
+			url.QueryEscape(token),
+		),
+		ExpiresAt: expiresAt,
+	})
+}
+
+// UnlinkIdentity removes a provider from the current user's account,
+// unless it is the only way for the user to sign in.
+func (a AuthHandler) UnlinkIdentity(ctx echo.Context) error {
+	// the user in the context only holds the token's claims.
+	user, err := a.app.Repositories.User.GetByID(util.ContextGetUser(ctx).ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+	provider := ctx.Param("provider")
+
+	if user.Password == "" {
+		identities, err := a.app.Repositories.Identity.GetAll(user.ID)
+		if err != nil {
+			return util.ErrInternalServer(ctx, err)
+		}
+		if len(identities) == 1 && identities[0].Provider == provider {
+			return echo.NewHTTPError(http.StatusForbidden, ErrLastSignInMethod)
+		}
+	}
+
+	err = a.app.Repositories.Identity.Delete(user.ID, provider)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, echo.Map{})
+}
+
+// helpers
+
+// linkIdentity links the identity the user signed in to the provider with
+// to the account of the user the link token was issued to.
+func (a AuthHandler) linkIdentity(ctx echo.Context, linkToken string, user goth.User) error {
+	userID, err := util.ValidateLinkToken(a.app, linkToken, user.Provider)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, ErrInvalidToken)
+	}
+
+	existing, err := a.app.Repositories.Identity.GetBySubject(user.Provider, user.UserID)
+	if err == nil {
+		if existing.UserID == userID {
+			return ctx.JSON(http.StatusOK, existing)
+		}
+		return echo.NewHTTPError(http.StatusConflict, repository.ErrIdentityLinked)
+	}
+	if !errors.Is(err, repository.ErrRecordNotFound) {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	identity := models.UserIdentity{
+		UserID:   userID,
+		Provider: user.Provider,
+		Subject:  user.UserID,
+		Email:    user.Email,
+	}
+	err = a.app.Repositories.Identity.Create(&identity)
+	if err != nil {
+		if errors.Is(err, repository.ErrIdentityLinked) {
+			return echo.NewHTTPError(http.StatusConflict, err)
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, identity)
+}
+
+// providerEmailVerified reports whether the provider has verified the
+// user's email. Facebook only shares verified emails.
+func providerEmailVerified(user goth.User) bool {
+	if user.Provider == models.IDENTITY_FACEBOOK {
+		return user.Email != ""
+	}
+	for _, key := range []string{"email_verified", "verified_email"} {
+		if verified, ok := user.RawData[key].(bool); ok {
+			return verified
+		}
+	}
+	return false
+}
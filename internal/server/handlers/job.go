@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/audit"
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/repository"
+)
+
+type JobHandler struct {
+	app *util.Application
+}
+
+func NewJobHandler(app *util.Application) JobHandler {
+	return JobHandler{app: app}
+}
+
+// GetJobs lists background jobs, latest first. Failed jobs are listed
+// unless another status is requested.
+func (j JobHandler) GetJobs(ctx echo.Context) error {
+	filter := models.JobFilter{
+		Kind:   ctx.QueryParam("kind"),
+		Status: ctx.QueryParam("status"),
+		Page:   models.DefaultPage,
+		Limit:  models.DefaultPageLimit,
+	}
+	if filter.Status == "" {
+		filter.Status = models.JOB_FAILED
+	}
+	if page := ctx.QueryParam("page"); page != "" {
+		reqPage, err := strconv.Atoi(page)
+		if err != nil || reqPage < 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid page value")
+		}
+		filter.Page = reqPage
+	}
+	if size := ctx.QueryParam("size"); size != "" {
+		reqSize, err := strconv.Atoi(size)
+		if err != nil || reqSize < 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid size value")
+		}
+		filter.Limit = reqSize
+	}
+
+	jobs, err := j.app.Repositories.Job.GetAll(filter)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, jobs)
+}
+
+// RetryJob runs a failed job again, with its attempts reset.
+func (j JobHandler) RetryJob(ctx echo.Context) error {
+	id := ctx.Param("id")
+	if !util.IsValidUUID(id) {
+		return echo.ErrBadRequest
+	}
+
+	job, err := j.app.Repositories.Job.Retry(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrJobNotFailed) {
+			return echo.NewHTTPError(http.StatusConflict, err)
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+	recordAudit(
+		ctx, j.app,
+		audit.ACTION_JOB_RETRY, audit.ENTITY_JOB, job.ID,
+		echo.Map{"status": models.JOB_FAILED},
+		echo.Map{"status": job.Status},
+	)
+
+	return ctx.JSON(http.StatusOK, job)
+}
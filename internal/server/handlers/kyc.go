@@ -0,0 +1,310 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/lokatalent/backend_go/cmd/api/models/response"
+	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/audit"
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/repository"
+)
+
+// validity of the links to a submission's files shown to reviewers.
+const kycFileURLDuration = 15 * time.Minute
+
+var kycDocumentTypes = []string{
+	models.KYC_DOCUMENT_PASSPORT,
+	models.KYC_DOCUMENT_NATIONAL_ID,
+	models.KYC_DOCUMENT_DRIVERS_LICENSE,
+	models.KYC_DOCUMENT_VOTERS_CARD,
+}
+
+var kycDecisions = []string{
+	models.KYC_APPROVED,
+	models.KYC_REJECTED,
+	models.KYC_NEEDS_RESUBMISSION,
+}
+
+type KYCHandler struct {
+	app *util.Application
+}
+
+func NewKYCHandler(app *util.Application) KYCHandler {
+	return KYCHandler{app: app}
+}
+
+// SubmitKYC uploads a service provider's government issued ID and selfie
+// for review.
+func (k KYCHandler) SubmitKYC(ctx echo.Context) error {
+	authenticatedUser := util.ContextGetUser(ctx)
+	user, err := k.app.Repositories.User.GetByID(authenticatedUser.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+	if user.ServiceRole != models.SERVICE_PROVIDER && user.ServiceRole != models.SERVICE_BOTH {
+		return echo.NewHTTPError(
+			http.StatusForbidden,
+			"only service providers verify their identity.",
+		)
+	}
+
+	documentType := ctx.FormValue("document_type")
+	if !slices.Contains(kycDocumentTypes, documentType) {
+		return echo.NewHTTPError(http.StatusBadRequest, ErrInvalidDocumentType)
+	}
+
+	latest, err := k.app.Repositories.KYC.GetLatest(user.ID)
+	if err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
+		return util.ErrInternalServer(ctx, err)
+	}
+	if err == nil {
+		switch latest.Status {
+		case models.KYC_APPROVED:
+			return echo.NewHTTPError(http.StatusConflict, ErrAlreadyVerified)
+		case models.KYC_PENDING:
+			return echo.NewHTTPError(http.StatusConflict, repository.ErrKYCPending)
+		}
+	}
+
+	submission := models.KYCSubmission{
+		ID:           uuid.NewString(),
+		UserID:       user.ID,
+		DocumentType: documentType,
+	}
+	submission.DocumentKey = submission.DocumentPath()
+	submission.SelfieKey = submission.SelfiePath()
+
+	err = k.uploadKYCFile(
+		ctx, "document", submission.DocumentKey,
+		util.ContentTypeJPEG, util.ContentTypePNG, util.ContentTypePDF,
+	)
+	if err != nil {
+		return err
+	}
+	err = k.uploadKYCFile(
+		ctx, "selfie", submission.SelfieKey,
+		util.ContentTypeJPEG, util.ContentTypePNG,
+	)
+	if err != nil {
+		k.deleteKYCFiles(ctx, &submission)
+		return err
+	}
+
+	err = k.app.Repositories.KYC.Create(&submission)
+	if err != nil {
+		k.deleteKYCFiles(ctx, &submission)
+		if errors.Is(err, repository.ErrKYCPending) {
+			return echo.NewHTTPError(http.StatusConflict, err)
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, submission)
+}
+
+// GetOwnKYC returns the current user's latest submission.
+func (k KYCHandler) GetOwnKYC(ctx echo.Context) error {
+	authenticatedUser := util.ContextGetUser(ctx)
+
+	submission, err := k.app.Repositories.KYC.GetLatest(authenticatedUser.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, submission)
+}
+
+// GetKYCSubmissions lists submissions for review, oldest first. Pending
+// submissions are listed unless another status is requested.
+func (k KYCHandler) GetKYCSubmissions(ctx echo.Context) error {
+	filter := models.KYCFilter{
+		UserID: ctx.QueryParam("user_id"),
+		Status: ctx.QueryParam("status"),
+		Page:   models.DefaultPage,
+		Limit:  models.DefaultPageLimit,
+	}
+	if filter.Status == "" {
+		filter.Status = models.KYC_PENDING
+	}
+	if filter.UserID != "" && !util.IsValidUUID(filter.UserID) {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid user id")
+	}
+	if page := ctx.QueryParam("page"); page != "" {
+		reqPage, err := strconv.Atoi(page)
+		if err != nil || reqPage < 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid page value")
+		}
+		filter.Page = reqPage
+	}
+	if size := ctx.QueryParam("size"); size != "" {
+		reqSize, err := strconv.Atoi(size)
+		if err != nil || reqSize < 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid size value")
+		}
+		filter.Limit = reqSize
+	}
+
+	submissions, err := k.app.Repositories.KYC.GetAll(filter)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, submissions)
+}
+
+// GetKYCSubmission returns a submission with temporary links to its
+// files.
+func (k KYCHandler) GetKYCSubmission(ctx echo.Context) error {
+	submission, err := k.app.Repositories.KYC.GetByID(ctx.Param("id"))
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	resp := response.KYCSubmissionResponse{KYCSubmission: submission}
+	resp.DocumentURL, err = k.app.Repositories.Storage.GetFileURL(
+		submission.DocumentKey, kycFileURLDuration)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+	resp.SelfieURL, err = k.app.Repositories.Storage.GetFileURL(
+		submission.SelfieKey, kycFileURLDuration)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// ReviewKYC approves or rejects a pending submission, or asks for it to
+// be submitted again. A reason is required unless it is approved, and the
+// provider is notified of the decision.
+func (k KYCHandler) ReviewKYC(ctx echo.Context) error {
+	reqData := struct {
+		Status string `json:"status" validate:"required"`
+		Reason string `json:"reason" validate:"max=500"`
+	}{}
+
+	if err := ctx.Bind(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	if err := ctx.Validate(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	if !slices.Contains(kycDecisions, reqData.Status) {
+		return echo.NewHTTPError(http.StatusBadRequest, ErrInvalidKYCDecision)
+	}
+	if reqData.Status != models.KYC_APPROVED && reqData.Reason == "" {
+		return echo.NewHTTPError(
+			http.StatusBadRequest,
+			"reason is required unless the submission is approved.",
+		)
+	}
+
+	submission, err := k.app.Repositories.KYC.GetByID(ctx.Param("id"))
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+	reviewer := util.ContextGetUser(ctx)
+	if submission.UserID == reviewer.ID {
+		return echo.NewHTTPError(
+			http.StatusForbidden,
+			"can not review your own submission.",
+		)
+	}
+
+	previous := submission
+	submission.Status = reqData.Status
+	submission.Reason = reqData.Reason
+	submission.ReviewerID.String = reviewer.ID
+	submission.ReviewerID.Valid = true
+	err = k.app.Repositories.KYC.Review(&submission)
+	if err != nil {
+		if errors.Is(err, repository.ErrKYCReviewed) {
+			return echo.NewHTTPError(http.StatusConflict, err)
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+	recordAudit(
+		ctx, k.app,
+		audit.ACTION_KYC_REVIEW, audit.ENTITY_KYC, submission.ID,
+		previous, submission,
+	)
+
+	notification := models.Notification{
+		Type:    models.NOTIFICATION_TYPE_KYC,
+		UserID:  submission.UserID,
+		Message: kycDecisionMessage(&submission),
+	}
+	if err := k.app.Notifier.Dispatch(&notification); err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, submission)
+}
+
+// helpers
+
+// uploadKYCFile stores the form file under the private key.
+func (k KYCHandler) uploadKYCFile(ctx echo.Context, field, key string, contentTypes ...string) error {
+	file, fileHeader, err := ctx.Request().FormFile(field)
+	if err != nil {
+		if errors.Is(err, http.ErrMissingFile) {
+			return echo.NewHTTPError(
+				http.StatusBadRequest, "missing "+field+" file")
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+	defer file.Close()
+
+	contentType, err := util.ValidateContentType(fileHeader.Header, contentTypes...)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	_, err = k.app.Repositories.Storage.UploadFile(file, key, contentType)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+	return nil
+}
+
+// deleteKYCFiles removes the files of a submission that could not be
+// saved.
+func (k KYCHandler) deleteKYCFiles(ctx echo.Context, submission *models.KYCSubmission) {
+	for _, key := range []string{submission.DocumentKey, submission.SelfieKey} {
+		if err := k.app.Repositories.Storage.DeleteFile(key); err != nil {
+			ctx.Logger().Error(err)
+		}
+	}
+}
+
+func kycDecisionMessage(submission *models.KYCSubmission) string {
+	switch submission.Status {
+	case models.KYC_APPROVED:
+		return "Your identity verification has been approved."
+	case models.KYC_NEEDS_RESUBMISSION:
+		return "Your identity verification needs to be submitted again: " + submission.Reason
+	default:
+		return "Your identity verification has been rejected: " + submission.Reason
+	}
+}
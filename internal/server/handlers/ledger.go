@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/models"
+)
+
+type LedgerHandler struct {
+	app *util.Application
+}
+
+func NewLedgerHandler(app *util.Application) LedgerHandler {
+	return LedgerHandler{app: app}
+}
+
+func (l LedgerHandler) GetAccounts(ctx echo.Context) error {
+	filter, err := ledgerFilterFromQuery(ctx)
+	if err != nil {
+		return err
+	}
+
+	accounts, err := l.app.Repositories.Ledger.GetAccounts(filter)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, accounts)
+}
+
+func (l LedgerHandler) GetEntries(ctx echo.Context) error {
+	filter, err := ledgerFilterFromQuery(ctx)
+	if err != nil {
+		return err
+	}
+
+	entries, err := l.app.Repositories.Ledger.GetEntries(filter)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, entries)
+}
+
+// helpers
+
+func ledgerFilterFromQuery(ctx echo.Context) (models.LedgerFilter, error) {
+	filter := models.LedgerFilter{
+		AccountType: ctx.QueryParam("account_type"),
+		UserID:      ctx.QueryParam("user_id"),
+		BookingID:   ctx.QueryParam("booking_id"),
+		PaymentID:   ctx.QueryParam("payment_id"),
+		Page:        models.DefaultPage,
+		Limit:       models.DefaultPageLimit,
+	}
+
+	if page := ctx.QueryParam("page"); page != "" {
+		reqPage, err := strconv.Atoi(page)
+		if err != nil || reqPage < 1 {
+			return filter, echo.NewHTTPError(http.StatusBadRequest, "invalid page value")
+		}
+		filter.Page = reqPage
+	}
+	if size := ctx.QueryParam("size"); size != "" {
+		reqSize, err := strconv.Atoi(size)
+		if err != nil || reqSize < 1 {
+			return filter, echo.NewHTTPError(http.StatusBadRequest, "invalid size value")
+		}
+		filter.Limit = reqSize
+	}
+
+	return filter, nil
+}
@@ -1,17 +1,33 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
 	"net/http"
+	"slices"
 	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v4"
 
 	"github.com/lokatalent/backend_go/cmd/api/models/response"
 	"github.com/lokatalent/backend_go/cmd/api/util"
 	"github.com/lokatalent/backend_go/internal/models"
-	// "github.com/lokatalent/backend_go/internal/repository"
+	"github.com/lokatalent/backend_go/internal/notify"
+	"github.com/lokatalent/backend_go/internal/realtime"
+	"github.com/lokatalent/backend_go/internal/repository"
 )
 
+// interval between comments keeping an idle event stream open.
+const streamHeartbeatInterval = 30 * time.Second
+
+var pushPlatforms = []string{
+	models.PUSH_PLATFORM_ANDROID,
+	models.PUSH_PLATFORM_IOS,
+}
+
 type NotificationHandler struct {
 	app *util.Application
 }
@@ -97,3 +113,259 @@ func (n NotificationHandler) Count(ctx echo.Context) error {
 
 	return ctx.JSON(http.StatusOK, count)
 }
+
+// Stream pushes the current user's new notifications, and changes to
+// their unread count, as server-sent events. The unread count is sent
+// first, and the stream ends when the access token expires, for the
+// client to reconnect with a new one.
+func (n NotificationHandler) Stream(ctx echo.Context) error {
+	authenticatedUser := util.ContextGetUser(ctx)
+
+	// subscribe before counting, so no change is missed in between
+	sub := n.app.Realtime.Subscribe(authenticatedUser.ID)
+	defer n.app.Realtime.Unsubscribe(sub)
+
+	count, err := n.app.Repositories.Notification.CountUnseen(authenticatedUser.ID)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+	countData, err := json.Marshal(echo.Map{"count": count})
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	resp := ctx.Response()
+	resp.Header().Set(echo.HeaderContentType, "text/event-stream")
+	resp.Header().Set(echo.HeaderCacheControl, "no-cache")
+	resp.Header().Set(echo.HeaderConnection, "keep-alive")
+	// disable proxy buffering
+	resp.Header().Set("X-Accel-Buffering", "no")
+	resp.WriteHeader(http.StatusOK)
+
+	err = writeStreamEvent(resp, realtime.Event{
+		Type: realtime.EVENT_UNREAD_COUNT,
+		Data: countData,
+	})
+	if err != nil {
+		return nil
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+	expiry := time.NewTimer(time.Until(util.ContextGetTokenExpiry(ctx)))
+	defer expiry.Stop()
+
+	for {
+		select {
+		case <-ctx.Request().Context().Done():
+			return nil
+		case <-expiry.C:
+			return nil
+		case event := <-sub.Events():
+			if err := writeStreamEvent(resp, event); err != nil {
+				return nil
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(resp, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+			resp.Flush()
+		}
+	}
+}
+
+// GetPreferences returns the current user's channels for every type of
+// notification, and their quiet hours.
+func (n NotificationHandler) GetPreferences(ctx echo.Context) error {
+	authenticatedUser := util.ContextGetUser(ctx)
+
+	saved, err := n.app.Repositories.Notification.GetPreferences(authenticatedUser.ID)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	resp := response.NotificationSettingsResponse{
+		Preferences: []models.NotificationPreference{},
+	}
+	types := slices.Sorted(maps.Keys(notify.DefaultChannels))
+	for _, notificationType := range types {
+		preference := models.NotificationPreference{
+			Type:     notificationType,
+			Channels: notify.DefaultChannels[notificationType],
+		}
+		for _, savedPreference := range saved {
+			if savedPreference.Type == notificationType {
+				preference = savedPreference
+			}
+		}
+		resp.Preferences = append(resp.Preferences, preference)
+	}
+
+	quietHours, err := n.app.Repositories.Notification.GetQuietHours(authenticatedUser.ID)
+	if err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
+		return util.ErrInternalServer(ctx, err)
+	}
+	if err == nil {
+		resp.QuietHours = &quietHours
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// SetPreference sets the channels the current user receives a type of
+// notification on. No channels turns the type off.
+func (n NotificationHandler) SetPreference(ctx echo.Context) error {
+	reqData := struct {
+		Channels []string `json:"channels"`
+	}{}
+
+	if err := ctx.Bind(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	notificationType := ctx.Param("type")
+	if _, ok := notify.DefaultChannels[notificationType]; !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, ErrInvalidNotificationType)
+	}
+	channels := []string{}
+	for _, channel := range reqData.Channels {
+		if !slices.Contains(notify.Channels, channel) {
+			return echo.NewHTTPError(http.StatusBadRequest, ErrInvalidNotificationChannel)
+		}
+		if !slices.Contains(channels, channel) {
+			channels = append(channels, channel)
+		}
+	}
+
+	preference := models.NotificationPreference{
+		UserID:   util.ContextGetUser(ctx).ID,
+		Type:     notificationType,
+		Channels: channels,
+	}
+	err := n.app.Repositories.Notification.SetPreference(&preference)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, preference)
+}
+
+// SetQuietHours sets when the current user is not sent SMS and push
+// notifications.
+func (n NotificationHandler) SetQuietHours(ctx echo.Context) error {
+	reqData := struct {
+		Start    string `json:"start" validate:"required"`
+		End      string `json:"end" validate:"required"`
+		Timezone string `json:"timezone" validate:"required"`
+	}{}
+
+	if err := ctx.Bind(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	if err := ctx.Validate(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	quietHours := models.QuietHours{
+		UserID:   util.ContextGetUser(ctx).ID,
+		Start:    reqData.Start,
+		End:      reqData.End,
+		Timezone: reqData.Timezone,
+	}
+	if err := quietHours.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	err := n.app.Repositories.Notification.SetQuietHours(&quietHours)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, quietHours)
+}
+
+// DeleteQuietHours turns the current user's quiet hours off.
+func (n NotificationHandler) DeleteQuietHours(ctx echo.Context) error {
+	authenticatedUser := util.ContextGetUser(ctx)
+
+	err := n.app.Repositories.Notification.DeleteQuietHours(authenticatedUser.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, echo.Map{})
+}
+
+// RegisterDevice registers a mobile device of the current user for push
+// notifications.
+func (n NotificationHandler) RegisterDevice(ctx echo.Context) error {
+	reqData := struct {
+		Token    string `json:"token" validate:"required,max=4096"`
+		Platform string `json:"platform" validate:"required"`
+	}{}
+
+	if err := ctx.Bind(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	if err := ctx.Validate(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	if !slices.Contains(pushPlatforms, reqData.Platform) {
+		return echo.NewHTTPError(http.StatusBadRequest, ErrInvalidPushPlatform)
+	}
+
+	device := models.PushDevice{
+		Token:    reqData.Token,
+		UserID:   util.ContextGetUser(ctx).ID,
+		Platform: reqData.Platform,
+	}
+	err := n.app.Repositories.Notification.AddPushDevice(&device)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, device)
+}
+
+// UnregisterDevice stops push notifications to a device of the current
+// user, e.g. when they sign out of the app.
+func (n NotificationHandler) UnregisterDevice(ctx echo.Context) error {
+	reqData := struct {
+		Token string `json:"token" validate:"required"`
+	}{}
+
+	if err := ctx.Bind(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	if err := ctx.Validate(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	authenticatedUser := util.ContextGetUser(ctx)
+	err := n.app.Repositories.Notification.DeletePushDevice(authenticatedUser.ID, reqData.Token)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, echo.Map{})
+}
+
+// helpers
+
+func writeStreamEvent(resp *echo.Response, event realtime.Event) error {
+	data := event.Data
+	if len(data) == 0 {
+		data = json.RawMessage("{}")
+	}
+	_, err := fmt.Fprintf(resp, "event: %s\ndata: %s\n\n", event.Type, data)
+	if err != nil {
+		return err
+	}
+	resp.Flush()
+	return nil
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/audit"
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/repository"
+)
+
+type OutboxHandler struct {
+	app *util.Application
+}
+
+func NewOutboxHandler(app *util.Application) OutboxHandler {
+	return OutboxHandler{app: app}
+}
+
+// GetOutbox lists outbox messages, latest first. Dead-lettered messages
+// are listed unless another status is requested.
+func (o OutboxHandler) GetOutbox(ctx echo.Context) error {
+	filter := models.OutboxFilter{
+		Kind:   ctx.QueryParam("kind"),
+		Status: ctx.QueryParam("status"),
+		Page:   models.DefaultPage,
+		Limit:  models.DefaultPageLimit,
+	}
+	if filter.Status == "" {
+		filter.Status = models.OUTBOX_DEAD
+	}
+	if page := ctx.QueryParam("page"); page != "" {
+		reqPage, err := strconv.Atoi(page)
+		if err != nil || reqPage < 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid page value")
+		}
+		filter.Page = reqPage
+	}
+	if size := ctx.QueryParam("size"); size != "" {
+		reqSize, err := strconv.Atoi(size)
+		if err != nil || reqSize < 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid size value")
+		}
+		filter.Limit = reqSize
+	}
+
+	messages, err := o.app.Repositories.Outbox.GetAll(filter)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, messages)
+}
+
+// RetryOutboxMessage returns a dead-lettered message to the relay, with
+// its attempts reset.
+func (o OutboxHandler) RetryOutboxMessage(ctx echo.Context) error {
+	id := ctx.Param("id")
+	if !util.IsValidUUID(id) {
+		return echo.ErrBadRequest
+	}
+
+	message, err := o.app.Repositories.Outbox.Retry(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrOutboxNotDead) {
+			return echo.NewHTTPError(http.StatusConflict, err)
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+	recordAudit(
+		ctx, o.app,
+		audit.ACTION_OUTBOX_RETRY, audit.ENTITY_OUTBOX, message.ID,
+		echo.Map{"status": models.OUTBOX_DEAD},
+		echo.Map{"status": message.Status},
+	)
+
+	return ctx.JSON(http.StatusOK, message)
+}
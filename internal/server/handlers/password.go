@@ -0,0 +1,343 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/repository"
+	"github.com/lokatalent/backend_go/internal/throttle"
+)
+
+const (
+	passwordResetTmpl        = "password_reset.gotmpl"
+	maxPasswordResetAttempts = 5
+	passwordResetSentMsg     = "if the account exists, password reset instructions have been sent."
+)
+
+// ForgotPassword sends a password reset token to the user's email, or a
+// code to their phone number. The response does not reveal whether the
+// account exists. Resets are issued a limited number of times per
+// account.
+func (a AuthHandler) ForgotPassword(ctx echo.Context) error {
+	reqData := struct {
+		Email    string `json:"email"`
+		PhoneNum string `json:"phone_num"`
+	}{}
+
+	if err := ctx.Bind(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	// reject addresses locked out by repeated failures
+	ipKey := throttle.IPKey(ctx.RealIP())
+	if err := a.checkThrottle(ctx, ipKey); err != nil {
+		return err
+	}
+
+	var user models.User
+	var channel string
+	var err error
+	switch {
+	case util.IsValidEmail(reqData.Email):
+		channel = models.EMAIL_VERIFICATION
+		user, err = a.app.Repositories.User.GetByEmail(reqData.Email)
+	case util.IsValidPhoneNumber(reqData.PhoneNum):
+		channel = models.PHONE_VERIFICATION
+		user, err = a.app.Repositories.User.GetByPhone(reqData.PhoneNum)
+	default:
+		return echo.NewHTTPError(
+			http.StatusBadRequest,
+			"valid email or phone_num is required!",
+		)
+	}
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return ctx.JSON(http.StatusOK, passwordResetSentMsg)
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	if err := a.checkThrottle(ctx, throttle.AccountKey(user.ID)); err != nil {
+		return err
+	}
+	// accounts that were issued too many resets are silently skipped, so
+	// as not to reveal that they exist.
+	resetKey := throttle.PasswordResetKey(user.ID)
+	resetThrottle, err := a.app.Repositories.Throttle.Get(resetKey)
+	if err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
+		return util.ErrInternalServer(ctx, err)
+	}
+	if err == nil && resetThrottle.Locked() {
+		return ctx.JSON(http.StatusOK, passwordResetSentMsg)
+	}
+
+	// ensure no multiple active reset
+	activeReset, err := a.app.Repositories.User.GetPasswordReset(user.ID)
+	if err != nil {
+		// do not flag not found error
+		if !errors.Is(err, repository.ErrRecordNotFound) {
+			return util.ErrInternalServer(ctx, err)
+		}
+	} else {
+		switch time.Now().Compare(activeReset.ExpiresAt) {
+		case -1:
+			// reset is still active
+			return ctx.JSON(http.StatusOK, passwordResetSentMsg)
+		case 0, 1:
+			// remove expired reset and proceed to send new one.
+			err = a.app.Repositories.User.DeletePasswordReset(user.ID)
+			if err != nil {
+				return util.ErrInternalServer(ctx, err)
+			}
+		}
+	}
+
+	// send reset token
+	var tokenHash string
+	switch channel {
+	case models.EMAIL_VERIFICATION:
+		var token string
+		token, tokenHash, err = util.GenerateToken(a.app.Config.TokenHashKey)
+		if err != nil {
+			return util.ErrInternalServer(ctx, err)
+		}
+		err = a.app.Mailer.Send(
+			user.Email,
+			passwordResetTmpl,
+			struct {
+				FirstName string
+				Token     string
+				Year      int
+			}{
+				FirstName: user.FirstName,
+				Token:     token,
+				Year:      time.Now().Year(),
+			},
+		)
+	case models.PHONE_VERIFICATION:
+		var code int64
+		code, err = util.RandomInt(verificationCodeMin, verificationCodeMax)
+		if err != nil {
+			return util.ErrInternalServer(ctx, err)
+		}
+		tokenHash = util.HashToken(a.app.Config.TokenHashKey, strconv.FormatInt(code, 10))
+		err = a.app.SMSSender.Send(
+			user.PhoneNum,
+			passwordResetTmpl,
+			struct {
+				FirstName string
+				Code      int
+			}{
+				FirstName: user.FirstName,
+				Code:      int(code),
+			},
+		)
+	}
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	err = a.app.Repositories.User.CreatePasswordReset(
+		&models.PasswordReset{
+			UserID:    user.ID,
+			TokenHash: tokenHash,
+			Channel:   channel,
+		},
+	)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+	if _, err := a.recordFailure(resetKey, throttle.PasswordReset); err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, passwordResetSentMsg)
+}
+
+// ResetPassword sets a new password using the token sent by
+// ForgotPassword, and signs the user out of every session.
+func (a AuthHandler) ResetPassword(ctx echo.Context) error {
+	reqData := struct {
+		Email       string `json:"email"`
+		PhoneNum    string `json:"phone_num"`
+		Token       string `json:"token" validate:"required"`
+		NewPassword string `json:"new_password" validate:"required,min=8"`
+	}{}
+
+	if err := ctx.Bind(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	if err := ctx.Validate(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	// reject addresses locked out by repeated failures
+	ipKey := throttle.IPKey(ctx.RealIP())
+	if err := a.checkThrottle(ctx, ipKey); err != nil {
+		return err
+	}
+
+	var user models.User
+	var err error
+	switch {
+	case util.IsValidEmail(reqData.Email):
+		user, err = a.app.Repositories.User.GetByEmail(reqData.Email)
+	case util.IsValidPhoneNumber(reqData.PhoneNum):
+		user, err = a.app.Repositories.User.GetByPhone(reqData.PhoneNum)
+	default:
+		return echo.NewHTTPError(
+			http.StatusBadRequest,
+			"valid email or phone_num is required!",
+		)
+	}
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			if _, err := a.recordFailure(ipKey, throttle.IP); err != nil {
+				return util.ErrInternalServer(ctx, err)
+			}
+			return echo.NewHTTPError(
+				http.StatusBadRequest,
+				ErrInvalidResetToken)
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	accountKey := throttle.AccountKey(user.ID)
+	if err := a.checkThrottle(ctx, accountKey); err != nil {
+		return err
+	}
+
+	reset, err := a.app.Repositories.User.GetPasswordReset(user.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.NewHTTPError(
+				http.StatusBadRequest,
+				ErrInvalidResetToken)
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+	if time.Now().After(reset.ExpiresAt) || reset.Attempts >= maxPasswordResetAttempts {
+		err = a.app.Repositories.User.DeletePasswordReset(user.ID)
+		if err != nil {
+			return util.ErrInternalServer(ctx, err)
+		}
+		return echo.NewHTTPError(
+			http.StatusBadRequest,
+			ErrExpiredResetToken)
+	}
+
+	tokenHash := util.HashToken(a.app.Config.TokenHashKey, reqData.Token)
+	if !hmac.Equal([]byte(tokenHash), []byte(reset.TokenHash)) {
+		err = a.app.Repositories.User.IncrementPasswordResetAttempts(reset.ID)
+		if err != nil {
+			return util.ErrInternalServer(ctx, err)
+		}
+		if _, err := a.recordFailure(ipKey, throttle.IP); err != nil {
+			return util.ErrInternalServer(ctx, err)
+		}
+		lockedUntil, err := a.recordFailure(accountKey, throttle.Account)
+		if err != nil {
+			return util.ErrInternalServer(ctx, err)
+		}
+		if lockedUntil != nil {
+			a.notifyLockout(ctx, &user, *lockedUntil)
+		}
+		return echo.NewHTTPError(
+			http.StatusBadRequest,
+			ErrInvalidResetToken)
+	}
+
+	// a concurrent request may have used the reset already.
+	err = a.app.Repositories.User.ConsumePasswordReset(reset.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.NewHTTPError(
+				http.StatusBadRequest,
+				ErrInvalidResetToken)
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	if err := a.setPassword(&user, reqData.NewPassword); err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, "password successfully reset.")
+}
+
+// ChangePassword changes the authenticated user's password. Every other
+// session is signed out, and the current device gets a new session.
+func (a AuthHandler) ChangePassword(ctx echo.Context) error {
+	reqData := struct {
+		CurrentPassword string `json:"current_password" validate:"required"`
+		NewPassword     string `json:"new_password" validate:"required,min=8"`
+	}{}
+
+	if err := ctx.Bind(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	if err := ctx.Validate(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	authenticatedUser := util.ContextGetUser(ctx)
+	user, err := a.app.Repositories.User.GetByID(authenticatedUser.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	err = util.ValidatePassword(reqData.CurrentPassword, user.Password)
+	if err != nil {
+		return echo.NewHTTPError(
+			http.StatusUnauthorized,
+			ErrInvalidPassword)
+	}
+
+	if err := a.setPassword(&user, reqData.NewPassword); err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+	err = a.app.Repositories.User.DeletePasswordReset(user.ID)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	tokens, err := a.newSession(ctx, &user, "")
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, tokens)
+}
+
+// setPassword stores the user's new password, unlocks their account and
+// revokes all their sessions.
+func (a AuthHandler) setPassword(user *models.User, password string) error {
+	hashedPassword, err := util.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	err = a.app.Repositories.User.UpdatePassword(user.ID, hashedPassword)
+	if err != nil {
+		return err
+	}
+	user.Password = hashedPassword
+
+	// a new password lifts any lockout of the account
+	err = a.app.Repositories.Throttle.Reset(throttle.AccountKey(user.ID))
+	if err != nil {
+		return err
+	}
+
+	return a.app.Repositories.Session.RevokeAll(user.ID)
+}
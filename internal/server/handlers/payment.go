@@ -2,18 +2,31 @@ package handlers
 
 import (
 	"errors"
-	"fmt"
+	"io"
 	"net/http"
+	"slices"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 
 	// "github.com/lokatalent/backend_go/cmd/api/models/response"
 	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/gateway"
+	"github.com/lokatalent/backend_go/internal/ledger"
 	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/rbac"
 	"github.com/lokatalent/backend_go/internal/repository"
 )
 
+const maxWebhookPayload = 1 << 20 // 1MB
+
+// statuses of bookings whose charges are held in escrow.
+var chargeableBookingStatuses = []string{
+	models.BOOKING_OPEN,
+	models.BOOKING_PROVIDER_SELECTED,
+	models.BOOKING_ACCEPTED,
+}
+
 type PaymentHandler struct {
 	app *util.Application
 }
@@ -35,8 +48,7 @@ func (p PaymentHandler) InitializeTransaction(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err)
 	}
 
-	authenticatedUser := util.ContextGetUser(ctx)
-	authUser, err := p.app.Repositories.User.GetByID(authenticatedUser.ID)
+	principal, err := util.ContextGetPrincipal(ctx, p.app)
 	if err != nil {
 		if errors.Is(err, repository.ErrRecordNotFound) {
 			return echo.ErrNotFound
@@ -55,7 +67,7 @@ func (p PaymentHandler) InitializeTransaction(ctx echo.Context) error {
 			return util.ErrInternalServer(ctx, err)
 		}
 	}
-	if booking.RequesterID != authUser.ID {
+	if !rbac.CanPayBooking(principal, &booking) {
 		return echo.NewHTTPError(
 			http.StatusUnauthorized,
 			"only requester can pay for open booking.",
@@ -71,13 +83,12 @@ func (p PaymentHandler) InitializeTransaction(ctx echo.Context) error {
 	if err != nil {
 		if errors.Is(err, repository.ErrRecordNotFound) {
 			paymentRef := uuid.NewString()
-			accessCode, err = initTransaction(
-				authUser.Email,
-				paymentRef,
-				reqData.CallBackURL,
-				booking.TotalPrice,
-				p.app.Config.Paystack.APIKey,
-			)
+			accessCode, err = p.app.PaymentGateway.InitializeCharge(gateway.Charge{
+				Email:       principal.Email,
+				Reference:   paymentRef,
+				CallbackURL: reqData.CallBackURL,
+				Amount:      booking.TotalPrice,
+			})
 			if err != nil {
 				return util.ErrInternalServer(ctx, err)
 			}
@@ -167,56 +178,223 @@ func (p PaymentHandler) VerifyTransaction(ctx echo.Context) error {
 			}
 			return util.ErrInternalServer(ctx, err)
 		}
+	} else {
+		return echo.NewHTTPError(
+			http.StatusForbidden,
+			"only the booking's requester or provider can verify its payments.",
+		)
 	}
 
-	fmt.Println(payment)
 	if payment.Status == models.PAYMENT_STATUS_VERIFIED {
 		return ctx.JSON(http.StatusOK, models.PAYMENT_STATUS_VERIFIED)
 	}
 
 	var status string
 	if authenticatedUser.ID == booking.RequesterID {
-		status, err = verifyTransaction(payment.PaymentRef, p.app.Config.Paystack.APIKey)
+		var amount models.Money
+		status, amount, err = p.app.PaymentGateway.VerifyCharge(payment.PaymentRef)
 		if err != nil {
 			return util.ErrInternalServer(ctx, err)
 		}
+		if status == gateway.STATUS_SUCCESS && !amount.Equal(payment.Amount) {
+			ctx.Logger().Errorf(
+				"charge %s amount mismatch: expected %s, got %s",
+				payment.ID, payment.Amount, amount,
+			)
+			return echo.NewHTTPError(
+				http.StatusConflict,
+				"amount paid does not match the booking price.",
+			)
+		}
 	}
 
 	if authenticatedUser.ID == booking.ProviderID.String {
-		status, err = verifyTransfer(payment.PaymentRef, p.app.Config.Paystack.APIKey)
+		status, err = p.app.PaymentGateway.VerifyTransfer(payment.PaymentRef)
 		if err != nil {
 			return util.ErrInternalServer(ctx, err)
 		}
 	}
 
 	switch status {
-	case "success":
-		_, err = p.app.Repositories.Payment.UpdatePaymentStatus(payment.ID, models.PAYMENT_STATUS_VERIFIED)
+	case gateway.STATUS_SUCCESS:
+		if authenticatedUser.ID == booking.RequesterID {
+			err = settleCharge(p.app, payment)
+		} else {
+			err = settleTransfer(p.app, payment)
+		}
 		if err != nil {
 			return util.ErrInternalServer(ctx, err)
 		}
-		// update wallet
+		return ctx.JSON(http.StatusOK, models.PAYMENT_STATUS_VERIFIED)
+	case gateway.STATUS_ABANDONED, gateway.STATUS_FAILED:
 		if authenticatedUser.ID == booking.RequesterID {
-			err = p.app.Repositories.Payment.UpdateWallet(
-				booking.RequesterID,
-				models.PAYMENT_TYPE_CREDIT,
-				booking.TotalPrice,
-			)
-			if err != nil {
-				return util.ErrInternalServer(ctx, err)
-			}
+			err = cancelCharge(p.app, payment)
+		} else {
+			err = failTransfer(p.app, payment, models.PAYMENT_STATUS_FAILED)
 		}
-		return ctx.JSON(http.StatusOK, models.PAYMENT_STATUS_VERIFIED)
-	case "abandoned", "failed", "reversed":
-		err = p.app.Repositories.Payment.DeleteAccessCode(payment.ID)
 		if err != nil {
 			return util.ErrInternalServer(ctx, err)
 		}
-		_, err = p.app.Repositories.Payment.UpdatePaymentStatus(payment.ID, models.PAYMENT_STATUS_CANCELED)
-		if err != nil {
-			return util.ErrInternalServer(ctx, err)
+	case gateway.STATUS_REVERSED:
+		if authenticatedUser.ID == booking.ProviderID.String {
+			err = failTransfer(p.app, payment, models.PAYMENT_STATUS_REVERSED)
+			if err != nil {
+				return util.ErrInternalServer(ctx, err)
+			}
 		}
 	}
 
 	return ctx.JSON(http.StatusOK, status)
 }
+
+// Webhook receives charge and transfer events from the payment gateway. Events are
+// applied through conditional status transitions, so redelivered events
+// do not change wallets twice.
+func (p PaymentHandler) Webhook(ctx echo.Context) error {
+	payload, err := io.ReadAll(io.LimitReader(ctx.Request().Body, maxWebhookPayload))
+	if err != nil {
+		return echo.ErrBadRequest
+	}
+
+	event, err := p.app.PaymentGateway.ParseWebhook(payload, ctx.Request().Header)
+	if err != nil {
+		if errors.Is(err, gateway.ErrInvalidSignature) {
+			return echo.NewHTTPError(
+				http.StatusUnauthorized,
+				"invalid webhook signature.",
+			)
+		}
+		return echo.ErrBadRequest
+	}
+
+	// acknowledge events for references not issued by this service, so
+	// the gateway stops retrying them.
+	if !util.IsValidUUID(event.Reference) {
+		return ctx.NoContent(http.StatusOK)
+	}
+	payment, err := p.app.Repositories.Payment.GetPayment(models.PaymentFilter{
+		PaymentRef: event.Reference,
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return ctx.NoContent(http.StatusOK)
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	switch event.Type {
+	case gateway.EVENT_CHARGE_SUCCESS:
+		if payment.Type != models.PAYMENT_TYPE_CREDIT {
+			break
+		}
+		if !event.Amount.Equal(payment.Amount) {
+			ctx.Logger().Errorf(
+				"charge %s amount mismatch: expected %s, got %s",
+				payment.ID, payment.Amount, event.Amount,
+			)
+			break
+		}
+		err = settleCharge(p.app, payment)
+	case gateway.EVENT_TRANSFER_SUCCESS:
+		if payment.Type == models.PAYMENT_TYPE_DEBIT {
+			err = settleTransfer(p.app, payment)
+		}
+	case gateway.EVENT_TRANSFER_FAILED:
+		if payment.Type == models.PAYMENT_TYPE_DEBIT {
+			err = failTransfer(p.app, payment, models.PAYMENT_STATUS_FAILED)
+		}
+	case gateway.EVENT_TRANSFER_REVERSED:
+		if payment.Type == models.PAYMENT_TYPE_DEBIT {
+			err = failTransfer(p.app, payment, models.PAYMENT_STATUS_REVERSED)
+		}
+	}
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.NoContent(http.StatusOK)
+}
+
+// helpers
+
+// settleCharge marks a pending booking charge as verified and posts the
+// charge to the ledger. A charge settling after its booking was canceled
+// is refunded to the requester's wallet in the same transaction, rather
+// than held in escrow. It does nothing if the charge has already been
+// settled.
+func settleCharge(app *util.Application, payment models.Payment) error {
+	booking, err := app.Repositories.Booking.GetByID(payment.BookingID.String)
+	if err != nil {
+		return err
+	}
+
+	entries := []models.LedgerEntry{ledger.BookingCharge(booking, payment.Amount)}
+	if !slices.Contains(chargeableBookingStatuses, booking.Status) {
+		entries = append(entries, ledger.Refund(booking, payment.Amount))
+	}
+	_, err = app.Repositories.Payment.TransitionPaymentStatus(
+		payment.ID,
+		[]string{models.PAYMENT_STATUS_PENDING},
+		models.PAYMENT_STATUS_VERIFIED,
+		entries...,
+	)
+	if err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
+
+// cancelCharge cancels a pending booking charge that was abandoned or
+// failed at the payment gateway.
+func cancelCharge(app *util.Application, payment models.Payment) error {
+	_, err := app.Repositories.Payment.TransitionPaymentStatus(
+		payment.ID,
+		[]string{models.PAYMENT_STATUS_PENDING},
+		models.PAYMENT_STATUS_CANCELED,
+	)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	return app.Repositories.Payment.DeleteAccessCode(payment.ID)
+}
+
+// settleTransfer marks a pending payout to a service provider as verified.
+func settleTransfer(app *util.Application, payment models.Payment) error {
+	_, err := app.Repositories.Payment.TransitionPaymentStatus(
+		payment.ID,
+		[]string{models.PAYMENT_STATUS_PENDING},
+		models.PAYMENT_STATUS_VERIFIED,
+	)
+	if err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
+
+// failTransfer records a payout that failed or was reversed, and returns
+// the amount to the service provider's earnings so it is not lost.
+func failTransfer(app *util.Application, payment models.Payment, status string) error {
+	booking, err := app.Repositories.Booking.GetByID(payment.BookingID.String)
+	if err != nil {
+		return err
+	}
+
+	from := []string{models.PAYMENT_STATUS_PENDING}
+	if status == models.PAYMENT_STATUS_REVERSED {
+		from = append(from, models.PAYMENT_STATUS_VERIFIED)
+	}
+	_, err = app.Repositories.Payment.TransitionPaymentStatus(
+		payment.ID,
+		from,
+		status,
+		ledger.PayoutReturned(booking, payment.Amount),
+	)
+	if err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,569 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/gateway"
+	"github.com/lokatalent/backend_go/internal/ledger"
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/repository"
+)
+
+const testWebhookSecret = "test-secret"
+
+// memPayments is an in-memory PaymentRepository holding the payments,
+// access codes and ledger entries the payment handlers use.
+type memPayments struct {
+	repository.PaymentRepository
+
+	mu          sync.Mutex
+	payments    map[string]models.Payment
+	accessCodes map[string]string
+	entries     []models.LedgerEntry
+}
+
+func (m *memPayments) GetPayment(filter models.PaymentFilter) (models.Payment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, payment := range m.payments {
+		if (filter.ID == "" || payment.ID == filter.ID) &&
+			(filter.BookingID == "" || payment.BookingID.String == filter.BookingID) &&
+			(filter.Type == "" || payment.Type == filter.Type) &&
+			(filter.PaymentRef == "" || payment.PaymentRef == filter.PaymentRef) &&
+			(filter.Status == "" || payment.Status == filter.Status) {
+			return payment, nil
+		}
+	}
+	return models.Payment{}, repository.ErrRecordNotFound
+}
+
+func (m *memPayments) TransitionPaymentStatus(id string, from []string, to string, entries ...models.LedgerEntry) (models.Payment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	payment, ok := m.payments[id]
+	if !ok {
+		return models.Payment{}, repository.ErrRecordNotFound
+	}
+	for _, status := range from {
+		if payment.Status == status {
+			payment.Status = to
+			m.payments[id] = payment
+			m.entries = append(m.entries, entries...)
+			return payment, nil
+		}
+	}
+	return models.Payment{}, repository.ErrRecordNotFound
+}
+
+func (m *memPayments) DeleteAccessCode(paymentID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.accessCodes, paymentID)
+	return nil
+}
+
+func (m *memPayments) status(id string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.payments[id].Status
+}
+
+func (m *memPayments) posted() []models.LedgerEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]models.LedgerEntry{}, m.entries...)
+}
+
+// memBookings is an in-memory BookingRepository for looking up bookings.
+type memBookings struct {
+	repository.BookingRepository
+
+	bookings map[string]models.Booking
+}
+
+func (m *memBookings) GetByID(id string) (models.Booking, error) {
+	booking, ok := m.bookings[id]
+	if !ok {
+		return models.Booking{}, repository.ErrRecordNotFound
+	}
+	return booking, nil
+}
+
+type paymentFixture struct {
+	handler  PaymentHandler
+	engine   *echo.Echo
+	gateway  *gateway.Fake
+	payments *memPayments
+	bookings *memBookings
+	booking  models.Booking
+	charge   models.Payment
+	transfer models.Payment
+}
+
+// newPaymentFixture returns a payment handler backed by the fake gateway,
+// with a booking, its pending charge and a pending payout to its provider.
+func newPaymentFixture(t *testing.T) *paymentFixture {
+	t.Helper()
+
+	booking := models.Booking{
+		ID:          uuid.NewString(),
+		RequesterID: uuid.NewString(),
+		TotalPrice:  models.NGN(1500000),
+		ActualPrice: models.NGN(1350000),
+		Status:      models.BOOKING_OPEN,
+	}
+	booking.ProviderID.String = uuid.NewString()
+	booking.ProviderID.Valid = true
+
+	charge := models.Payment{
+		ID:         uuid.NewString(),
+		Type:       models.PAYMENT_TYPE_CREDIT,
+		PaymentRef: uuid.NewString(),
+		Amount:     booking.TotalPrice,
+		Status:     models.PAYMENT_STATUS_PENDING,
+	}
+	charge.BookingID.String = booking.ID
+	charge.BookingID.Valid = true
+
+	transfer := models.Payment{
+		ID:         uuid.NewString(),
+		Type:       models.PAYMENT_TYPE_DEBIT,
+		PaymentRef: uuid.NewString(),
+		Amount:     booking.ActualPrice,
+		Status:     models.PAYMENT_STATUS_PENDING,
+	}
+	transfer.BookingID = charge.BookingID
+
+	payments := &memPayments{
+		payments: map[string]models.Payment{
+			charge.ID:   charge,
+			transfer.ID: transfer,
+		},
+		accessCodes: map[string]string{charge.ID: "fake_access_" + charge.PaymentRef},
+	}
+	fake := gateway.NewFake(testWebhookSecret, gateway.STATUS_SUCCESS)
+	_, err := fake.InitializeCharge(gateway.Charge{
+		Reference: charge.PaymentRef,
+		Amount:    charge.Amount,
+	})
+	if err != nil {
+		t.Fatalf("InitializeCharge: %v", err)
+	}
+	bookings := &memBookings{
+		bookings: map[string]models.Booking{booking.ID: booking},
+	}
+	app := &util.Application{
+		Repositories: &repository.Repositories{
+			Payment: payments,
+			Booking: bookings,
+		},
+		PaymentGateway: fake,
+	}
+
+	engine := echo.New()
+	engine.Validator = util.NewCustomValidator()
+
+	return &paymentFixture{
+		handler:  NewPaymentHandler(app),
+		engine:   engine,
+		gateway:  fake,
+		payments: payments,
+		bookings: bookings,
+		booking:  booking,
+		charge:   charge,
+		transfer: transfer,
+	}
+}
+
+// webhook delivers a signed webhook event, returning the response status.
+func (f *paymentFixture) webhook(t *testing.T, event gateway.Event) int {
+	t.Helper()
+
+	payload, header, err := f.gateway.Webhook(event)
+	if err != nil {
+		t.Fatalf("Webhook: %v", err)
+	}
+	return f.deliver(t, payload, header)
+}
+
+func (f *paymentFixture) deliver(t *testing.T, payload []byte, header http.Header) int {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/payments/webhook", bytes.NewReader(payload))
+	req.Header = header
+	rec := httptest.NewRecorder()
+	if err := f.handler.Webhook(f.engine.NewContext(req, rec)); err != nil {
+		t.Fatalf("Webhook handler: %v", err)
+	}
+	return rec.Code
+}
+
+// verify calls VerifyTransaction for the booking as userID, returning the
+// response status and body.
+func (f *paymentFixture) verify(t *testing.T, userID string) (int, string) {
+	t.Helper()
+
+	rec, err := f.verifyRequest(userID)
+	if err != nil {
+		t.Fatalf("VerifyTransaction: %v", err)
+	}
+	var status string
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+		t.Fatalf("decoding %q: %v", rec.Body.String(), err)
+	}
+	return rec.Code, status
+}
+
+func (f *paymentFixture) verifyRequest(userID string) (*httptest.ResponseRecorder, error) {
+	body := `{"booking_id":"` + f.booking.ID + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/payments/verify", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	ctx := f.engine.NewContext(req, rec)
+	claims := &util.CustomAccessJWTClaims{}
+	claims.ID = userID
+	ctx.Set(util.ContextKeyUser, &jwt.Token{Claims: claims})
+
+	return rec, f.handler.VerifyTransaction(ctx)
+}
+
+// assertHTTPError checks that err is an HTTP error with code.
+func assertHTTPError(t *testing.T, err error, code int) {
+	t.Helper()
+
+	httpErr, ok := err.(*echo.HTTPError)
+	if !ok || httpErr.Code != code {
+		t.Fatalf("error = %v, want %d", err, code)
+	}
+}
+
+func assertEntries(t *testing.T, got []models.LedgerEntry, want ...models.LedgerEntry) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("posted %d ledger entries, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].Description != want[i].Description {
+			t.Errorf("entry %d = %q, want %q", i, got[i].Description, want[i].Description)
+		}
+		if len(got[i].Postings) != len(want[i].Postings) {
+			t.Errorf("entry %d has %d postings, want %d", i, len(got[i].Postings), len(want[i].Postings))
+		}
+	}
+}
+
+func TestVerifyChargeSuccess(t *testing.T) {
+	f := newPaymentFixture(t)
+
+	code, status := f.verify(t, f.booking.RequesterID)
+	if code != http.StatusOK || status != models.PAYMENT_STATUS_VERIFIED {
+		t.Fatalf("VerifyTransaction = %d %q, want 200 %q", code, status, models.PAYMENT_STATUS_VERIFIED)
+	}
+	if got := f.payments.status(f.charge.ID); got != models.PAYMENT_STATUS_VERIFIED {
+		t.Errorf("charge status = %s, want %s", got, models.PAYMENT_STATUS_VERIFIED)
+	}
+	assertEntries(t, f.payments.posted(), ledger.BookingCharge(f.booking, f.charge.Amount))
+
+	// verifying again reports the settled charge without posting it twice.
+	code, status = f.verify(t, f.booking.RequesterID)
+	if code != http.StatusOK || status != models.PAYMENT_STATUS_VERIFIED {
+		t.Fatalf("second VerifyTransaction = %d %q", code, status)
+	}
+	assertEntries(t, f.payments.posted(), ledger.BookingCharge(f.booking, f.charge.Amount))
+}
+
+func TestVerifyChargeAmountMismatch(t *testing.T) {
+	f := newPaymentFixture(t)
+	_, err := f.gateway.InitializeCharge(gateway.Charge{
+		Reference: f.charge.PaymentRef,
+		Amount:    f.charge.Amount.Sub(models.NGN(100)),
+	})
+	if err != nil {
+		t.Fatalf("InitializeCharge: %v", err)
+	}
+
+	_, err = f.verifyRequest(f.booking.RequesterID)
+	assertHTTPError(t, err, http.StatusConflict)
+	if got := f.payments.status(f.charge.ID); got != models.PAYMENT_STATUS_PENDING {
+		t.Errorf("charge status = %s, want %s", got, models.PAYMENT_STATUS_PENDING)
+	}
+	assertEntries(t, f.payments.posted())
+}
+
+func TestVerifyOtherUser(t *testing.T) {
+	f := newPaymentFixture(t)
+
+	_, err := f.verifyRequest(uuid.NewString())
+	assertHTTPError(t, err, http.StatusForbidden)
+	assertEntries(t, f.payments.posted())
+}
+
+func TestVerifyChargeAbandoned(t *testing.T) {
+	f := newPaymentFixture(t)
+	f.gateway.SetStatus(f.charge.PaymentRef, gateway.STATUS_ABANDONED)
+
+	code, status := f.verify(t, f.booking.RequesterID)
+	if code != http.StatusOK || status != gateway.STATUS_ABANDONED {
+		t.Fatalf("VerifyTransaction = %d %q, want 200 %q", code, status, gateway.STATUS_ABANDONED)
+	}
+	if got := f.payments.status(f.charge.ID); got != models.PAYMENT_STATUS_CANCELED {
+		t.Errorf("charge status = %s, want %s", got, models.PAYMENT_STATUS_CANCELED)
+	}
+	if _, ok := f.payments.accessCodes[f.charge.ID]; ok {
+		t.Error("access code of the canceled charge was kept")
+	}
+	assertEntries(t, f.payments.posted())
+}
+
+func TestVerifyChargePending(t *testing.T) {
+	f := newPaymentFixture(t)
+	f.gateway.SetStatus(f.charge.PaymentRef, gateway.STATUS_PENDING)
+
+	_, status := f.verify(t, f.booking.RequesterID)
+	if status != gateway.STATUS_PENDING {
+		t.Fatalf("VerifyTransaction = %q, want %q", status, gateway.STATUS_PENDING)
+	}
+	if got := f.payments.status(f.charge.ID); got != models.PAYMENT_STATUS_PENDING {
+		t.Errorf("charge status = %s, want %s", got, models.PAYMENT_STATUS_PENDING)
+	}
+	assertEntries(t, f.payments.posted())
+}
+
+func TestVerifyTransfer(t *testing.T) {
+	tests := []struct {
+		name          string
+		gatewayStatus string
+		paymentStatus string
+		returned      bool
+	}{
+		{
+			name:          "success",
+			gatewayStatus: gateway.STATUS_SUCCESS,
+			paymentStatus: models.PAYMENT_STATUS_VERIFIED,
+		},
+		{
+			name:          "failed",
+			gatewayStatus: gateway.STATUS_FAILED,
+			paymentStatus: models.PAYMENT_STATUS_FAILED,
+			returned:      true,
+		},
+		{
+			name:          "reversed",
+			gatewayStatus: gateway.STATUS_REVERSED,
+			paymentStatus: models.PAYMENT_STATUS_REVERSED,
+			returned:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newPaymentFixture(t)
+			f.gateway.SetStatus(f.transfer.PaymentRef, tt.gatewayStatus)
+
+			code, _ := f.verify(t, f.booking.ProviderID.String)
+			if code != http.StatusOK {
+				t.Fatalf("VerifyTransaction = %d, want 200", code)
+			}
+			if got := f.payments.status(f.transfer.ID); got != tt.paymentStatus {
+				t.Errorf("transfer status = %s, want %s", got, tt.paymentStatus)
+			}
+			if got := f.payments.status(f.charge.ID); got != models.PAYMENT_STATUS_PENDING {
+				t.Errorf("charge status = %s, want it untouched", got)
+			}
+			if tt.returned {
+				assertEntries(t, f.payments.posted(), ledger.PayoutReturned(f.booking, f.transfer.Amount))
+			} else {
+				assertEntries(t, f.payments.posted())
+			}
+		})
+	}
+}
+
+func TestWebhookChargeIdempotent(t *testing.T) {
+	f := newPaymentFixture(t)
+	event := gateway.Event{
+		Type:      gateway.EVENT_CHARGE_SUCCESS,
+		Reference: f.charge.PaymentRef,
+		Status:    gateway.STATUS_SUCCESS,
+		Amount:    f.charge.Amount,
+	}
+
+	for i := range 3 {
+		if code := f.webhook(t, event); code != http.StatusOK {
+			t.Fatalf("delivery %d = %d, want 200", i+1, code)
+		}
+	}
+
+	if got := f.payments.status(f.charge.ID); got != models.PAYMENT_STATUS_VERIFIED {
+		t.Errorf("charge status = %s, want %s", got, models.PAYMENT_STATUS_VERIFIED)
+	}
+	// a replayed event must not credit the charge twice.
+	assertEntries(t, f.payments.posted(), ledger.BookingCharge(f.booking, f.charge.Amount))
+}
+
+func TestWebhookChargeAfterCancel(t *testing.T) {
+	for _, status := range []string{models.BOOKING_CANCELED, models.BOOKING_COMPLETED} {
+		t.Run(status, func(t *testing.T) {
+			f := newPaymentFixture(t)
+			f.booking.Status = status
+			f.bookings.bookings[f.booking.ID] = f.booking
+
+			f.webhook(t, gateway.Event{
+				Type:      gateway.EVENT_CHARGE_SUCCESS,
+				Reference: f.charge.PaymentRef,
+				Amount:    f.charge.Amount,
+			})
+			if got := f.payments.status(f.charge.ID); got != models.PAYMENT_STATUS_VERIFIED {
+				t.Errorf("charge status = %s, want %s", got, models.PAYMENT_STATUS_VERIFIED)
+			}
+			// the late charge is returned to the requester's wallet.
+			assertEntries(
+				t,
+				f.payments.posted(),
+				ledger.BookingCharge(f.booking, f.charge.Amount),
+				ledger.Refund(f.booking, f.charge.Amount),
+			)
+		})
+	}
+}
+
+func TestWebhookChargeAmountMismatch(t *testing.T) {
+	f := newPaymentFixture(t)
+	code := f.webhook(t, gateway.Event{
+		Type:      gateway.EVENT_CHARGE_SUCCESS,
+		Reference: f.charge.PaymentRef,
+		Status:    gateway.STATUS_SUCCESS,
+		Amount:    f.charge.Amount.Sub(models.NGN(100)),
+	})
+	if code != http.StatusOK {
+		t.Fatalf("Webhook = %d, want 200", code)
+	}
+	if got := f.payments.status(f.charge.ID); got != models.PAYMENT_STATUS_PENDING {
+		t.Errorf("charge status = %s, want %s", got, models.PAYMENT_STATUS_PENDING)
+	}
+	assertEntries(t, f.payments.posted())
+}
+
+func TestWebhookChargeForTransfer(t *testing.T) {
+	f := newPaymentFixture(t)
+	f.webhook(t, gateway.Event{
+		Type:      gateway.EVENT_CHARGE_SUCCESS,
+		Reference: f.transfer.PaymentRef,
+		Status:    gateway.STATUS_SUCCESS,
+		Amount:    f.transfer.Amount,
+	})
+	if got := f.payments.status(f.transfer.ID); got != models.PAYMENT_STATUS_PENDING {
+		t.Errorf("transfer status = %s, want %s", got, models.PAYMENT_STATUS_PENDING)
+	}
+	assertEntries(t, f.payments.posted())
+}
+
+func TestWebhookTransferEvents(t *testing.T) {
+	tests := []struct {
+		name     string
+		events   []string
+		status   string
+		returned int
+	}{
+		{
+			name:   "success",
+			events: []string{gateway.EVENT_TRANSFER_SUCCESS, gateway.EVENT_TRANSFER_SUCCESS},
+			status: models.PAYMENT_STATUS_VERIFIED,
+		},
+		{
+			name:     "failed replayed",
+			events:   []string{gateway.EVENT_TRANSFER_FAILED, gateway.EVENT_TRANSFER_FAILED},
+			status:   models.PAYMENT_STATUS_FAILED,
+			returned: 1,
+		},
+		{
+			name:     "reversed after success",
+			events:   []string{gateway.EVENT_TRANSFER_SUCCESS, gateway.EVENT_TRANSFER_REVERSED},
+			status:   models.PAYMENT_STATUS_REVERSED,
+			returned: 1,
+		},
+		{
+			name:     "reversed replayed",
+			events:   []string{gateway.EVENT_TRANSFER_REVERSED, gateway.EVENT_TRANSFER_REVERSED},
+			status:   models.PAYMENT_STATUS_REVERSED,
+			returned: 1,
+		},
+		{
+			name:     "success after failure",
+			events:   []string{gateway.EVENT_TRANSFER_FAILED, gateway.EVENT_TRANSFER_SUCCESS},
+			status:   models.PAYMENT_STATUS_FAILED,
+			returned: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newPaymentFixture(t)
+			for _, eventType := range tt.events {
+				code := f.webhook(t, gateway.Event{
+					Type:      eventType,
+					Reference: f.transfer.PaymentRef,
+					Amount:    f.transfer.Amount,
+				})
+				if code != http.StatusOK {
+					t.Fatalf("%s = %d, want 200", eventType, code)
+				}
+			}
+
+			if got := f.payments.status(f.transfer.ID); got != tt.status {
+				t.Errorf("transfer status = %s, want %s", got, tt.status)
+			}
+			want := []models.LedgerEntry{}
+			for range tt.returned {
+				want = append(want, ledger.PayoutReturned(f.booking, f.transfer.Amount))
+			}
+			assertEntries(t, f.payments.posted(), want...)
+		})
+	}
+}
+
+func TestWebhookInvalidSignature(t *testing.T) {
+	f := newPaymentFixture(t)
+	payload, header, err := f.gateway.Webhook(gateway.Event{
+		Type:      gateway.EVENT_CHARGE_SUCCESS,
+		Reference: f.charge.PaymentRef,
+		Amount:    f.charge.Amount,
+	})
+	if err != nil {
+		t.Fatalf("Webhook: %v", err)
+	}
+	forged := bytes.Replace(payload, []byte(f.charge.PaymentRef), []byte(f.transfer.PaymentRef), 1)
+
+	req := httptest.NewRequest(http.MethodPost, "/payments/webhook", bytes.NewReader(forged))
+	req.Header = header
+	err = f.handler.Webhook(f.engine.NewContext(req, httptest.NewRecorder()))
+	assertHTTPError(t, err, http.StatusUnauthorized)
+	assertEntries(t, f.payments.posted())
+}
+
+func TestWebhookUnknownReference(t *testing.T) {
+	f := newPaymentFixture(t)
+	for _, reference := range []string{uuid.NewString(), "not-issued-here"} {
+		code := f.webhook(t, gateway.Event{
+			Type:      gateway.EVENT_CHARGE_SUCCESS,
+			Reference: reference,
+			Amount:    f.charge.Amount,
+		})
+		if code != http.StatusOK {
+			t.Errorf("Webhook for %q = %d, want 200", reference, code)
+		}
+	}
+	assertEntries(t, f.payments.posted())
+}
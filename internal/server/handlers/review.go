@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/repository"
+)
+
+type ReviewHandler struct {
+	app *util.Application
+}
+
+func NewReviewHandler(app *util.Application) ReviewHandler {
+	return ReviewHandler{app: app}
+}
+
+// CreateReview rates the other side of a completed booking. Requesters
+// review the provider and providers review the requester, once each.
+func (r ReviewHandler) CreateReview(ctx echo.Context) error {
+	reqData := struct {
+		Rating  int    `json:"rating" validate:"required,gte=1,lte=5"`
+		Comment string `json:"comment" validate:"max=2000"`
+	}{}
+
+	if err := ctx.Bind(&reqData); err != nil {
+		return echo.ErrBadRequest
+	}
+	if err := ctx.Validate(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	booking, err := r.app.Repositories.Booking.GetByID(ctx.Param("id"))
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+	if booking.Status != models.BOOKING_COMPLETED {
+		return echo.NewHTTPError(
+			http.StatusForbidden,
+			"can only review completed booking.",
+		)
+	}
+
+	authenticatedUser := util.ContextGetUser(ctx)
+	review := models.Review{
+		BookingID:  booking.ID,
+		ReviewerID: authenticatedUser.ID,
+		Rating:     reqData.Rating,
+		Comment:    reqData.Comment,
+	}
+	switch authenticatedUser.ID {
+	case booking.RequesterID:
+		review.ReviewerRole = models.REVIEWER_REQUESTER
+		review.RevieweeID = booking.ProviderID.String
+	case booking.ProviderID.String:
+		review.ReviewerRole = models.REVIEWER_PROVIDER
+		review.RevieweeID = booking.RequesterID
+	default:
+		return echo.NewHTTPError(
+			http.StatusForbidden,
+			"only booking requester or provider can review booking.",
+		)
+	}
+
+	err = r.app.Repositories.Review.Create(&review)
+	if err != nil {
+		if errors.Is(err, repository.ErrDuplicateReview) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, review)
+}
+
+func (r ReviewHandler) GetBookingReviews(ctx echo.Context) error {
+	bookingID := ctx.Param("id")
+
+	if !util.IsValidUUID(bookingID) {
+		return echo.ErrBadRequest
+	}
+
+	reviews, err := r.app.Repositories.Review.GetForBooking(bookingID)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, reviews)
+}
+
+// GetUserReviews lists reviews received by a user along with their
+// rating as a provider.
+func (r ReviewHandler) GetUserReviews(ctx echo.Context) error {
+	userID := ctx.Param("id")
+
+	if !util.IsValidUUID(userID) {
+		return echo.ErrBadRequest
+	}
+	reqData := struct {
+		Page         int    `query:"page" validate:"gte=1"`
+		PageSize     int    `query:"size" validate:"required,gte=1"`
+		ReviewerRole string `query:"reviewer_role"`
+	}{}
+
+	if err := ctx.Bind(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	if err := ctx.Validate(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	if reqData.ReviewerRole != "" &&
+		reqData.ReviewerRole != models.REVIEWER_REQUESTER &&
+		reqData.ReviewerRole != models.REVIEWER_PROVIDER {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid reviewer_role value")
+	}
+
+	reviews, err := r.app.Repositories.Review.GetForUser(models.ReviewFilter{
+		RevieweeID:   userID,
+		ReviewerRole: reqData.ReviewerRole,
+		Page:         reqData.Page,
+		Limit:        reqData.PageSize,
+	})
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+	summary, err := r.app.Repositories.Review.GetSummary(userID)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, map[string]any{
+		"rating":       summary.Rating,
+		"review_count": summary.ReviewCount,
+		"reviews":      reviews,
+	})
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/audit"
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/repository"
+)
+
+type RoleHandler struct {
+	app *util.Application
+}
+
+func NewRoleHandler(app *util.Application) RoleHandler {
+	return RoleHandler{app: app}
+}
+
+func (r RoleHandler) GetRoles(ctx echo.Context) error {
+	roles, err := r.app.Repositories.Role.GetAll()
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, roles)
+}
+
+func (r RoleHandler) GetPermissions(ctx echo.Context) error {
+	permissions, err := r.app.Repositories.Role.GetPermissions()
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, permissions)
+}
+
+func (r RoleHandler) CreateRole(ctx echo.Context) error {
+	reqData := struct {
+		Name              string   `json:"name" validate:"required"`
+		Description       string   `json:"description"`
+		RequiresTwoFactor bool     `json:"requires_two_factor"`
+		Permissions       []string `json:"permissions"`
+	}{}
+
+	if err := ctx.Bind(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	if err := ctx.Validate(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	if !util.IsValidRoleName(reqData.Name) {
+		return echo.NewHTTPError(
+			http.StatusBadRequest,
+			"invalid role name. expected lowercase letters, digits and underscores.",
+		)
+	}
+
+	role := models.Role{
+		Name:              reqData.Name,
+		Description:       reqData.Description,
+		RequiresTwoFactor: reqData.RequiresTwoFactor,
+		Permissions:       reqData.Permissions,
+	}
+	if role.Permissions == nil {
+		role.Permissions = []string{}
+	}
+
+	err := r.app.Repositories.Role.Create(&role)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicateRole):
+			return echo.NewHTTPError(http.StatusConflict, err)
+		case errors.Is(err, repository.ErrUnknownPermission):
+			return echo.NewHTTPError(http.StatusBadRequest, err)
+		default:
+			return util.ErrInternalServer(ctx, err)
+		}
+	}
+	recordAudit(ctx, r.app, audit.ACTION_ROLE_CREATE, audit.ENTITY_ROLE, role.Name, nil, role)
+
+	return ctx.JSON(http.StatusCreated, role)
+}
+
+// UpdateRole changes a role's description, two-factor requirement and
+// permissions. The super admin role, which manages roles, can not be
+// changed so that it is never locked out.
+func (r RoleHandler) UpdateRole(ctx echo.Context) error {
+	reqData := struct {
+		Description       *string   `json:"description"`
+		RequiresTwoFactor *bool     `json:"requires_two_factor"`
+		Permissions       *[]string `json:"permissions"`
+	}{}
+
+	if err := ctx.Bind(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	role, err := r.app.Repositories.Role.Get(ctx.Param("name"))
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+	if role.Name == models.USER_ADMIN_SUPER {
+		return echo.NewHTTPError(
+			http.StatusForbidden,
+			"super admin role can not be changed.",
+		)
+	}
+
+	previous := role
+	if reqData.Description != nil {
+		role.Description = *reqData.Description
+	}
+	if reqData.RequiresTwoFactor != nil {
+		role.RequiresTwoFactor = *reqData.RequiresTwoFactor
+	}
+	if reqData.Permissions != nil {
+		role.Permissions = *reqData.Permissions
+	}
+
+	err = r.app.Repositories.Role.Update(&role)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			return echo.ErrNotFound
+		case errors.Is(err, repository.ErrUnknownPermission):
+			return echo.NewHTTPError(http.StatusBadRequest, err)
+		default:
+			return util.ErrInternalServer(ctx, err)
+		}
+	}
+	recordAudit(ctx, r.app, audit.ACTION_ROLE_UPDATE, audit.ENTITY_ROLE, role.Name, previous, role)
+
+	return ctx.JSON(http.StatusOK, role)
+}
+
+func (r RoleHandler) DeleteRole(ctx echo.Context) error {
+	role, err := r.app.Repositories.Role.Get(ctx.Param("name"))
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+	if role.Builtin {
+		return echo.NewHTTPError(
+			http.StatusForbidden,
+			"built-in roles can not be deleted.",
+		)
+	}
+
+	err = r.app.Repositories.Role.Delete(role.Name)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			return echo.ErrNotFound
+		case errors.Is(err, repository.ErrRoleInUse):
+			return echo.NewHTTPError(http.StatusConflict, err)
+		default:
+			return util.ErrInternalServer(ctx, err)
+		}
+	}
+	recordAudit(ctx, r.app, audit.ACTION_ROLE_DELETE, audit.ENTITY_ROLE, role.Name, role, nil)
+
+	return ctx.JSON(http.StatusOK, echo.Map{})
+}
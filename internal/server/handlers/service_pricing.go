@@ -1,7 +1,7 @@
 package handlers
 
 import (
-	// "errors"
+	"errors"
 	"net/http"
 
 	// "github.com/google/uuid"
@@ -9,8 +9,9 @@ import (
 	// "golang.org/x/sync/errgroup"
 
 	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/audit"
 	"github.com/lokatalent/backend_go/internal/models"
-	// "github.com/lokatalent/backend_go/internal/repository"
+	"github.com/lokatalent/backend_go/internal/repository"
 )
 
 type ServicePricingHandler struct {
@@ -23,21 +24,10 @@ func NewServicePricingHandler(app *util.Application) ServicePricingHandler {
 
 func (s ServicePricingHandler) CreateServicePricing(ctx echo.Context) error {
 	reqData := struct {
-		ServiceType string  `json:"service_type" validate:"required"`
-		RatePerHour float64 `json:"rate_per_hour" validate:"required,gte=1"`
+		ServiceType string       `json:"service_type" validate:"required"`
+		RatePerHour models.Money `json:"rate_per_hour" validate:"required,gte=100"`
 	}{}
 
-	authenticatedUser := util.ContextGetUser(ctx)
-	authUser, err := s.app.Repositories.User.GetByID(authenticatedUser.ID)
-	if err != nil || (authUser.Role != models.USER_ADMIN_SUPER) {
-		if err == nil {
-			return echo.NewHTTPError(
-				http.StatusUnauthorized,
-				"only super admin can create service price")
-		}
-		return util.ErrInternalServer(ctx, err)
-	}
-
 	if err := ctx.Bind(&reqData); err != nil {
 		return echo.ErrBadRequest
 	}
@@ -55,10 +45,15 @@ func (s ServicePricingHandler) CreateServicePricing(ctx echo.Context) error {
 		ServiceType: reqData.ServiceType,
 		RatePerHour: reqData.RatePerHour,
 	}
-	err = s.app.Repositories.ServicePricing.CreateServicePricing(&servicePricing)
+	err := s.app.Repositories.ServicePricing.CreateServicePricing(&servicePricing)
 	if err != nil {
 		return util.ErrInternalServer(ctx, err)
 	}
+	recordAudit(
+		ctx, s.app,
+		audit.ACTION_PRICING_CREATE, audit.ENTITY_PRICING, servicePricing.ServiceType,
+		nil, servicePricing,
+	)
 
 	return ctx.JSON(http.StatusOK, servicePricing)
 }
@@ -91,21 +86,10 @@ func (s ServicePricingHandler) GetAllServicesPricing(ctx echo.Context) error {
 
 func (s ServicePricingHandler) UpdateServicePricing(ctx echo.Context) error {
 	reqData := struct {
-		ServiceType string  `json:"service_type" validate:"required"`
-		RatePerHour float64 `json:"rate_per_hour" validate:"required,gte=1"`
+		ServiceType string       `json:"service_type" validate:"required"`
+		RatePerHour models.Money `json:"rate_per_hour" validate:"required,gte=100"`
 	}{}
 
-	authenticatedUser := util.ContextGetUser(ctx)
-	authUser, err := s.app.Repositories.User.GetByID(authenticatedUser.ID)
-	if err != nil || (authUser.Role != models.USER_ADMIN_SUPER) {
-		if err == nil {
-			return echo.NewHTTPError(
-				http.StatusUnauthorized,
-				"only super admin can update service price")
-		}
-		return util.ErrInternalServer(ctx, err)
-	}
-
 	if err := ctx.Bind(&reqData); err != nil {
 		return echo.ErrBadRequest
 	}
@@ -119,6 +103,14 @@ func (s ServicePricingHandler) UpdateServicePricing(ctx echo.Context) error {
 		)
 	}
 
+	previous, err := s.app.Repositories.ServicePricing.GetServicePricing(reqData.ServiceType)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+
 	servicePricing := models.ServicePricing{
 		ServiceType: reqData.ServiceType,
 		RatePerHour: reqData.RatePerHour,
@@ -127,6 +119,11 @@ func (s ServicePricingHandler) UpdateServicePricing(ctx echo.Context) error {
 	if err != nil {
 		return util.ErrInternalServer(ctx, err)
 	}
+	recordAudit(
+		ctx, s.app,
+		audit.ACTION_PRICING_UPDATE, audit.ENTITY_PRICING, servicePricing.ServiceType,
+		previous, servicePricing,
+	)
 
 	return ctx.JSON(http.StatusOK, servicePricing)
 }
@@ -140,13 +137,10 @@ func (s ServicePricingHandler) DeleteServicePricing(ctx echo.Context) error {
 		)
 	}
 
-	authenticatedUser := util.ContextGetUser(ctx)
-	authUser, err := s.app.Repositories.User.GetByID(authenticatedUser.ID)
-	if err != nil || (authUser.Role != models.USER_ADMIN_SUPER) {
-		if err == nil {
-			return echo.NewHTTPError(
-				http.StatusUnauthorized,
-				"only super admin can delete service price")
+	previous, err := s.app.Repositories.ServicePricing.GetServicePricing(serviceType)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
 		}
 		return util.ErrInternalServer(ctx, err)
 	}
@@ -155,6 +149,11 @@ func (s ServicePricingHandler) DeleteServicePricing(ctx echo.Context) error {
 	if err != nil {
 		return util.ErrInternalServer(ctx, err)
 	}
+	recordAudit(
+		ctx, s.app,
+		audit.ACTION_PRICING_DELETE, audit.ENTITY_PRICING, serviceType,
+		previous, nil,
+	)
 
 	return ctx.JSON(http.StatusOK, "successfully deleted.")
 }
@@ -166,17 +165,6 @@ func (s ServicePricingHandler) CreateServiceCommission(ctx echo.Context) error {
 		Percentage int `json:"percentage" validate:"required,gte=1"`
 	}{}
 
-	authenticatedUser := util.ContextGetUser(ctx)
-	authUser, err := s.app.Repositories.User.GetByID(authenticatedUser.ID)
-	if err != nil || (authUser.Role != models.USER_ADMIN_SUPER) {
-		if err == nil {
-			return echo.NewHTTPError(
-				http.StatusUnauthorized,
-				"only super admin can create commission")
-		}
-		return util.ErrInternalServer(ctx, err)
-	}
-
 	if err := ctx.Bind(&reqData); err != nil {
 		return echo.ErrBadRequest
 	}
@@ -187,10 +175,15 @@ func (s ServicePricingHandler) CreateServiceCommission(ctx echo.Context) error {
 	serviceCommission := models.ServiceCommission{
 		Percentage: reqData.Percentage,
 	}
-	err = s.app.Repositories.Commission.CreateServiceCommission(&serviceCommission)
+	err := s.app.Repositories.Commission.CreateServiceCommission(&serviceCommission)
 	if err != nil {
 		return util.ErrInternalServer(ctx, err)
 	}
+	recordAudit(
+		ctx, s.app,
+		audit.ACTION_COMMISSION_CREATE, audit.ENTITY_COMMISSION, serviceCommission.ID,
+		nil, serviceCommission,
+	)
 
 	return ctx.JSON(http.StatusOK, serviceCommission)
 }
@@ -210,17 +203,6 @@ func (s ServicePricingHandler) UpdateServiceCommission(ctx echo.Context) error {
 		Percentage int `json:"percentage" validate:"required,gte=1"`
 	}{}
 
-	authenticatedUser := util.ContextGetUser(ctx)
-	authUser, err := s.app.Repositories.User.GetByID(authenticatedUser.ID)
-	if err != nil || (authUser.Role != models.USER_ADMIN_SUPER) {
-		if err == nil {
-			return echo.NewHTTPError(
-				http.StatusUnauthorized,
-				"only super admin can update commission")
-		}
-		return util.ErrInternalServer(ctx, err)
-	}
-
 	if err := ctx.Bind(&reqData); err != nil {
 		return echo.ErrBadRequest
 	}
@@ -228,6 +210,18 @@ func (s ServicePricingHandler) UpdateServiceCommission(ctx echo.Context) error {
 		return echo.ErrBadRequest
 	}
 
+	// there is a single commission row.
+	previous, err := s.app.Repositories.Commission.GetServiceCommission()
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+	if previous.ID != id {
+		return echo.ErrNotFound
+	}
+
 	serviceCommission := models.ServiceCommission{
 		ID:         id,
 		Percentage: reqData.Percentage,
@@ -236,6 +230,11 @@ func (s ServicePricingHandler) UpdateServiceCommission(ctx echo.Context) error {
 	if err != nil {
 		return util.ErrInternalServer(ctx, err)
 	}
+	recordAudit(
+		ctx, s.app,
+		audit.ACTION_COMMISSION_UPDATE, audit.ENTITY_COMMISSION, serviceCommission.ID,
+		previous, serviceCommission,
+	)
 
 	return ctx.JSON(http.StatusOK, serviceCommission)
 }
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/lokatalent/backend_go/cmd/api/models/response"
+	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/repository"
+)
+
+// maximum length of a session's device label.
+const maxDeviceLength = 100
+
+// newSession signs the user in on a new device, returning the session's
+// first pair of tokens.
+func (a AuthHandler) newSession(ctx echo.Context, user *models.User, device string) (response.TokensResponse, error) {
+	refreshToken, tokenHash, err := util.GenerateToken(a.app.Config.TokenHashKey)
+	if err != nil {
+		return response.TokensResponse{}, err
+	}
+
+	userAgent := ctx.Request().UserAgent()
+	if device == "" {
+		device = userAgent
+	}
+	if len(device) > maxDeviceLength {
+		device = device[:maxDeviceLength]
+	}
+
+	session := models.Session{
+		UserID:    user.ID,
+		Device:    device,
+		UserAgent: userAgent,
+		IPAddress: ctx.RealIP(),
+	}
+	err = a.app.Repositories.Session.Create(&session, &models.RefreshToken{
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(util.RefreshTokenDuration),
+	})
+	if err != nil {
+		return response.TokensResponse{}, err
+	}
+
+	accessToken, expiration, err := util.GenerateAccessToken(a.app, user, session.ID)
+	if err != nil {
+		return response.TokensResponse{}, err
+	}
+
+	return response.TokensResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresAt:    expiration,
+	}, nil
+}
+
+// rotateSession issues a new pair of tokens for an existing session,
+// retiring the refresh token previousID, or all of the session's unused
+// ones if empty.
+func (a AuthHandler) rotateSession(sessionID, previousID string, user *models.User) (response.TokensResponse, error) {
+	refreshToken, tokenHash, err := util.GenerateToken(a.app.Config.TokenHashKey)
+	if err != nil {
+		return response.TokensResponse{}, err
+	}
+
+	err = a.app.Repositories.Session.Rotate(sessionID, previousID, &models.RefreshToken{
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(util.RefreshTokenDuration),
+	})
+	if err != nil {
+		return response.TokensResponse{}, err
+	}
+
+	accessToken, expiration, err := util.GenerateAccessToken(a.app, user, sessionID)
+	if err != nil {
+		return response.TokensResponse{}, err
+	}
+
+	return response.TokensResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresAt:    expiration,
+	}, nil
+}
+
+// reissueTokens issues new tokens for the authenticated session, after
+// the user's details in them have changed.
+func (a AuthHandler) reissueTokens(ctx echo.Context, user *models.User) (response.TokensResponse, error) {
+	sessionID := util.ContextGetSessionID(ctx)
+	if sessionID == "" {
+		return a.newSession(ctx, user, "")
+	}
+	return a.rotateSession(sessionID, "", user)
+}
+
+// Logout revokes the authenticated session.
+func (a AuthHandler) Logout(ctx echo.Context) error {
+	sessionID := util.ContextGetSessionID(ctx)
+	if sessionID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, ErrInvalidToken)
+	}
+
+	authenticatedUser := util.ContextGetUser(ctx)
+	err := a.app.Repositories.Session.Revoke(sessionID, authenticatedUser.ID)
+	if err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, "successfully logged out.")
+}
+
+// GetSessions lists the devices the user is signed in on.
+func (a AuthHandler) GetSessions(ctx echo.Context) error {
+	authenticatedUser := util.ContextGetUser(ctx)
+	sessions, err := a.app.Repositories.Session.GetActive(authenticatedUser.ID)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	currentID := util.ContextGetSessionID(ctx)
+	resp := []response.SessionResponse{}
+	for _, session := range sessions {
+		resp = append(resp, response.SessionResponseFromModel(&session, currentID))
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// RevokeSession signs the user out of one of their sessions.
+func (a AuthHandler) RevokeSession(ctx echo.Context) error {
+	id := ctx.Param("id")
+
+	if !util.IsValidUUID(id) {
+		return echo.ErrBadRequest
+	}
+
+	authenticatedUser := util.ContextGetUser(ctx)
+	err := a.app.Repositories.Session.Revoke(id, authenticatedUser.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, "session successfully revoked.")
+}
+
+// revokeReusedSession revokes a session whose refresh token was reused.
+func (a AuthHandler) revokeReusedSession(ctx echo.Context, session *models.Session) error {
+	err := a.app.Repositories.Session.Revoke(session.ID, session.UserID)
+	if err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return echo.NewHTTPError(http.StatusUnauthorized, ErrRefreshTokenReused)
+}
+
+// JWKS publishes the public keys access tokens are signed with.
+func (a AuthHandler) JWKS(ctx echo.Context) error {
+	ctx.Response().Header().Set(echo.HeaderCacheControl, "public, max-age=300")
+	return ctx.JSON(http.StatusOK, a.app.Config.JWT.Keys.JWKS())
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/repository"
+	"github.com/lokatalent/backend_go/internal/throttle"
+)
+
+const accountLockedTmpl = "account_locked.gotmpl"
+
+// checkThrottle rejects the request if the key is locked out.
+func (a AuthHandler) checkThrottle(ctx echo.Context, key string) error {
+	authThrottle, err := a.app.Repositories.Throttle.Get(key)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	if authThrottle.Locked() {
+		retryAfter := time.Until(*authThrottle.LockedUntil).Seconds()
+		ctx.Response().Header().Set(
+			echo.HeaderRetryAfter,
+			strconv.Itoa(int(retryAfter)+1),
+		)
+		return echo.NewHTTPError(
+			http.StatusTooManyRequests,
+			ErrTooManyAttempts)
+	}
+	return nil
+}
+
+// recordFailure counts a failed attempt for the key, locking it out once
+// the policy's limit is reached. It returns when the lockout ends, or nil
+// if the key was not locked.
+func (a AuthHandler) recordFailure(key string, policy throttle.Policy) (*time.Time, error) {
+	authThrottle, err := a.app.Repositories.Throttle.RecordFailure(key, policy.Window)
+	if err != nil {
+		return nil, err
+	}
+	if !policy.ShouldLock(authThrottle) {
+		return nil, nil
+	}
+
+	until, lockouts := policy.LockUntil(authThrottle, time.Now())
+	err = a.app.Repositories.Throttle.Lock(key, until, lockouts)
+	if err != nil {
+		return nil, err
+	}
+	return &until, nil
+}
+
+// notifyLockout emails the user that their account has been locked.
+func (a AuthHandler) notifyLockout(ctx echo.Context, user *models.User, until time.Time) {
+	if user.Email == "" {
+		return
+	}
+
+	err := a.app.Mailer.Send(
+		user.Email,
+		accountLockedTmpl,
+		struct {
+			FirstName string
+			Until     string
+			IPAddress string
+			Year      int
+		}{
+			FirstName: user.FirstName,
+			Until:     until.UTC().Format("Jan 2, 2006 15:04 MST"),
+			IPAddress: ctx.RealIP(),
+			Year:      time.Now().Year(),
+		},
+	)
+	if err != nil {
+		_ = util.ErrInternalServer(ctx, err)
+	}
+}
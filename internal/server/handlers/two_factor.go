@@ -0,0 +1,490 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/lokatalent/backend_go/cmd/api/models/response"
+	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/repository"
+	"github.com/lokatalent/backend_go/internal/throttle"
+	"github.com/lokatalent/backend_go/internal/totp"
+)
+
+const recoveryCodeCount = 10
+
+// signIn completes the password, or third-party, step of sign-in. Users
+// with two-factor authentication, or whose role requires it, get a
+// challenge token for the second step instead of a session.
+func (a AuthHandler) signIn(ctx echo.Context, user *models.User, device string) error {
+	principal, err := util.LoadPrincipal(a.app, *user)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	enabled := false
+	twoFactor, err := a.app.Repositories.TwoFactor.Get(user.ID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrRecordNotFound) {
+			return util.ErrInternalServer(ctx, err)
+		}
+	} else {
+		enabled = twoFactor.Enabled
+	}
+
+	if enabled || principal.RequiresTwoFactor {
+		challengeToken, expiration, err := util.GenerateChallengeToken(a.app, user, device)
+		if err != nil {
+			return util.ErrInternalServer(ctx, err)
+		}
+		return ctx.JSON(http.StatusOK, response.TwoFactorChallengeResponse{
+			TwoFactorRequired:  true,
+			EnrollmentRequired: !enabled,
+			ChallengeToken:     challengeToken,
+			ExpiresAt:          expiration,
+		})
+	}
+
+	// start a new session with access and refresh tokens
+	tokens, err := a.newSession(ctx, user, device)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	resp := response.AuthResponse{
+		TokensResponse: tokens,
+		UserResponse:   response.UserResponseFromModel(user),
+	}
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// TwoFactorChallenge completes sign-in with a code from the user's
+// authenticator app, or one of their recovery codes.
+func (a AuthHandler) TwoFactorChallenge(ctx echo.Context) error {
+	reqData := struct {
+		ChallengeToken string `json:"challenge_token" validate:"required"`
+		Code           string `json:"code"`
+		RecoveryCode   string `json:"recovery_code"`
+	}{}
+
+	if err := ctx.Bind(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	if err := ctx.Validate(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	if reqData.Code == "" && reqData.RecoveryCode == "" {
+		return echo.NewHTTPError(
+			http.StatusBadRequest,
+			"code or recovery_code is required!",
+		)
+	}
+
+	claims, err := util.ValidateChallengeToken(a.app, reqData.ChallengeToken)
+	if err != nil {
+		return echo.NewHTTPError(
+			http.StatusUnauthorized,
+			ErrInvalidToken)
+	}
+	user, err := a.app.Repositories.User.GetByID(claims.Subject)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	accountKey := throttle.AccountKey(user.ID)
+	if err := a.checkThrottle(ctx, accountKey); err != nil {
+		return err
+	}
+
+	twoFactor, err := a.app.Repositories.TwoFactor.Get(user.ID)
+	if err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
+		return util.ErrInternalServer(ctx, err)
+	}
+	if !twoFactor.Enabled {
+		return echo.NewHTTPError(
+			http.StatusForbidden,
+			ErrTwoFactorNotEnabled)
+	}
+
+	if reqData.Code != "" {
+		err = a.useTOTPCode(&twoFactor, reqData.Code)
+	} else {
+		err = a.useRecoveryCode(user.ID, reqData.RecoveryCode)
+	}
+	if err != nil {
+		return a.twoFactorFailure(ctx, &user, err)
+	}
+
+	tokens, err := a.newSession(ctx, &user, claims.Device)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	resp := response.AuthResponse{
+		TokensResponse: tokens,
+		UserResponse:   response.UserResponseFromModel(&user),
+	}
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// EnrollTwoFactor generates a new TOTP secret for the user, to be
+// confirmed with EnableTwoFactor. Users who must enroll before they can
+// sign in authenticate with their challenge token.
+func (a AuthHandler) EnrollTwoFactor(ctx echo.Context) error {
+	reqData := struct {
+		ChallengeToken string `json:"challenge_token"`
+	}{}
+
+	if err := ctx.Bind(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	user, _, err := a.twoFactorUser(ctx, reqData.ChallengeToken)
+	if err != nil {
+		return err
+	}
+	principal, err := util.LoadPrincipal(a.app, user)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+	if !util.CanEnrollTwoFactor(principal) {
+		return echo.NewHTTPError(
+			http.StatusForbidden,
+			"two-factor authentication is only available to staff and service providers.",
+		)
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+	err = a.app.Repositories.TwoFactor.Enroll(&models.TwoFactor{
+		UserID: user.ID,
+		Secret: secret,
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrTwoFactorEnabled) {
+			return echo.NewHTTPError(
+				http.StatusForbidden,
+				repository.ErrTwoFactorEnabled)
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	account := user.Email
+	if account == "" {
+		account = user.PhoneNum
+	}
+	return ctx.JSON(http.StatusOK, response.TwoFactorEnrollmentResponse{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(util.TOTPIssuer, account, secret),
+	})
+}
+
+// EnableTwoFactor confirms an enrollment with a code from the user's
+// authenticator app, returning their recovery codes. Users enrolling from
+// sign-in also get a new session.
+func (a AuthHandler) EnableTwoFactor(ctx echo.Context) error {
+	reqData := struct {
+		ChallengeToken string `json:"challenge_token"`
+		Code           string `json:"code" validate:"required"`
+	}{}
+
+	if err := ctx.Bind(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	if err := ctx.Validate(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	user, claims, err := a.twoFactorUser(ctx, reqData.ChallengeToken)
+	if err != nil {
+		return err
+	}
+
+	accountKey := throttle.AccountKey(user.ID)
+	if err := a.checkThrottle(ctx, accountKey); err != nil {
+		return err
+	}
+
+	twoFactor, err := a.app.Repositories.TwoFactor.Get(user.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.NewHTTPError(
+				http.StatusFailedDependency,
+				"two-factor authentication has not been enrolled.",
+			)
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+	if twoFactor.Enabled {
+		return echo.NewHTTPError(
+			http.StatusForbidden,
+			repository.ErrTwoFactorEnabled)
+	}
+
+	step, ok := totp.Validate(twoFactor.Secret, reqData.Code, time.Now(), twoFactor.LastUsedStep)
+	if !ok {
+		return a.twoFactorFailure(ctx, &user, ErrInvalidTwoFactorCode)
+	}
+
+	codes, hashes, err := a.generateRecoveryCodes()
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+	err = a.app.Repositories.TwoFactor.Enable(user.ID, step, hashes)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.NewHTTPError(
+				http.StatusConflict,
+				"two-factor enrollment has changed, try again.",
+			)
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	resp := response.RecoveryCodesResponse{RecoveryCodes: codes}
+	if claims != nil {
+		tokens, err := a.newSession(ctx, &user, claims.Device)
+		if err != nil {
+			return util.ErrInternalServer(ctx, err)
+		}
+		resp.Tokens = &tokens
+	}
+
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// DisableTwoFactor removes the user's two-factor authentication. Users
+// whose role requires it can not disable it.
+func (a AuthHandler) DisableTwoFactor(ctx echo.Context) error {
+	reqData := struct {
+		Password string `json:"password" validate:"required"`
+		Code     string `json:"code" validate:"required"`
+	}{}
+
+	if err := ctx.Bind(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	if err := ctx.Validate(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	principal, err := util.ContextGetPrincipal(ctx, a.app)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+	if principal.RequiresTwoFactor {
+		return echo.NewHTTPError(
+			http.StatusForbidden,
+			"two-factor authentication is required for your role.",
+		)
+	}
+	user := principal.User
+
+	err = util.ValidatePassword(reqData.Password, user.Password)
+	if err != nil {
+		return echo.NewHTTPError(
+			http.StatusUnauthorized,
+			ErrInvalidPassword)
+	}
+
+	twoFactor, err := a.enabledTwoFactor(ctx, &user)
+	if err != nil {
+		return err
+	}
+	if err := a.useTOTPCode(&twoFactor, reqData.Code); err != nil {
+		return a.twoFactorFailure(ctx, &user, err)
+	}
+
+	err = a.app.Repositories.TwoFactor.Delete(user.ID)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, "two-factor authentication disabled.")
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes.
+func (a AuthHandler) RegenerateRecoveryCodes(ctx echo.Context) error {
+	reqData := struct {
+		Code string `json:"code" validate:"required"`
+	}{}
+
+	if err := ctx.Bind(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	if err := ctx.Validate(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	authenticatedUser := util.ContextGetUser(ctx)
+	user, err := a.app.Repositories.User.GetByID(authenticatedUser.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	twoFactor, err := a.enabledTwoFactor(ctx, &user)
+	if err != nil {
+		return err
+	}
+	if err := a.useTOTPCode(&twoFactor, reqData.Code); err != nil {
+		return a.twoFactorFailure(ctx, &user, err)
+	}
+
+	codes, hashes, err := a.generateRecoveryCodes()
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+	err = a.app.Repositories.TwoFactor.ReplaceRecoveryCodes(user.ID, hashes)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, response.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// twoFactorUser returns the user enrolling two-factor authentication,
+// identified by their access token or, during sign-in, their challenge
+// token. The challenge claims are returned in the latter case.
+func (a AuthHandler) twoFactorUser(ctx echo.Context, challengeToken string) (models.User, *util.CustomChallengeJWTClaims, error) {
+	var claims *util.CustomChallengeJWTClaims
+	userID := util.ContextGetUser(ctx).ID
+	if userID == "" {
+		if challengeToken == "" {
+			return models.User{}, nil, echo.ErrUnauthorized
+		}
+
+		var err error
+		claims, err = util.ValidateChallengeToken(a.app, challengeToken)
+		if err != nil {
+			return models.User{}, nil, echo.NewHTTPError(
+				http.StatusUnauthorized,
+				ErrInvalidToken)
+		}
+		userID = claims.Subject
+	}
+
+	user, err := a.app.Repositories.User.GetByID(userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return models.User{}, nil, echo.ErrNotFound
+		}
+		return models.User{}, nil, util.ErrInternalServer(ctx, err)
+	}
+
+	return user, claims, nil
+}
+
+// enabledTwoFactor returns the user's two-factor authentication, failing
+// if it is not enabled.
+func (a AuthHandler) enabledTwoFactor(ctx echo.Context, user *models.User) (models.TwoFactor, error) {
+	if err := a.checkThrottle(ctx, throttle.AccountKey(user.ID)); err != nil {
+		return models.TwoFactor{}, err
+	}
+
+	twoFactor, err := a.app.Repositories.TwoFactor.Get(user.ID)
+	if err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
+		return models.TwoFactor{}, util.ErrInternalServer(ctx, err)
+	}
+	if !twoFactor.Enabled {
+		return models.TwoFactor{}, echo.NewHTTPError(
+			http.StatusForbidden,
+			ErrTwoFactorNotEnabled)
+	}
+
+	return twoFactor, nil
+}
+
+// useTOTPCode validates a code from the user's authenticator app, so that
+// it can not be used again.
+func (a AuthHandler) useTOTPCode(twoFactor *models.TwoFactor, code string) error {
+	step, ok := totp.Validate(twoFactor.Secret, code, time.Now(), twoFactor.LastUsedStep)
+	if !ok {
+		return ErrInvalidTwoFactorCode
+	}
+
+	err := a.app.Repositories.TwoFactor.UseStep(twoFactor.UserID, step)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return ErrInvalidTwoFactorCode
+		}
+		return err
+	}
+	return nil
+}
+
+func (a AuthHandler) useRecoveryCode(userID, code string) error {
+	err := a.app.Repositories.TwoFactor.UseRecoveryCode(
+		userID,
+		util.HashToken(a.app.Config.TokenHashKey, normalizeRecoveryCode(code)),
+	)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return ErrInvalidTwoFactorCode
+		}
+		return err
+	}
+	return nil
+}
+
+// twoFactorFailure counts an invalid code towards the account's lockout.
+func (a AuthHandler) twoFactorFailure(ctx echo.Context, user *models.User, err error) error {
+	if !errors.Is(err, ErrInvalidTwoFactorCode) {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	lockedUntil, err := a.recordFailure(throttle.AccountKey(user.ID), throttle.Account)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+	if lockedUntil != nil {
+		a.notifyLockout(ctx, user, *lockedUntil)
+	}
+
+	return echo.NewHTTPError(
+		http.StatusUnauthorized,
+		ErrInvalidTwoFactorCode)
+}
+
+// generateRecoveryCodes returns a new set of recovery codes, formatted as
+// xxxxx-xxxxx, along with the hashes they are stored as.
+func (a AuthHandler) generateRecoveryCodes() ([]string, []string, error) {
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		buf := make([]byte, 7)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, err
+		}
+		code := strings.ToLower(encoding.EncodeToString(buf))[:10]
+
+		codes = append(codes, code[:5]+"-"+code[5:])
+		hashes = append(hashes, util.HashToken(a.app.Config.TokenHashKey, code))
+	}
+
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.ReplaceAll(code, "-", "")
+}
@@ -15,7 +15,10 @@ import (
 
 	"github.com/lokatalent/backend_go/cmd/api/models/response"
 	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/audit"
+	"github.com/lokatalent/backend_go/internal/gateway"
 	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/rbac"
 	"github.com/lokatalent/backend_go/internal/repository"
 )
 
@@ -61,10 +64,9 @@ func (u UserHandler) ListUsers(ctx echo.Context) error {
 		return util.ErrInternalServer(ctx, err)
 	}
 
-	// restrict full user detail to admin
-	authenticatedUser := util.ContextGetUser(ctx)
-	authUser, err := u.app.Repositories.User.GetByID(authenticatedUser.ID)
-	if err == nil && util.IsAdmin(authUser.Role) {
+	// restrict full user detail to staff who read any user
+	principal, err := util.ContextGetPrincipal(ctx, u.app)
+	if err == nil && principal.Can(rbac.USERS_READ_ANY) {
 		resp := []response.UserResponse{}
 		for _, user := range users {
 			resp = append(resp, response.UserResponseFromModel(&user))
@@ -122,10 +124,9 @@ func (u UserHandler) Search(ctx echo.Context) error {
 		return util.ErrInternalServer(ctx, err)
 	}
 
-	// restrict full user detail to admin
-	authenticatedUser := util.ContextGetUser(ctx)
-	authUser, err := u.app.Repositories.User.GetByID(authenticatedUser.ID)
-	if err == nil && util.IsAdmin(authUser.Role) {
+	// restrict full user detail to staff who read any user
+	principal, err := util.ContextGetPrincipal(ctx, u.app)
+	if err == nil && principal.Can(rbac.USERS_READ_ANY) {
 		resp := []response.UserResponse{}
 		for _, user := range users {
 			resp = append(resp, response.UserResponseFromModel(&user))
@@ -151,21 +152,11 @@ func (u UserHandler) GetOwnProfile(ctx echo.Context) error {
 	}
 
 	resp := response.UserResponseFromModel(&user)
-	return ctx.JSON(http.StatusOK, resp)
-}
-
-func (u UserHandler) GetOwnEducationProfile(ctx echo.Context) error {
-	authenticatedUser := util.ContextGetUser(ctx)
-
-	eduInfo, err := u.app.Repositories.User.GetEducationInfo(authenticatedUser.ID)
+	resp.RatingSummary, err = u.providerRating(&user)
 	if err != nil {
-		if errors.Is(err, repository.ErrRecordNotFound) {
-			return echo.ErrNotFound
-		}
 		return util.ErrInternalServer(ctx, err)
 	}
-
-	return ctx.JSON(http.StatusOK, eduInfo)
+	return ctx.JSON(http.StatusOK, resp)
 }
 
 func (u UserHandler) GetOwnBankProfile(ctx echo.Context) error {
@@ -197,33 +188,35 @@ func (u UserHandler) GetProfile(ctx echo.Context) error {
 		return util.ErrInternalServer(ctx, err)
 	}
 
-	// restrict full detail to admin
-	authenticatedUser := util.ContextGetUser(ctx)
-	authUser, err := u.app.Repositories.User.GetByID(authenticatedUser.ID)
-	if err == nil && util.IsAdmin(authUser.Role) {
+	// restrict full detail to staff who read any user
+	principal, err := util.ContextGetPrincipal(ctx, u.app)
+	canReadAny := err == nil && principal.Can(rbac.USERS_READ_ANY)
+
+	rating, err := u.providerRating(&user)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+	if canReadAny {
 		resp := response.UserResponseFromModel(&user)
+		resp.RatingSummary = rating
 		return ctx.JSON(http.StatusOK, resp)
 	}
 	resp := response.PublicUserResponseFromModel(&user)
+	resp.RatingSummary = rating
 	return ctx.JSON(http.StatusOK, resp)
 }
 
-func (u UserHandler) GetEducationProfile(ctx echo.Context) error {
-	id := ctx.Param("id")
-
-	if !util.IsValidUUID(id) {
-		return echo.ErrBadRequest
+// providerRating returns the rating of user as a service provider, or nil
+// if they do not provide services.
+func (u UserHandler) providerRating(user *models.User) (*models.RatingSummary, error) {
+	if user.ServiceRole == models.SERVICE_REQUESTER {
+		return nil, nil
 	}
-
-	eduInfo, err := u.app.Repositories.User.GetEducationInfo(id)
+	summary, err := u.app.Repositories.Review.GetSummary(user.ID)
 	if err != nil {
-		if errors.Is(err, repository.ErrRecordNotFound) {
-			return echo.ErrNotFound
-		}
-		return util.ErrInternalServer(ctx, err)
+		return nil, err
 	}
-
-	return ctx.JSON(http.StatusOK, eduInfo)
+	return &summary, nil
 }
 
 func (u UserHandler) ChangeRole(ctx echo.Context) error {
@@ -235,19 +228,20 @@ func (u UserHandler) ChangeRole(ctx echo.Context) error {
 			http.StatusBadRequest,
 			"invalid user id!")
 	}
-	if (newRole != models.USER_REGULAR) && !util.IsAdmin(newRole) {
-		return echo.NewHTTPError(
-			http.StatusBadRequest,
-			"invalid role. expected one of regular|admin|admin_super")
-	}
 
-	authenticatedUser := util.ContextGetUser(ctx)
-	authUser, err := u.app.Repositories.User.GetByID(authenticatedUser.ID)
-	if err != nil || (authUser.Role != models.USER_ADMIN_SUPER) {
-		if err == nil {
+	_, err := u.app.Repositories.Role.Get(newRole)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
 			return echo.NewHTTPError(
-				http.StatusUnauthorized,
-				"only super admin can change role")
+				http.StatusBadRequest,
+				ErrInvalidRole)
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+	user, err := u.app.Repositories.User.GetByID(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
 		}
 		return util.ErrInternalServer(ctx, err)
 	}
@@ -256,6 +250,11 @@ func (u UserHandler) ChangeRole(ctx echo.Context) error {
 	if err != nil {
 		return util.ErrInternalServer(ctx, err)
 	}
+	recordAudit(
+		ctx, u.app,
+		audit.ACTION_USER_ROLE_CHANGE, audit.ENTITY_USER, id,
+		echo.Map{"role": user.Role}, echo.Map{"role": newRole},
+	)
 
 	return ctx.JSON(http.StatusOK, echo.Map{})
 }
@@ -393,95 +392,6 @@ func (u UserHandler) UpdateProfilePersonal(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, response.UserResponseFromModel(&existingUser))
 }
 
-func (u UserHandler) UpdateProfileEducation(ctx echo.Context) error {
-	isNewInfo := false
-
-	reqData := struct {
-		Institute  string `json:"institute"`
-		Degree     string `json:"degree"`
-		Discipline string `json:"discipline"`
-		Start      string `json:"start"`
-		Finish     string `json:"finish"`
-	}{}
-
-	if err := ctx.Bind(&reqData); err != nil {
-		return echo.ErrBadRequest
-	}
-
-	var parsedStartDate, parsedEndDate time.Time
-	var err error
-
-	if reqData.Start != "" {
-		parsedStartDate, err = util.ParseDate(reqData.Start)
-		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
-		}
-	}
-
-	if reqData.Finish != "" {
-		parsedEndDate, err = util.ParseDate(reqData.Finish)
-		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
-		}
-	}
-
-	if parsedStartDate.IsZero() || !parsedStartDate.Before(parsedEndDate) {
-		return echo.NewHTTPError(
-			http.StatusBadRequest,
-			"invalid start and finish date.",
-		)
-	}
-
-	user := util.ContextGetUser(ctx)
-	existingUser, err := u.app.Repositories.User.GetByID(user.ID)
-	if err != nil {
-		return util.ErrInternalServer(ctx, err)
-	}
-
-	existingEduInfo, err := u.app.Repositories.User.GetEducationInfo(existingUser.ID)
-	if err != nil {
-		if !errors.Is(err, repository.ErrRecordNotFound) {
-			return util.ErrInternalServer(ctx, err)
-		}
-		isNewInfo = true
-	}
-
-	if len(existingEduInfo.Institute) == 0 || len(reqData.Institute) > 1 {
-		existingEduInfo.Institute = reqData.Institute
-	}
-
-	if len(existingEduInfo.Degree) == 0 || len(reqData.Degree) > 1 {
-		existingEduInfo.Degree = reqData.Degree
-	}
-
-	if len(existingEduInfo.Discipline) == 0 || len(reqData.Discipline) > 1 {
-		existingEduInfo.Discipline = reqData.Discipline
-	}
-
-	if existingEduInfo.Start.IsZero() || !parsedStartDate.IsZero() {
-		existingEduInfo.Start = parsedStartDate
-	}
-
-	if existingEduInfo.Finish.IsZero() || !parsedEndDate.IsZero() {
-		existingEduInfo.Finish = parsedEndDate
-	}
-
-	if isNewInfo {
-		existingEduInfo.UserID = existingUser.ID
-		err = u.app.Repositories.User.CreateEducationInfo(&existingEduInfo)
-		if err != nil {
-			return util.ErrInternalServer(ctx, err)
-		}
-	} else {
-		err = u.app.Repositories.User.UpdateEducationInfo(&existingEduInfo)
-		if err != nil {
-			return util.ErrInternalServer(ctx, err)
-		}
-	}
-
-	return ctx.JSON(http.StatusOK, existingEduInfo)
-}
-
 func (u UserHandler) UpdateProfileBank(ctx echo.Context) error {
 	isNewInfo := false
 
@@ -531,10 +441,9 @@ func (u UserHandler) UpdateProfileBank(ctx echo.Context) error {
 		existingBankInfo.BankCode = reqData.BankCode
 	}
 
-	existingBankInfo.AccountName, err = resolveAccountNumber(
+	existingBankInfo.AccountName, err = u.app.PaymentGateway.ResolveAccount(
 		existingBankInfo.AccountNum,
 		existingBankInfo.BankCode,
-		u.app.Config.Paystack.APIKey,
 	)
 	if err != nil {
 		return util.ErrInternalServer(ctx, err)
@@ -545,12 +454,11 @@ func (u UserHandler) UpdateProfileBank(ctx echo.Context) error {
 	recipientCode, err := u.app.Repositories.Payment.GetRecipientCode(existingUser.ID)
 	if err != nil {
 		if errors.Is(err, repository.ErrRecordNotFound) {
-			recipientCode, err = createTransferRecipient(
-				existingBankInfo.AccountName,
-				existingBankInfo.AccountNum,
-				existingBankInfo.BankCode,
-				u.app.Config.Paystack.APIKey,
-			)
+			recipientCode, err = u.app.PaymentGateway.CreateRecipient(gateway.BankAccount{
+				AccountName: existingBankInfo.AccountName,
+				AccountNum:  existingBankInfo.AccountNum,
+				BankCode:    existingBankInfo.BankCode,
+			})
 			if err != nil {
 				return util.ErrInternalServer(ctx, err)
 			}
@@ -567,18 +475,17 @@ func (u UserHandler) UpdateProfileBank(ctx echo.Context) error {
 			return util.ErrInternalServer(ctx, err)
 		}
 	} else {
-		err = deleteRecipient(recipientCode, u.app.Config.Paystack.APIKey)
+		err = u.app.PaymentGateway.DeleteRecipient(recipientCode)
 		if err != nil {
 			if errors.Is(err, errors.New(http.StatusText(http.StatusNotFound))) {
 				return util.ErrInternalServer(ctx, err)
 			}
 		}
-		recipientCode, err = createTransferRecipient(
-			existingBankInfo.AccountName,
-			existingBankInfo.AccountNum,
-			existingBankInfo.BankCode,
-			u.app.Config.Paystack.APIKey,
-		)
+		recipientCode, err = u.app.PaymentGateway.CreateRecipient(gateway.BankAccount{
+			AccountName: existingBankInfo.AccountName,
+			AccountNum:  existingBankInfo.AccountNum,
+			BankCode:    existingBankInfo.BankCode,
+		})
 		if err != nil {
 			return util.ErrInternalServer(ctx, err)
 		}
@@ -677,10 +584,26 @@ func (u UserHandler) DeleteProfileImage(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, "image successfully deleted")
 }
 
+// GetCertifications returns a user's certifications. Other users only see
+// approved certifications that have not expired, unless they review
+// certifications.
 func (u UserHandler) GetCertifications(ctx echo.Context) error {
 	id := ctx.Param("id")
 
-	certs, err := u.app.Repositories.User.GetCertifications(id)
+	principal, err := util.ContextGetPrincipal(ctx, u.app)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrUnauthorized
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	var certs []models.UserCertification
+	if principal.ID == id || principal.Can(rbac.CERTIFICATIONS_REVIEW) {
+		certs, err = u.app.Repositories.User.GetCertifications(id)
+	} else {
+		certs, err = u.app.Repositories.User.GetApprovedCertifications(id)
+	}
 	if err != nil {
 		if errors.Is(err, repository.ErrRecordNotFound) {
 			return echo.NewHTTPError(
@@ -715,7 +638,13 @@ func (u UserHandler) UploadCertifications(ctx echo.Context) error {
 		return util.ErrInternalServer(ctx, err)
 	}
 
-	for _, image := range form.File["images"] {
+	for i, image := range form.File["images"] {
+		newCert, err := certificationFromForm(form, i)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		newCert.UserID = authenticatedUser.ID
+
 		contentType, err := util.ValidateContentType(
 			image.Header,
 			util.ContentTypeJPEG,
@@ -742,10 +671,6 @@ func (u UserHandler) UploadCertifications(ctx echo.Context) error {
 			sem.Acquire()
 			defer sem.Release()
 
-			newCert := models.UserCertification{
-				ID:     uuid.NewString(),
-				UserID: authenticatedUser.ID,
-			}
 			imgURL, err := u.app.Repositories.Storage.UploadFile(
 				src,
 				newCert.CertificationPath(),
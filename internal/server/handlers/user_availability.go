@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/availability"
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/repository"
+)
+
+// default window of exceptions listed when none is requested.
+const AVAILABILITY_EXCEPTIONS_WINDOW = 90 * 24 * time.Hour
+
+func (u UserHandler) UpdateServiceAvailability(ctx echo.Context) error {
+	reqData := struct {
+		ServiceType  string              `json:"service_type" validate:"required"`
+		Availability models.Availability `json:"availability" validate:"required"`
+	}{}
+
+	if err := ctx.Bind(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	if err := ctx.Validate(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	if !util.IsValidServiceType(reqData.ServiceType) {
+		return echo.NewHTTPError(http.StatusBadRequest, ErrInvalidServiceType)
+	}
+	if err := reqData.Availability.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	reqData.Availability.Normalize()
+
+	authenticatedUser := util.ContextGetUser(ctx)
+	service, err := u.app.Repositories.User.UpdateServiceAvailability(
+		authenticatedUser.ID,
+		reqData.ServiceType,
+		reqData.Availability,
+	)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, service)
+}
+
+// GetAvailabilityExceptions lists a provider's exceptions between the
+// from and to query parameters, by default the next 90 days.
+func (u UserHandler) GetAvailabilityExceptions(ctx echo.Context) error {
+	id := ctx.Param("id")
+
+	if !util.IsValidUUID(id) {
+		return echo.ErrBadRequest
+	}
+
+	from := time.Now()
+	if ctx.QueryParam("from") != "" {
+		parsed, err := time.Parse(time.RFC3339, ctx.QueryParam("from"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid from value")
+		}
+		from = parsed
+	}
+	to := from.Add(AVAILABILITY_EXCEPTIONS_WINDOW)
+	if ctx.QueryParam("to") != "" {
+		parsed, err := time.Parse(time.RFC3339, ctx.QueryParam("to"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid to value")
+		}
+		to = parsed
+	}
+
+	exceptions, err := u.app.Repositories.User.GetAvailabilityExceptions(id, from, to)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, exceptions)
+}
+
+func (u UserHandler) CreateAvailabilityException(ctx echo.Context) error {
+	reqData := struct {
+		Kind     string    `json:"kind" validate:"required"`
+		StartsAt time.Time `json:"starts_at" validate:"required"`
+		EndsAt   time.Time `json:"ends_at" validate:"required"`
+		Reason   string    `json:"reason" validate:"max=500"`
+	}{}
+
+	if err := ctx.Bind(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	if err := ctx.Validate(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	authenticatedUser := util.ContextGetUser(ctx)
+	authUser, err := u.app.Repositories.User.GetByID(authenticatedUser.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+	if authUser.ServiceRole == models.SERVICE_REQUESTER {
+		return echo.NewHTTPError(
+			http.StatusForbidden,
+			"service requester can not set availability!",
+		)
+	}
+
+	exception := models.AvailabilityException{
+		UserID:   authUser.ID,
+		Kind:     reqData.Kind,
+		StartsAt: reqData.StartsAt,
+		EndsAt:   reqData.EndsAt,
+		Reason:   reqData.Reason,
+	}
+	if err := exception.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	err = u.app.Repositories.User.CreateAvailabilityException(&exception)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusCreated, exception)
+}
+
+func (u UserHandler) UpdateAvailabilityException(ctx echo.Context) error {
+	reqData := struct {
+		Kind     string     `json:"kind"`
+		StartsAt *time.Time `json:"starts_at"`
+		EndsAt   *time.Time `json:"ends_at"`
+		Reason   *string    `json:"reason" validate:"omitempty,max=500"`
+	}{}
+
+	if err := ctx.Bind(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+	if err := ctx.Validate(&reqData); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	}
+
+	authenticatedUser := util.ContextGetUser(ctx)
+	exception, err := u.app.Repositories.User.GetAvailabilityException(
+		ctx.Param("id"),
+		authenticatedUser.ID,
+	)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	if reqData.Kind != "" {
+		exception.Kind = reqData.Kind
+	}
+	if reqData.StartsAt != nil {
+		exception.StartsAt = *reqData.StartsAt
+	}
+	if reqData.EndsAt != nil {
+		exception.EndsAt = *reqData.EndsAt
+	}
+	if reqData.Reason != nil {
+		exception.Reason = *reqData.Reason
+	}
+	if err := exception.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	err = u.app.Repositories.User.UpdateAvailabilityException(&exception)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, exception)
+}
+
+func (u UserHandler) DeleteAvailabilityException(ctx echo.Context) error {
+	authenticatedUser := util.ContextGetUser(ctx)
+
+	err := u.app.Repositories.User.DeleteAvailabilityException(
+		ctx.Param("id"),
+		authenticatedUser.ID,
+	)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return echo.ErrNotFound
+		}
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, "availability exception successfully deleted.")
+}
+
+// CheckAvailability reports whether a provider is free between the start
+// and end query parameters: their schedule for service_type, or any of
+// their services if omitted, covers the period and no accepted or
+// in-progress booking overlaps it.
+func (u UserHandler) CheckAvailability(ctx echo.Context) error {
+	id := ctx.Param("id")
+	serviceType := ctx.QueryParam("service_type")
+
+	if !util.IsValidUUID(id) {
+		return echo.ErrBadRequest
+	}
+	if serviceType != "" && !util.IsValidServiceType(serviceType) {
+		return echo.NewHTTPError(http.StatusBadRequest, ErrInvalidServiceType)
+	}
+	start, err := time.Parse(time.RFC3339, ctx.QueryParam("start"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid start value")
+	}
+	end, err := time.Parse(time.RFC3339, ctx.QueryParam("end"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid end value")
+	}
+	if !end.After(start) {
+		return echo.NewHTTPError(http.StatusBadRequest, "end must be after start")
+	}
+
+	services := []models.UserService{}
+	if serviceType != "" {
+		service, err := u.app.Repositories.User.GetService(id, serviceType)
+		if err != nil {
+			if errors.Is(err, repository.ErrRecordNotFound) {
+				return echo.ErrNotFound
+			}
+			return util.ErrInternalServer(ctx, err)
+		}
+		services = append(services, service)
+	} else {
+		services, err = u.app.Repositories.User.GetAllServices(id)
+		if err != nil {
+			return util.ErrInternalServer(ctx, err)
+		}
+	}
+
+	exceptions, err := u.app.Repositories.User.GetAvailabilityExceptions(id, start, end)
+	if err != nil {
+		return util.ErrInternalServer(ctx, err)
+	}
+
+	free := false
+	for _, service := range services {
+		covered, err := availability.Covers(service.Availability, exceptions, start, end)
+		if err != nil {
+			return util.ErrInternalServer(ctx, err)
+		}
+		if covered {
+			free = true
+			break
+		}
+	}
+
+	if free {
+		booked, err := u.app.Repositories.Booking.HasOverlapping(id, start, end)
+		if err != nil {
+			return util.ErrInternalServer(ctx, err)
+		}
+		free = !booked
+	}
+
+	return ctx.JSON(http.StatusOK, map[string]any{
+		"free":  free,
+		"start": start,
+		"end":   end,
+	})
+}
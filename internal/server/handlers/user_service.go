@@ -50,7 +50,7 @@ func (u UserHandler) CreateService(ctx echo.Context) error {
 	reqData := struct {
 		ServiceType     string              `json:"service_type" validate:"required"`
 		ServiceDesc     string              `json:"service_desc" validate:"required"`
-		RatePerHour     float64             `json:"rate_per_hour" validate:"required"`
+		RatePerHour     models.Money        `json:"rate_per_hour" validate:"required"`
 		ExperienceYears int                 `json:"experience_years" validate:"required"`
 		Address         string              `json:"address" validate:"required"`
 		Availability    models.Availability `json:"availability" validate:"required"`
@@ -86,6 +86,16 @@ func (u UserHandler) CreateService(ctx echo.Context) error {
 		)
 	}
 
+	if err := reqData.Availability.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	reqData.Availability.Normalize()
+
+	location, err := geocodeAddress(ctx, u.app, reqData.Address)
+	if err != nil {
+		return err
+	}
+
 	newService := models.UserService{
 		UserID:          authUser.ID,
 		ServiceType:     reqData.ServiceType,
@@ -93,6 +103,7 @@ func (u UserHandler) CreateService(ctx echo.Context) error {
 		RatePerHour:     reqData.RatePerHour,
 		ExperienceYears: reqData.ExperienceYears,
 		Address:         reqData.Address,
+		Location:        location,
 		Availability:    reqData.Availability,
 	}
 
@@ -114,7 +125,7 @@ func (u UserHandler) UpdateService(ctx echo.Context) error {
 	reqData := struct {
 		ServiceType     string              `json:"service_type" validate:"required"`
 		ServiceDesc     string              `json:"service_desc" validate:"required"`
-		RatePerHour     float64             `json:"rate_per_hour" validate:"required"`
+		RatePerHour     models.Money        `json:"rate_per_hour" validate:"required"`
 		ExperienceYears int                 `json:"experience_years" validate:"required"`
 		Address         string              `json:"address" validate:"required"`
 		Availability    models.Availability `json:"availability" validate:"required"`
@@ -131,6 +142,9 @@ func (u UserHandler) UpdateService(ctx echo.Context) error {
 	if !util.IsValidServiceType(reqData.ServiceType) {
 		return echo.NewHTTPError(http.StatusBadRequest, ErrInvalidServiceType)
 	}
+	if !util.ValidPlaceAddress(reqData.Address) {
+		return echo.NewHTTPError(http.StatusBadRequest, ErrInvalidPlaceAddress)
+	}
 
 	authenticatedUser := util.ContextGetUser(ctx)
 	authUser, err := u.app.Repositories.User.GetByID(authenticatedUser.ID)
@@ -141,6 +155,16 @@ func (u UserHandler) UpdateService(ctx echo.Context) error {
 		return util.ErrInternalServer(ctx, err)
 	}
 
+	if err := reqData.Availability.Validate(); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	reqData.Availability.Normalize()
+
+	location, err := geocodeAddress(ctx, u.app, reqData.Address)
+	if err != nil {
+		return err
+	}
+
 	newService := models.UserService{
 		UserID:          authUser.ID,
 		ServiceType:     reqData.ServiceType,
@@ -148,6 +172,7 @@ func (u UserHandler) UpdateService(ctx echo.Context) error {
 		RatePerHour:     reqData.RatePerHour,
 		ExperienceYears: reqData.ExperienceYears,
 		Address:         reqData.Address,
+		Location:        location,
 		Availability:    reqData.Availability,
 	}
 
@@ -3,9 +3,13 @@ package handlers
 import (
 	"errors"
 	// "math"
+	"net/http"
 	"time"
 
+	"github.com/labstack/echo/v4"
+
 	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/geo"
 	"github.com/lokatalent/backend_go/internal/models"
 )
 
@@ -16,36 +20,54 @@ const (
 	// MAX_SCHEDULED_BOOKING_HOURS = 300   // maximum of 30 days and 10 hours a day.
 )
 
+// calculateBookingPrice prices a booking from the hourly rate of its
+// service type, counting whole minutes and rounding to the nearest kobo.
 func calculateBookingPrice(
 	app *util.Application,
 	serviceType, bookingType string,
 	startDate, endDate, startTime, endTime time.Time,
-) (float64, error) {
+) (models.Money, error) {
 	servicePrice, err := app.Repositories.ServicePricing.GetServicePricing(serviceType)
 	if err != nil {
-		return float64(0.0), err
+		return models.Money{}, err
 	}
 
-	var hours float64
+	var minutes int64
 	switch bookingType {
 	case models.BOOKING_INSTANT:
-		hours = endTime.Sub(startTime).Hours()
-		if hours < float64(0.0) || hours > float64(MAX_INSTANT_BOOKING_HOURS) {
-			return float64(0.0), errors.New("invalid start and end time.")
+		minutes = int64(endTime.Sub(startTime).Minutes())
+		if minutes < 0 || minutes > MAX_INSTANT_BOOKING_HOURS*60 {
+			return models.Money{}, errors.New("invalid start and end time.")
 		}
-		return servicePrice.RatePerHour * hours, nil
+		return servicePrice.RatePerHour.MulFrac(minutes, 60), nil
 	case models.BOOKING_SCHEDULED:
-		days := endDate.Sub(startDate).Hours() / DAY_IN_HOURS
-		if days < float64(0.0) || days > MAX_BOOKING_DAYS {
-			return float64(0.0), errors.New("invalid start and end days.")
+		days := int64(endDate.Sub(startDate).Hours() / DAY_IN_HOURS)
+		if days < 0 || days > MAX_BOOKING_DAYS {
+			return models.Money{}, errors.New("invalid start and end days.")
 		}
-		hours = endTime.Sub(startTime).Hours()
-		if hours < float64(0.0) || hours > float64(MAX_INSTANT_BOOKING_HOURS) {
-			return float64(0.0), errors.New("invalid start and end time.")
+		minutes = int64(endTime.Sub(startTime).Minutes())
+		if minutes < 0 || minutes > MAX_INSTANT_BOOKING_HOURS*60 {
+			return models.Money{}, errors.New("invalid start and end time.")
 		}
-		return servicePrice.RatePerHour * hours * days, nil
+		return servicePrice.RatePerHour.MulFrac(minutes*days, 60), nil
 
 	default:
-		return 0.0, errors.New("invalid booking type!")
+		return models.Money{}, errors.New("invalid booking type!")
+	}
+}
+
+// geocodeAddress resolves a place address to its location, for storing
+// alongside the address.
+func geocodeAddress(ctx echo.Context, app *util.Application, address string) (models.Location, error) {
+	location, err := app.Geocoder.Geocode(address)
+	if err != nil {
+		if errors.Is(err, geo.ErrAddressNotFound) {
+			return models.Location{}, echo.NewHTTPError(
+				http.StatusBadRequest,
+				ErrUnknownPlaceAddress,
+			)
+		}
+		return models.Location{}, util.ErrInternalServer(ctx, err)
 	}
+	return location, nil
 }
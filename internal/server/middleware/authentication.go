@@ -18,7 +18,7 @@ func Authentication(app *util.Application) echo.MiddlewareFunc {
 				echo.HeaderAuthorization,
 			)
 		},
-		SigningKey: []byte(app.Config.JWT.Access),
+		KeyFunc: app.Config.JWT.Keys.Keyfunc,
 		NewClaimsFunc: func(ctx echo.Context) jwt.Claims {
 			return &util.CustomAccessJWTClaims{}
 		},
@@ -34,7 +34,7 @@ func PublicAuthentication(app *util.Application) echo.MiddlewareFunc {
 				echo.HeaderAuthorization,
 			)
 		},
-		SigningKey: []byte(app.Config.JWT.Access),
+		KeyFunc: app.Config.JWT.Keys.Keyfunc,
 		NewClaimsFunc: func(ctx echo.Context) jwt.Claims {
 			return &util.CustomAccessJWTClaims{}
 		},
@@ -54,3 +54,23 @@ func PublicAuthentication(app *util.Application) echo.MiddlewareFunc {
 		},
 	})
 }
+
+// StreamAuthentication authenticates long-lived event streams. Browsers
+// can not set headers on an EventSource, so the access token may also be
+// given in the access_token query parameter.
+func StreamAuthentication(app *util.Application) echo.MiddlewareFunc {
+	return echojwt.WithConfig(echojwt.Config{
+		BeforeFunc: func(ctx echo.Context) {
+			ctx.Response().Header().Add(
+				echo.HeaderVary,
+				echo.HeaderAuthorization,
+			)
+		},
+		KeyFunc: app.Config.JWT.Keys.Keyfunc,
+		NewClaimsFunc: func(ctx echo.Context) jwt.Claims {
+			return &util.CustomAccessJWTClaims{}
+		},
+		ContextKey:  util.ContextKeyUser,
+		TokenLookup: "header:Authorization:Bearer ,query:access_token",
+	})
+}
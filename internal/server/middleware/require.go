@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/repository"
+)
+
+// Require ensures that the user attempting to access a resource has
+// been granted the permission by their role.
+func Require(app *util.Application, permission string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			principal, err := util.ContextGetPrincipal(ctx, app)
+			if err != nil {
+				if errors.Is(err, repository.ErrRecordNotFound) {
+					return echo.ErrUnauthorized
+				}
+				return util.ErrInternalServer(ctx, err)
+			}
+			if !principal.Can(permission) {
+				return &echo.HTTPError{
+					Code:    http.StatusForbidden,
+					Message: "Missing permission: " + permission,
+				}
+			}
+			return next(ctx)
+		}
+	}
+}
@@ -1,11 +1,14 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
 
 	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/models"
+	"github.com/lokatalent/backend_go/internal/repository"
 )
 
 // RequireVerification ensures that user attempting to access a resource
@@ -22,3 +25,25 @@ func RequireVerification(next echo.HandlerFunc) echo.HandlerFunc {
 		return next(ctx)
 	}
 }
+
+// RequireKYCApproval ensures that the service provider attempting to
+// access a resource has had their identity verification approved. It is
+// used along with RequireVerification for provider-only actions.
+func RequireKYCApproval(app *util.Application) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			user := util.ContextGetUser(ctx)
+			submission, err := app.Repositories.KYC.GetLatest(user.ID)
+			if err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
+				return util.ErrInternalServer(ctx, err)
+			}
+			if err != nil || submission.Status != models.KYC_APPROVED {
+				return &echo.HTTPError{
+					Code:    http.StatusForbidden,
+					Message: "Identity verification is not approved!",
+				}
+			}
+			return next(ctx)
+		}
+	}
+}
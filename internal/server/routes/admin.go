@@ -0,0 +1,87 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/rbac"
+	"github.com/lokatalent/backend_go/internal/server/handlers"
+	"github.com/lokatalent/backend_go/internal/server/middleware"
+)
+
+func setAdminRoutes(app *util.Application, engine *echo.Echo) {
+	auditHandler := handlers.NewAuditHandler(app)
+	kycHandler := handlers.NewKYCHandler(app)
+	certificationHandler := handlers.NewCertificationHandler(app)
+	outboxHandler := handlers.NewOutboxHandler(app)
+	jobHandler := handlers.NewJobHandler(app)
+
+	admin := engine.Group(
+		"admin",
+		middleware.Authentication(app),
+		middleware.RequireVerification,
+	)
+	admin.GET(
+		"/audit-log",
+		auditHandler.GetAuditLog,
+		middleware.Require(app, rbac.AUDIT_READ),
+	)
+	admin.GET(
+		"/audit-log/export",
+		auditHandler.ExportAuditLog,
+		middleware.Require(app, rbac.AUDIT_READ),
+	)
+
+	// identity verification reviews
+	admin.GET(
+		"/kyc",
+		kycHandler.GetKYCSubmissions,
+		middleware.Require(app, rbac.KYC_REVIEW),
+	)
+	admin.GET(
+		"/kyc/:id",
+		kycHandler.GetKYCSubmission,
+		middleware.Require(app, rbac.KYC_REVIEW),
+	)
+	admin.PATCH(
+		"/kyc/:id/review",
+		kycHandler.ReviewKYC,
+		middleware.Require(app, rbac.KYC_REVIEW),
+	)
+
+	// certification reviews
+	admin.GET(
+		"/certifications",
+		certificationHandler.GetCertificationQueue,
+		middleware.Require(app, rbac.CERTIFICATIONS_REVIEW),
+	)
+	admin.PATCH(
+		"/certifications/:id/review",
+		certificationHandler.ReviewCertification,
+		middleware.Require(app, rbac.CERTIFICATIONS_REVIEW),
+	)
+
+	// undelivered side effects
+	admin.GET(
+		"/outbox",
+		outboxHandler.GetOutbox,
+		middleware.Require(app, rbac.OUTBOX_MANAGE),
+	)
+	admin.POST(
+		"/outbox/:id/retry",
+		outboxHandler.RetryOutboxMessage,
+		middleware.Require(app, rbac.OUTBOX_MANAGE),
+	)
+
+	// background jobs
+	admin.GET(
+		"/jobs",
+		jobHandler.GetJobs,
+		middleware.Require(app, rbac.JOBS_MANAGE),
+	)
+	admin.POST(
+		"/jobs/:id/retry",
+		jobHandler.RetryJob,
+		middleware.Require(app, rbac.JOBS_MANAGE),
+	)
+}
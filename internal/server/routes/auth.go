@@ -11,12 +11,15 @@ import (
 func setAuthRoutes(app *util.Application, engine *echo.Echo) {
 	handler := handlers.NewAuthHandler(app)
 
+	engine.GET("/.well-known/jwks.json", handler.JWKS)
+
 	auth := engine.Group("auth")
 	auth.POST("/refresh-token", handler.RefreshToken)
 	auth.GET("/:provider", handler.ProviderAuthentication)
 	auth.GET("/:provider/callback", handler.ProviderAuthCallback)
+	auth.POST("/:provider/callback", handler.ProviderAuthCallback)
 	auth.POST("/signup", handler.SignUp)
-	auth.GET("/signin", handler.SignIn)
+	auth.POST("/signin", handler.SignIn)
 	auth.PATCH("/verify-user", handler.VerifyUser, middleware.Authentication(app))
 	auth.GET(
 		"/send-otp", handler.VerifyContact,
@@ -24,4 +27,44 @@ func setAuthRoutes(app *util.Application, engine *echo.Echo) {
 	auth.PATCH(
 		"/verify-otp", handler.VerifyContactCallback,
 		middleware.Authentication(app))
+
+	// password
+	auth.POST("/forgot-password", handler.ForgotPassword)
+	auth.POST("/reset-password", handler.ResetPassword)
+	auth.PATCH(
+		"/change-password", handler.ChangePassword,
+		middleware.Authentication(app))
+
+	// two-factor authentication
+	auth.POST("/2fa/challenge", handler.TwoFactorChallenge)
+	auth.POST(
+		"/2fa/enroll", handler.EnrollTwoFactor,
+		middleware.PublicAuthentication(app))
+	auth.POST(
+		"/2fa/enable", handler.EnableTwoFactor,
+		middleware.PublicAuthentication(app))
+	auth.POST(
+		"/2fa/disable", handler.DisableTwoFactor,
+		middleware.Authentication(app))
+	auth.POST(
+		"/2fa/recovery-codes", handler.RegenerateRecoveryCodes,
+		middleware.Authentication(app))
+
+	// linked provider identities
+	auth.GET(
+		"/identities", handler.GetIdentities,
+		middleware.Authentication(app))
+	auth.POST(
+		"/identities/:provider", handler.LinkIdentity,
+		middleware.Authentication(app))
+	auth.DELETE(
+		"/identities/:provider", handler.UnlinkIdentity,
+		middleware.Authentication(app))
+
+	// sessions
+	auth.POST("/logout", handler.Logout, middleware.Authentication(app))
+	auth.GET("/sessions", handler.GetSessions, middleware.Authentication(app))
+	auth.DELETE(
+		"/sessions/:id", handler.RevokeSession,
+		middleware.Authentication(app))
 }
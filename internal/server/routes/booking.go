@@ -41,6 +41,7 @@ func setBookingRoutes(app *util.Application, engine *echo.Echo) {
 		handler.AcceptBooking,
 		middleware.Authentication(app),
 		middleware.RequireVerification,
+		middleware.RequireKYCApproval(app),
 	)
 	booking.PATCH(
 		"/:id/reject",
@@ -48,6 +49,19 @@ func setBookingRoutes(app *util.Application, engine *echo.Echo) {
 		middleware.Authentication(app),
 		middleware.RequireVerification,
 	)
+	booking.PATCH(
+		"/:id/start",
+		handler.StartBooking,
+		middleware.Authentication(app),
+		middleware.RequireVerification,
+		middleware.RequireKYCApproval(app),
+	)
+	booking.GET(
+		"/:id/events",
+		handler.GetBookingEvents,
+		middleware.Authentication(app),
+		middleware.RequireVerification,
+	)
 	booking.GET(
 		"/:id/find-providers",
 		handler.FindProviders,
@@ -66,6 +66,11 @@ func Engine(app *util.Application) *echo.Echo {
 	setBookingRoutes(app, engine)
 	setNotificationRoutes(app, engine)
 	setPaymentRoutes(app, engine)
+	setLedgerRoutes(app, engine)
+	setReviewRoutes(app, engine)
+	setRoleRoutes(app, engine)
+	setKYCRoutes(app, engine)
+	setAdminRoutes(app, engine)
 
 	return engine
 }
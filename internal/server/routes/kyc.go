@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/server/handlers"
+	"github.com/lokatalent/backend_go/internal/server/middleware"
+)
+
+func setKYCRoutes(app *util.Application, engine *echo.Echo) {
+	handler := handlers.NewKYCHandler(app)
+
+	kyc := engine.Group("kyc", middleware.Authentication(app))
+	kyc.GET("", handler.GetOwnKYC)
+	kyc.POST("", handler.SubmitKYC)
+}
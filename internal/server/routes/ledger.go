@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/rbac"
+	"github.com/lokatalent/backend_go/internal/server/handlers"
+	"github.com/lokatalent/backend_go/internal/server/middleware"
+)
+
+func setLedgerRoutes(app *util.Application, engine *echo.Echo) {
+	handler := handlers.NewLedgerHandler(app)
+
+	ledger := engine.Group("ledger")
+	ledger.GET(
+		"/accounts",
+		handler.GetAccounts,
+		middleware.Authentication(app),
+		middleware.RequireVerification,
+		middleware.Require(app, rbac.LEDGER_READ),
+	)
+	ledger.GET(
+		"/entries",
+		handler.GetEntries,
+		middleware.Authentication(app),
+		middleware.RequireVerification,
+		middleware.Require(app, rbac.LEDGER_READ),
+	)
+}
@@ -36,4 +36,42 @@ func setNotificationRoutes(app *util.Application, engine *echo.Echo) {
 		middleware.Authentication(app),
 		middleware.RequireVerification,
 	)
+	notification.GET(
+		"/stream",
+		handler.Stream,
+		middleware.StreamAuthentication(app),
+		middleware.RequireVerification,
+	)
+
+	// preferences
+	notification.GET(
+		"/preferences",
+		handler.GetPreferences,
+		middleware.Authentication(app),
+	)
+	notification.PUT(
+		"/preferences/:type",
+		handler.SetPreference,
+		middleware.Authentication(app),
+	)
+	notification.PUT(
+		"/quiet-hours",
+		handler.SetQuietHours,
+		middleware.Authentication(app),
+	)
+	notification.DELETE(
+		"/quiet-hours",
+		handler.DeleteQuietHours,
+		middleware.Authentication(app),
+	)
+	notification.POST(
+		"/devices",
+		handler.RegisterDevice,
+		middleware.Authentication(app),
+	)
+	notification.DELETE(
+		"/devices",
+		handler.UnregisterDevice,
+		middleware.Authentication(app),
+	)
 }
@@ -24,4 +24,5 @@ func setPaymentRoutes(app *util.Application, engine *echo.Echo) {
 		middleware.Authentication(app),
 		middleware.RequireVerification,
 	)
+	payment.POST("/webhook", handler.Webhook)
 }
@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/server/handlers"
+	"github.com/lokatalent/backend_go/internal/server/middleware"
+)
+
+func setReviewRoutes(app *util.Application, engine *echo.Echo) {
+	handler := handlers.NewReviewHandler(app)
+
+	booking := engine.Group("booking")
+	booking.POST(
+		"/:id/review",
+		handler.CreateReview,
+		middleware.Authentication(app),
+		middleware.RequireVerification,
+	)
+	booking.GET(
+		"/:id/review",
+		handler.GetBookingReviews,
+		middleware.PublicAuthentication(app),
+	)
+
+	user := engine.Group("users")
+	user.GET(
+		"/:id/reviews",
+		handler.GetUserReviews,
+		middleware.PublicAuthentication(app),
+	)
+}
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/rbac"
+	"github.com/lokatalent/backend_go/internal/server/handlers"
+	"github.com/lokatalent/backend_go/internal/server/middleware"
+)
+
+func setRoleRoutes(app *util.Application, engine *echo.Echo) {
+	handler := handlers.NewRoleHandler(app)
+
+	role := engine.Group(
+		"roles",
+		middleware.Authentication(app),
+		middleware.RequireVerification,
+		middleware.Require(app, rbac.ROLES_MANAGE),
+	)
+	role.GET("", handler.GetRoles)
+	role.POST("", handler.CreateRole)
+	role.GET("/permissions", handler.GetPermissions)
+	role.PATCH("/:name", handler.UpdateRole)
+	role.DELETE("/:name", handler.DeleteRole)
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/labstack/echo/v4"
 
 	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/rbac"
 	"github.com/lokatalent/backend_go/internal/server/handlers"
 	"github.com/lokatalent/backend_go/internal/server/middleware"
 )
@@ -17,6 +18,7 @@ func setServicePricingRoutes(app *util.Application, engine *echo.Echo) {
 		handler.CreateServicePricing,
 		middleware.Authentication(app),
 		middleware.RequireVerification,
+		middleware.Require(app, rbac.PRICING_WRITE),
 	)
 	servicePricing.GET(
 		"",
@@ -35,12 +37,14 @@ func setServicePricingRoutes(app *util.Application, engine *echo.Echo) {
 		handler.UpdateServicePricing,
 		middleware.Authentication(app),
 		middleware.RequireVerification,
+		middleware.Require(app, rbac.PRICING_WRITE),
 	)
 	servicePricing.DELETE(
 		"",
 		handler.DeleteServicePricing,
 		middleware.Authentication(app),
 		middleware.RequireVerification,
+		middleware.Require(app, rbac.PRICING_WRITE),
 	)
 
 	// commission
@@ -49,6 +53,7 @@ func setServicePricingRoutes(app *util.Application, engine *echo.Echo) {
 		handler.CreateServiceCommission,
 		middleware.Authentication(app),
 		middleware.RequireVerification,
+		middleware.Require(app, rbac.COMMISSION_WRITE),
 	)
 	servicePricing.GET("/commission", handler.GetServiceCommission)
 	servicePricing.PATCH(
@@ -56,5 +61,6 @@ func setServicePricingRoutes(app *util.Application, engine *echo.Echo) {
 		handler.UpdateServiceCommission,
 		middleware.Authentication(app),
 		middleware.RequireVerification,
+		middleware.Require(app, rbac.COMMISSION_WRITE),
 	)
 }
@@ -4,6 +4,7 @@ import (
 	"github.com/labstack/echo/v4"
 
 	"github.com/lokatalent/backend_go/cmd/api/util"
+	"github.com/lokatalent/backend_go/internal/rbac"
 	"github.com/lokatalent/backend_go/internal/server/handlers"
 	"github.com/lokatalent/backend_go/internal/server/middleware"
 )
@@ -16,34 +17,24 @@ func setUserRoutes(app *util.Application, engine *echo.Echo) {
 	user.GET("/search", handler.Search, middleware.PublicAuthentication(app))
 	user.PATCH(
 		"/:id/set-role", handler.ChangeRole, middleware.Authentication(app),
-		middleware.RequireVerification)
+		middleware.RequireVerification,
+		middleware.Require(app, rbac.USERS_ROLE_CHANGE))
 	user.PATCH(
 		"/set-service-role", handler.ChangeServiceRole,
 		middleware.Authentication(app), middleware.RequireVerification)
 
 	// profile
 	user.GET("/profile", handler.GetOwnProfile, middleware.Authentication(app))
-	user.GET(
-		"/profile/education",
-		handler.GetOwnEducationProfile, middleware.Authentication(app))
 	user.GET(
 		"/profile/bank",
 		handler.GetOwnBankProfile, middleware.Authentication(app))
 	user.GET(
 		"/:id/profile", handler.GetProfile, middleware.PublicAuthentication(app))
-	user.GET(
-		"/:id/profile/education",
-		handler.GetEducationProfile, middleware.PublicAuthentication(app))
 	user.PATCH(
 		"/profile",
 		handler.UpdateProfilePersonal,
 		middleware.Authentication(app),
 	)
-	user.PATCH(
-		"/profile/education",
-		handler.UpdateProfileEducation,
-		middleware.Authentication(app),
-	)
 	user.PATCH(
 		"/profile/bank",
 		handler.UpdateProfileBank,
@@ -56,6 +47,32 @@ func setUserRoutes(app *util.Application, engine *echo.Echo) {
 		"/profile/picture-delete", handler.DeleteProfileImage,
 		middleware.Authentication(app), middleware.RequireVerification)
 
+	// education and work history
+	user.GET(
+		"/:id/education",
+		handler.GetEducation, middleware.PublicAuthentication(app))
+	user.POST(
+		"/:id/education",
+		handler.CreateEducation, middleware.Authentication(app))
+	user.PUT(
+		"/:id/education/:entry_id",
+		handler.UpdateEducation, middleware.Authentication(app))
+	user.DELETE(
+		"/:id/education/:entry_id",
+		handler.DeleteEducation, middleware.Authentication(app))
+	user.GET(
+		"/:id/experience",
+		handler.GetExperience, middleware.PublicAuthentication(app))
+	user.POST(
+		"/:id/experience",
+		handler.CreateExperience, middleware.Authentication(app))
+	user.PUT(
+		"/:id/experience/:entry_id",
+		handler.UpdateExperience, middleware.Authentication(app))
+	user.DELETE(
+		"/:id/experience/:entry_id",
+		handler.DeleteExperience, middleware.Authentication(app))
+
 	user.GET(
 		"/:id/profile/certifications",
 		handler.GetCertifications,
@@ -98,6 +115,38 @@ func setUserRoutes(app *util.Application, engine *echo.Echo) {
 		middleware.Authentication(app),
 	)
 
+	// availability
+	user.GET(
+		"/:id/availability",
+		handler.CheckAvailability,
+		middleware.Authentication(app),
+	)
+	user.PUT(
+		"/service/availability",
+		handler.UpdateServiceAvailability,
+		middleware.Authentication(app),
+	)
+	user.GET(
+		"/:id/availability/exceptions",
+		handler.GetAvailabilityExceptions,
+		middleware.Authentication(app),
+	)
+	user.POST(
+		"/availability/exceptions",
+		handler.CreateAvailabilityException,
+		middleware.Authentication(app),
+	)
+	user.PATCH(
+		"/availability/exceptions/:id",
+		handler.UpdateAvailabilityException,
+		middleware.Authentication(app),
+	)
+	user.DELETE(
+		"/availability/exceptions/:id",
+		handler.DeleteAvailabilityException,
+		middleware.Authentication(app),
+	)
+
 	// wallet
 	user.GET(
 		"/wallet",
@@ -111,6 +160,18 @@ func setUserRoutes(app *util.Application, engine *echo.Echo) {
 		middleware.Authentication(app),
 	)
 
+	// personal data
+	user.GET("/export", handler.ExportData, middleware.Authentication(app))
+	user.GET(
+		"/deletion", handler.GetAccountDeletion,
+		middleware.Authentication(app))
+	user.POST(
+		"/deletion", handler.RequestAccountDeletion,
+		middleware.Authentication(app))
+	user.DELETE(
+		"/deletion", handler.CancelAccountDeletion,
+		middleware.Authentication(app))
+
 	user.POST(
 		"/waitlist",
 		handler.JoinWaitlist,
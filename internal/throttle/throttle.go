@@ -0,0 +1,95 @@
+// Package throttle defines how failed authentication attempts lock out an
+// account or an IP address. Failures are counted within a window; reaching
+// the limit locks the key out for a period that doubles with each
+// consecutive lockout.
+package throttle
+
+import (
+	"time"
+
+	"github.com/lokatalent/backend_go/internal/models"
+)
+
+// key prefixes
+const (
+	KEY_ACCOUNT        = "account:"
+	KEY_IP             = "ip:"
+	KEY_PASSWORD_RESET = "password_reset:"
+)
+
+// Policy configures the lockout of one kind of key.
+type Policy struct {
+	MaxFailures int
+	// Window is how long failures are remembered for.
+	Window time.Duration
+	// BaseLockout is the duration of the first lockout.
+	BaseLockout time.Duration
+	MaxLockout  time.Duration
+	// LockoutReset is how long after a lockout ends the next one
+	// starts again from BaseLockout.
+	LockoutReset time.Duration
+}
+
+var (
+	Account = Policy{
+		MaxFailures:  5,
+		Window:       15 * time.Minute,
+		BaseLockout:  time.Minute,
+		MaxLockout:   24 * time.Hour,
+		LockoutReset: 24 * time.Hour,
+	}
+	IP = Policy{
+		MaxFailures:  20,
+		Window:       15 * time.Minute,
+		BaseLockout:  time.Minute,
+		MaxLockout:   24 * time.Hour,
+		LockoutReset: 24 * time.Hour,
+	}
+	// PasswordReset limits how often a password reset is issued for an
+	// account, each issue counting as a failure.
+	PasswordReset = Policy{
+		MaxFailures:  3,
+		Window:       time.Hour,
+		BaseLockout:  15 * time.Minute,
+		MaxLockout:   24 * time.Hour,
+		LockoutReset: 24 * time.Hour,
+	}
+)
+
+func AccountKey(userID string) string {
+	return KEY_ACCOUNT + userID
+}
+
+func IPKey(ip string) string {
+	return KEY_IP + ip
+}
+
+func PasswordResetKey(userID string) string {
+	return KEY_PASSWORD_RESET + userID
+}
+
+// ShouldLock reports whether the failures recorded for the key reach the
+// policy's limit.
+func (p Policy) ShouldLock(throttle models.AuthThrottle) bool {
+	return throttle.Failures >= p.MaxFailures
+}
+
+// LockUntil returns when the next lockout of the key ends, along with the
+// number of consecutive lockouts it makes.
+func (p Policy) LockUntil(throttle models.AuthThrottle, now time.Time) (time.Time, int) {
+	lockouts := throttle.Lockouts
+	if throttle.LockedUntil != nil && now.Sub(*throttle.LockedUntil) > p.LockoutReset {
+		lockouts = 0
+	}
+
+	duration := p.BaseLockout
+	for range lockouts {
+		duration *= 2
+		if duration >= p.MaxLockout {
+			duration = p.MaxLockout
+			break
+		}
+	}
+
+	return now.Add(duration), lockouts + 1
+}
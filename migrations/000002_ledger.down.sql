@@ -0,0 +1,33 @@
+ALTER TABLE IF EXISTS "wallets"
+	ADD COLUMN IF NOT EXISTS "credits" DECIMAL(10,2) NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS "debits" DECIMAL(10,2) NOT NULL DEFAULT 0;
+
+-- restore wallet totals from the requester wallet postings.
+UPDATE "wallets" w
+SET
+	"credits" = COALESCE((
+		SELECT -SUM(p.amount)
+		FROM ledger_postings p
+		JOIN ledger_accounts a ON a.id = p.account_id
+		WHERE a.user_id = w.user_id
+			AND a.type = 'requester_wallet'
+			AND p.amount < 0
+	), 0),
+	"debits" = COALESCE((
+		SELECT SUM(p.amount)
+		FROM ledger_postings p
+		JOIN ledger_accounts a ON a.id = p.account_id
+		WHERE a.user_id = w.user_id
+			AND a.type = 'requester_wallet'
+			AND p.amount > 0
+	), 0);
+
+DROP TABLE IF EXISTS "ledger_postings";
+
+DROP TABLE IF EXISTS "ledger_entries";
+
+DROP TABLE IF EXISTS "ledger_accounts";
+
+DROP FUNCTION IF EXISTS "reject_ledger_mutation";
+
+DROP FUNCTION IF EXISTS "check_ledger_entry_balanced";
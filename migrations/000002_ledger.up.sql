@@ -0,0 +1,150 @@
+-- double-entry ledger backing wallets, earnings and commission.
+--
+-- Every posting amount is signed: debits are positive and credits are
+-- negative, so the postings of a journal entry always sum to zero.
+CREATE TABLE IF NOT EXISTS "ledger_accounts" (
+  "id"			UUID PRIMARY KEY NOT NULL,
+  "type"		TEXT NOT NULL,
+  "user_id"		UUID, -- NULL for system accounts.
+  "currency"	TEXT NOT NULL DEFAULT 'NGN',
+  "created_at"	TIMESTAMPTZ NOT NULL DEFAULT 'now()'
+);
+
+CREATE TABLE IF NOT EXISTS "ledger_entries" (
+  "id"			UUID PRIMARY KEY NOT NULL,
+  "description"	TEXT NOT NULL DEFAULT '',
+  "payment_id"	UUID,
+  "booking_id"	UUID,
+  "created_at"	TIMESTAMPTZ NOT NULL DEFAULT 'now()'
+);
+
+CREATE TABLE IF NOT EXISTS "ledger_postings" (
+  "id"			UUID PRIMARY KEY NOT NULL,
+  "entry_id"	UUID NOT NULL,
+  "account_id"	UUID NOT NULL,
+  "amount"		DECIMAL(12,2) NOT NULL CHECK ("amount" <> 0),
+  "created_at"	TIMESTAMPTZ NOT NULL DEFAULT 'now()'
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_ledger_account_type_user
+	ON "ledger_accounts" (
+		"type",
+		(COALESCE("user_id", '00000000-0000-0000-0000-000000000000'::UUID))
+	);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_postings_account_id
+	ON "ledger_postings" ("account_id");
+
+CREATE INDEX IF NOT EXISTS idx_ledger_postings_entry_id
+	ON "ledger_postings" ("entry_id");
+
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_booking_id
+	ON "ledger_entries" ("booking_id");
+
+ALTER TABLE IF EXISTS "ledger_accounts"
+	ADD FOREIGN KEY ("user_id")
+	REFERENCES "users" ("id");
+
+ALTER TABLE IF EXISTS "ledger_entries"
+	ADD FOREIGN KEY ("payment_id")
+	REFERENCES "payments" ("id");
+
+ALTER TABLE IF EXISTS "ledger_entries"
+	ADD FOREIGN KEY ("booking_id")
+	REFERENCES "bookings" ("id");
+
+ALTER TABLE IF EXISTS "ledger_postings"
+	ADD FOREIGN KEY ("entry_id")
+	REFERENCES "ledger_entries" ("id");
+
+ALTER TABLE IF EXISTS "ledger_postings"
+	ADD FOREIGN KEY ("account_id")
+	REFERENCES "ledger_accounts" ("id");
+
+-- reject journal entries whose postings do not sum to zero, checked when
+-- the inserting transaction commits.
+CREATE OR REPLACE FUNCTION check_ledger_entry_balanced()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF (
+        SELECT COALESCE(SUM(amount), 0)
+        FROM ledger_postings
+        WHERE entry_id = NEW.entry_id
+    ) <> 0 THEN
+        RAISE EXCEPTION 'ledger entry % is not balanced', NEW.entry_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER ledger_postings_balanced
+	AFTER INSERT ON "ledger_postings"
+	DEFERRABLE INITIALLY DEFERRED
+	FOR EACH ROW EXECUTE FUNCTION check_ledger_entry_balanced();
+
+-- the ledger is append-only, corrections are made with new entries.
+CREATE OR REPLACE FUNCTION reject_ledger_mutation()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'ledger table % is append-only', TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER ledger_entries_append_only
+	BEFORE UPDATE OR DELETE ON "ledger_entries"
+	FOR EACH ROW EXECUTE FUNCTION reject_ledger_mutation();
+
+CREATE TRIGGER ledger_postings_append_only
+	BEFORE UPDATE OR DELETE ON "ledger_postings"
+	FOR EACH ROW EXECUTE FUNCTION reject_ledger_mutation();
+
+-- system accounts
+INSERT INTO "ledger_accounts" ("id", "type") VALUES
+	(gen_random_uuid(), 'platform_commission'),
+	(gen_random_uuid(), 'paystack_clearing'),
+	(gen_random_uuid(), 'booking_escrow')
+ON CONFLICT DO NOTHING;
+
+-- carry existing wallet totals over as opening entries, crediting what
+-- was paid in and debiting what was spent against the clearing account.
+DO $$
+DECLARE
+    wallet RECORD;
+    entry_id UUID;
+    wallet_account_id UUID;
+    clearing_account_id UUID;
+BEGIN
+    SELECT id INTO clearing_account_id
+    FROM ledger_accounts
+    WHERE type = 'paystack_clearing' AND user_id IS NULL;
+
+    FOR wallet IN SELECT user_id, credits, debits FROM wallets LOOP
+        wallet_account_id := gen_random_uuid();
+        INSERT INTO ledger_accounts (id, type, user_id)
+        VALUES (wallet_account_id, 'requester_wallet', wallet.user_id);
+
+        IF wallet.credits <> 0 THEN
+            entry_id := gen_random_uuid();
+            INSERT INTO ledger_entries (id, description)
+            VALUES (entry_id, 'opening wallet credits');
+            INSERT INTO ledger_postings (id, entry_id, account_id, amount) VALUES
+                (gen_random_uuid(), entry_id, clearing_account_id, wallet.credits),
+                (gen_random_uuid(), entry_id, wallet_account_id, -wallet.credits);
+        END IF;
+
+        IF wallet.debits <> 0 THEN
+            entry_id := gen_random_uuid();
+            INSERT INTO ledger_entries (id, description)
+            VALUES (entry_id, 'opening wallet debits');
+            INSERT INTO ledger_postings (id, entry_id, account_id, amount) VALUES
+                (gen_random_uuid(), entry_id, wallet_account_id, wallet.debits),
+                (gen_random_uuid(), entry_id, clearing_account_id, -wallet.debits);
+        END IF;
+    END LOOP;
+END;
+$$;
+
+-- wallet balances are now derived from the ledger.
+ALTER TABLE IF EXISTS "wallets"
+	DROP COLUMN IF EXISTS "credits",
+	DROP COLUMN IF EXISTS "debits";
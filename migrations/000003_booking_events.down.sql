@@ -0,0 +1,8 @@
+-- map statuses introduced by the booking lifecycle back to the old ones.
+UPDATE "bookings" SET "status" = 'open', "provider_id" = NULL
+WHERE "status" = 'provider_selected';
+
+UPDATE "bookings" SET "status" = 'in_progress'
+WHERE "status" IN ('accepted', 'disputed');
+
+DROP TABLE IF EXISTS "booking_events";
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS "booking_events" (
+  "id"			UUID PRIMARY KEY NOT NULL,
+  "booking_id"	UUID NOT NULL,
+  "action"		TEXT NOT NULL,
+  "actor"		TEXT NOT NULL, -- requester, provider, admin or system.
+  "actor_id"	UUID, -- NULL for system actions.
+  "from_status"	TEXT NOT NULL,
+  "to_status"	TEXT NOT NULL,
+  "provider_id"	UUID,
+  "created_at"	TIMESTAMPTZ NOT NULL DEFAULT 'now()'
+);
+
+CREATE INDEX IF NOT EXISTS idx_booking_events_booking_id
+	ON "booking_events" ("booking_id", "created_at");
+
+ALTER TABLE IF EXISTS "booking_events"
+	ADD FOREIGN KEY ("booking_id")
+	REFERENCES "bookings" ("id");
+
+ALTER TABLE IF EXISTS "booking_events"
+	ADD FOREIGN KEY ("actor_id")
+	REFERENCES "users" ("id");
+
+ALTER TABLE IF EXISTS "booking_events"
+	ADD FOREIGN KEY ("provider_id")
+	REFERENCES "users" ("id");
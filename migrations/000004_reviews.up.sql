@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS "reviews" (
+  "id"			UUID PRIMARY KEY NOT NULL,
+  "booking_id"	UUID NOT NULL,
+  "reviewer_id"	UUID NOT NULL,
+  "reviewee_id"	UUID NOT NULL,
+  "reviewer_role"	TEXT NOT NULL, -- requester or provider.
+  "rating"		SMALLINT NOT NULL CHECK ("rating" BETWEEN 1 AND 5),
+  "comment"		TEXT NOT NULL DEFAULT '',
+  "created_at"	TIMESTAMPTZ NOT NULL DEFAULT 'now()',
+  "updated_at"	TIMESTAMPTZ NOT NULL DEFAULT 'now()'
+);
+
+-- one review per side of a booking.
+CREATE UNIQUE INDEX IF NOT EXISTS unique_booking_id_reviewer_role
+	ON "reviews" ("booking_id", "reviewer_role");
+
+CREATE INDEX IF NOT EXISTS idx_reviews_reviewee_id
+	ON "reviews" ("reviewee_id", "created_at");
+
+ALTER TABLE IF EXISTS "reviews"
+	ADD FOREIGN KEY ("booking_id")
+	REFERENCES "bookings" ("id");
+
+ALTER TABLE IF EXISTS "reviews"
+	ADD FOREIGN KEY ("reviewer_id")
+	REFERENCES "users" ("id");
+
+ALTER TABLE IF EXISTS "reviews"
+	ADD FOREIGN KEY ("reviewee_id")
+	REFERENCES "users" ("id");
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_services_service_type_lat_lng;
+
+ALTER TABLE IF EXISTS "bookings"
+	DROP COLUMN IF EXISTS "longitude",
+	DROP COLUMN IF EXISTS "latitude",
+	DROP COLUMN IF EXISTS "requester_location";
+
+ALTER TABLE IF EXISTS "services"
+	DROP COLUMN IF EXISTS "longitude",
+	DROP COLUMN IF EXISTS "latitude",
+	DROP COLUMN IF EXISTS "location";
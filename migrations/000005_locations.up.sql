@@ -0,0 +1,56 @@
+-- geocoded addresses. latitude and longitude are derived from the
+-- location so proximity queries can use a plain index.
+ALTER TABLE IF EXISTS "services"
+	ADD COLUMN IF NOT EXISTS "location" JSONB NOT NULL DEFAULT '{}',
+	ADD COLUMN IF NOT EXISTS "latitude" DOUBLE PRECISION GENERATED ALWAYS AS (
+		("location"->>'latitude')::DOUBLE PRECISION
+	) STORED,
+	ADD COLUMN IF NOT EXISTS "longitude" DOUBLE PRECISION GENERATED ALWAYS AS (
+		("location"->>'longitude')::DOUBLE PRECISION
+	) STORED;
+
+ALTER TABLE IF EXISTS "bookings"
+	ADD COLUMN IF NOT EXISTS "requester_location" JSONB NOT NULL DEFAULT '{}',
+	ADD COLUMN IF NOT EXISTS "latitude" DOUBLE PRECISION GENERATED ALWAYS AS (
+		("requester_location"->>'latitude')::DOUBLE PRECISION
+	) STORED,
+	ADD COLUMN IF NOT EXISTS "longitude" DOUBLE PRECISION GENERATED ALWAYS AS (
+		("requester_location"->>'longitude')::DOUBLE PRECISION
+	) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_services_service_type_lat_lng
+	ON "services" ("service_type", "latitude", "longitude");
+
+-- split existing "street_addr, city, state, country" addresses into
+-- components. Coordinates are filled in when the address is next saved.
+UPDATE "services" s
+SET "location" = jsonb_build_object(
+	'street_addr', trim(array_to_string(a.parts[1:a.n - 3], ',')),
+	'city', trim(a.parts[a.n - 2]),
+	'state', trim(a.parts[a.n - 1]),
+	'country', trim(a.parts[a.n])
+)
+FROM (
+	SELECT "id", parts, array_length(parts, 1) AS n
+	FROM (
+		SELECT "id", string_to_array("address", ',') AS parts
+		FROM "services"
+	) split
+) a
+WHERE s."id" = a."id" AND a.n >= 4;
+
+UPDATE "bookings" b
+SET "requester_location" = jsonb_build_object(
+	'street_addr', trim(array_to_string(a.parts[1:a.n - 3], ',')),
+	'city', trim(a.parts[a.n - 2]),
+	'state', trim(a.parts[a.n - 1]),
+	'country', trim(a.parts[a.n])
+)
+FROM (
+	SELECT "id", parts, array_length(parts, 1) AS n
+	FROM (
+		SELECT "id", string_to_array("requester_addr", ',') AS parts
+		FROM "bookings"
+	) split
+) a
+WHERE b."id" = a."id" AND a.n >= 4;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_bookings_provider_id_status;
+
+DROP FUNCTION IF EXISTS provider_booked(UUID, TEXT[], TIMESTAMPTZ, TIMESTAMPTZ);
+DROP FUNCTION IF EXISTS availability_covers(JSONB, TEXT[], TIME, TIME);
@@ -0,0 +1,60 @@
+-- availability_covers reports whether a weekly availability schedule
+-- covers start_time to end_time on every one of days, given as lowercase
+-- weekday names. Ranges that are missing or not "HH:MM[:SS]" cover
+-- nothing.
+CREATE OR REPLACE FUNCTION availability_covers(
+	availability JSONB,
+	days TEXT[],
+	start_time TIME,
+	end_time TIME
+)
+RETURNS BOOLEAN AS $$
+DECLARE
+	day TEXT;
+	range_start TIME;
+	range_end TIME;
+BEGIN
+	FOREACH day IN ARRAY days LOOP
+		BEGIN
+			range_start := (availability->day->>'start')::TIME;
+			range_end := (availability->day->>'end')::TIME;
+		EXCEPTION WHEN others THEN
+			RETURN false;
+		END;
+
+		IF range_start IS NULL OR range_end IS NULL OR
+			range_start > start_time OR range_end < end_time THEN
+			RETURN false;
+		END IF;
+	END LOOP;
+	RETURN true;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+-- provider_booked reports whether a provider has a booking in one of
+-- statuses overlapping [starts_at, ends_at) on any of its days. A booking
+-- occupies start_time to end_time on each day from start_date to
+-- end_date, not the whole span between them.
+CREATE OR REPLACE FUNCTION provider_booked(
+	provider_id UUID,
+	statuses TEXT[],
+	starts_at TIMESTAMPTZ,
+	ends_at TIMESTAMPTZ
+)
+RETURNS BOOLEAN AS $$
+	SELECT EXISTS (
+		SELECT 1
+		FROM bookings b,
+			generate_series(b.start_date, b.end_date, INTERVAL '1 day') AS day
+		WHERE
+			b.provider_id = provider_booked.provider_id
+			AND b.status = ANY(statuses)
+			AND b.start_date <= (ends_at + INTERVAL '1 day')::DATE
+			AND b.end_date >= (starts_at - INTERVAL '1 day')::DATE
+			AND day::DATE + b.start_time < ends_at
+			AND day::DATE + b.end_time > starts_at
+	);
+$$ LANGUAGE sql STABLE;
+
+CREATE INDEX IF NOT EXISTS idx_bookings_provider_id_status
+	ON "bookings" ("provider_id", "status");
@@ -0,0 +1,48 @@
+DROP FUNCTION IF EXISTS service_available(UUID, JSONB, DATE, DATE, TIMETZ, TIMETZ);
+
+-- restore single range schedules, keeping the first range of each day.
+UPDATE "services"
+SET "availability" = COALESCE((
+	SELECT jsonb_object_agg(
+		key,
+		CASE
+			WHEN jsonb_typeof(value) = 'array' AND jsonb_array_length(value) > 0
+				THEN value->0
+			WHEN jsonb_typeof(value) = 'object' THEN value
+			ELSE jsonb_build_object('start', '', 'end', '')
+		END
+	)
+	FROM jsonb_each("availability" - 'timezone')
+), '{}'::JSONB)
+WHERE jsonb_typeof("availability") = 'object';
+
+CREATE OR REPLACE FUNCTION availability_covers(
+	availability JSONB,
+	days TEXT[],
+	start_time TIME,
+	end_time TIME
+)
+RETURNS BOOLEAN AS $$
+DECLARE
+	day TEXT;
+	range_start TIME;
+	range_end TIME;
+BEGIN
+	FOREACH day IN ARRAY days LOOP
+		BEGIN
+			range_start := (availability->day->>'start')::TIME;
+			range_end := (availability->day->>'end')::TIME;
+		EXCEPTION WHEN others THEN
+			RETURN false;
+		END;
+
+		IF range_start IS NULL OR range_end IS NULL OR
+			range_start > start_time OR range_end < end_time THEN
+			RETURN false;
+		END IF;
+	END LOOP;
+	RETURN true;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+DROP TABLE IF EXISTS "availability_exceptions";
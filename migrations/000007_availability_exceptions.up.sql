@@ -0,0 +1,143 @@
+CREATE TABLE IF NOT EXISTS "availability_exceptions" (
+  "id"			UUID PRIMARY KEY NOT NULL,
+  "user_id"		UUID NOT NULL,
+  "kind"		TEXT NOT NULL, -- blackout or available.
+  "starts_at"	TIMESTAMPTZ NOT NULL,
+  "ends_at"		TIMESTAMPTZ NOT NULL,
+  "reason"		TEXT NOT NULL DEFAULT '',
+  "created_at"	TIMESTAMPTZ NOT NULL DEFAULT 'now()',
+  "updated_at"	TIMESTAMPTZ NOT NULL DEFAULT 'now()',
+  CHECK ("ends_at" > "starts_at")
+);
+
+CREATE INDEX IF NOT EXISTS idx_availability_exceptions_user_id
+	ON "availability_exceptions" ("user_id", "starts_at");
+
+ALTER TABLE IF EXISTS "availability_exceptions"
+	ADD FOREIGN KEY ("user_id")
+	REFERENCES "users" ("id") ON DELETE CASCADE;
+
+-- weekly schedules hold a list of ranges per day and a timezone.
+UPDATE "services"
+SET "availability" = COALESCE((
+	SELECT jsonb_object_agg(
+		key,
+		CASE
+			WHEN jsonb_typeof(value) = 'array' THEN value
+			WHEN jsonb_typeof(value) = 'object'
+				AND COALESCE(value->>'start', '') <> ''
+				AND COALESCE(value->>'end', '') <> ''
+				THEN jsonb_build_array(value)
+			ELSE '[]'::JSONB
+		END
+	)
+	FROM jsonb_each("availability")
+), '{}'::JSONB) || jsonb_build_object('timezone', 'Africa/Lagos')
+WHERE jsonb_typeof("availability") = 'object'
+	AND NOT "availability" ? 'timezone';
+
+DROP FUNCTION IF EXISTS availability_covers(JSONB, TEXT[], TIME, TIME);
+
+-- service_available reports whether a provider is available from
+-- start_time to end_time on every day from start_date to end_date. Each
+-- day must be covered by the union of the weekly ranges, read in the
+-- schedule's timezone, and extra availability exceptions, and must not
+-- overlap a blackout exception. Ranges that are not "HH:MM" cover
+-- nothing.
+CREATE OR REPLACE FUNCTION service_available(
+	provider_id UUID,
+	availability JSONB,
+	start_date DATE,
+	end_date DATE,
+	start_time TIMETZ,
+	end_time TIMETZ
+)
+RETURNS BOOLEAN AS $$
+DECLARE
+	weekdays CONSTANT TEXT[] := ARRAY[
+		'monday', 'tuesday', 'wednesday', 'thursday',
+		'friday', 'saturday', 'sunday'
+	];
+	tz TEXT;
+	day DATE;
+	occurrence_start TIMESTAMPTZ;
+	occurrence_end TIMESTAMPTZ;
+	reached TIMESTAMPTZ;
+	range_start TIMESTAMPTZ;
+	range_end TIMESTAMPTZ;
+BEGIN
+	tz := COALESCE(NULLIF(availability->>'timezone', ''), 'Africa/Lagos');
+
+	FOR day IN
+		SELECT generate_series(start_date, end_date, INTERVAL '1 day')::DATE
+	LOOP
+		occurrence_start := day + start_time;
+		occurrence_end := day + end_time;
+		IF occurrence_end <= occurrence_start THEN
+			RETURN false;
+		END IF;
+
+		IF EXISTS (
+			SELECT 1
+			FROM availability_exceptions e
+			WHERE
+				e.user_id = provider_id
+				AND e.kind = 'blackout'
+				AND e.starts_at < occurrence_end
+				AND e.ends_at > occurrence_start
+		) THEN
+			RETURN false;
+		END IF;
+
+		reached := occurrence_start;
+		FOR range_start, range_end IN
+			SELECT starts_at, ends_at
+			FROM (
+				SELECT
+					(local_day + (r->>'start')::TIME) AT TIME ZONE tz AS starts_at,
+					(local_day + (r->>'end')::TIME) AT TIME ZONE tz AS ends_at
+				FROM generate_series(
+					(occurrence_start AT TIME ZONE tz)::DATE,
+					(occurrence_end AT TIME ZONE tz)::DATE,
+					INTERVAL '1 day'
+				) AS local_day,
+				jsonb_array_elements(
+					CASE
+						WHEN jsonb_typeof(
+							availability->(weekdays[EXTRACT(ISODOW FROM local_day)::INT])
+						) = 'array'
+						THEN availability->(weekdays[EXTRACT(ISODOW FROM local_day)::INT])
+						ELSE '[]'::JSONB
+					END
+				) AS r
+				WHERE
+					r->>'start' ~ '^([01]?[0-9]|2[0-3]):[0-5][0-9]$'
+					AND r->>'end' ~ '^(([01]?[0-9]|2[0-3]):[0-5][0-9]|24:00)$'
+
+				UNION ALL
+
+				SELECT e.starts_at, e.ends_at
+				FROM availability_exceptions e
+				WHERE
+					e.user_id = provider_id
+					AND e.kind = 'available'
+					AND e.starts_at < occurrence_end
+					AND e.ends_at > occurrence_start
+			) ranges
+			ORDER BY starts_at
+		LOOP
+			EXIT WHEN range_start > reached;
+			IF range_end > reached THEN
+				reached := range_end;
+			END IF;
+			EXIT WHEN reached >= occurrence_end;
+		END LOOP;
+
+		IF reached < occurrence_end THEN
+			RETURN false;
+		END IF;
+	END LOOP;
+
+	RETURN true;
+END;
+$$ LANGUAGE plpgsql STABLE;
@@ -0,0 +1,40 @@
+CREATE TABLE IF NOT EXISTS "sessions" (
+  "id"			UUID PRIMARY KEY NOT NULL,
+  "user_id"		UUID NOT NULL,
+  "device"		TEXT NOT NULL DEFAULT '',
+  "user_agent"	TEXT NOT NULL DEFAULT '',
+  "ip_address"	TEXT NOT NULL DEFAULT '',
+  "expires_at"	TIMESTAMPTZ NOT NULL, -- expiry of the latest refresh token.
+  "revoked_at"	TIMESTAMPTZ,
+  "last_used_at"	TIMESTAMPTZ NOT NULL DEFAULT 'now()',
+  "created_at"	TIMESTAMPTZ NOT NULL DEFAULT 'now()'
+);
+
+-- every refresh token issued for a session; a session is a token family.
+CREATE TABLE IF NOT EXISTS "refresh_tokens" (
+  "id"			UUID PRIMARY KEY NOT NULL,
+  "session_id"	UUID NOT NULL,
+  "token_hash"	TEXT NOT NULL,
+  "expires_at"	TIMESTAMPTZ NOT NULL,
+  "used_at"		TIMESTAMPTZ, -- set once the token is rotated.
+  "created_at"	TIMESTAMPTZ NOT NULL DEFAULT 'now()'
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_refresh_token_hash
+	ON "refresh_tokens" ("token_hash");
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_session_id
+	ON "refresh_tokens" ("session_id");
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id
+	ON "sessions" ("user_id", "last_used_at");
+
+ALTER TABLE IF EXISTS "sessions"
+	ADD FOREIGN KEY ("user_id")
+	REFERENCES "users" ("id")
+	ON DELETE CASCADE;
+
+ALTER TABLE IF EXISTS "refresh_tokens"
+	ADD FOREIGN KEY ("session_id")
+	REFERENCES "sessions" ("id")
+	ON DELETE CASCADE;
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS "password_resets" (
+  "id"			UUID PRIMARY KEY NOT NULL,
+  "user_id"		UUID NOT NULL,
+  "token_hash"	TEXT NOT NULL,
+  "channel"		TEXT NOT NULL, -- email or phone.
+  "attempts"	INT NOT NULL DEFAULT 0,
+  "created_at"	TIMESTAMPTZ NOT NULL DEFAULT 'now()',
+  "expires_at"	TIMESTAMPTZ NOT NULL DEFAULT now() + INTERVAL '30 minutes'
+);
+
+-- only one pending reset per user.
+CREATE UNIQUE INDEX IF NOT EXISTS unique_password_resets_user_id
+	ON "password_resets" ("user_id");
+
+ALTER TABLE IF EXISTS "password_resets"
+	ADD FOREIGN KEY ("user_id")
+	REFERENCES "users" ("id")
+	ON DELETE CASCADE;
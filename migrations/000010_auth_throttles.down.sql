@@ -0,0 +1,4 @@
+ALTER TABLE IF EXISTS "contact_verifications"
+	DROP COLUMN IF EXISTS "attempts";
+
+DROP TABLE IF EXISTS "auth_throttles";
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS "auth_throttles" (
+  "key"			TEXT PRIMARY KEY NOT NULL, -- account:<user id> or ip:<address>.
+  "failures"		INT NOT NULL DEFAULT 0,
+  "lockouts"		INT NOT NULL DEFAULT 0, -- consecutive lockouts.
+  "locked_until"	TIMESTAMPTZ,
+  "updated_at"	TIMESTAMPTZ NOT NULL DEFAULT 'now()'
+);
+
+ALTER TABLE IF EXISTS "contact_verifications"
+	ADD COLUMN IF NOT EXISTS "attempts" INT NOT NULL DEFAULT 0;
@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS "two_factor" (
+  "user_id"			UUID PRIMARY KEY NOT NULL,
+  "secret"			TEXT NOT NULL,
+  "enabled"			BOOLEAN NOT NULL DEFAULT false,
+  "last_used_step"	BIGINT NOT NULL DEFAULT 0, -- rejects replayed codes.
+  "confirmed_at"	TIMESTAMPTZ,
+  "created_at"		TIMESTAMPTZ NOT NULL DEFAULT 'now()',
+  "updated_at"		TIMESTAMPTZ NOT NULL DEFAULT 'now()'
+);
+
+CREATE TABLE IF NOT EXISTS "recovery_codes" (
+  "id"			UUID PRIMARY KEY NOT NULL,
+  "user_id"		UUID NOT NULL,
+  "code_hash"	TEXT NOT NULL,
+  "used_at"		TIMESTAMPTZ,
+  "created_at"	TIMESTAMPTZ NOT NULL DEFAULT 'now()'
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_recovery_codes_user_id_code_hash
+	ON "recovery_codes" ("user_id", "code_hash");
+
+ALTER TABLE IF EXISTS "two_factor"
+	ADD FOREIGN KEY ("user_id")
+	REFERENCES "users" ("id")
+	ON DELETE CASCADE;
+
+ALTER TABLE IF EXISTS "recovery_codes"
+	ADD FOREIGN KEY ("user_id")
+	REFERENCES "users" ("id")
+	ON DELETE CASCADE;
@@ -0,0 +1,10 @@
+-- users of custom roles fall back to regular.
+UPDATE "users" SET "role" = 'regular'
+WHERE "role" NOT IN ('regular', 'admin', 'admin_super');
+
+ALTER TABLE IF EXISTS "users"
+	DROP CONSTRAINT IF EXISTS "users_role_fkey";
+
+DROP TABLE IF EXISTS "role_permissions";
+DROP TABLE IF EXISTS "permissions";
+DROP TABLE IF EXISTS "roles";
@@ -0,0 +1,67 @@
+CREATE TABLE IF NOT EXISTS "roles" (
+  "name"				TEXT PRIMARY KEY NOT NULL,
+  "description"			TEXT NOT NULL DEFAULT '',
+  "requires_two_factor"	BOOLEAN NOT NULL DEFAULT false,
+  "builtin"				BOOLEAN NOT NULL DEFAULT false,
+  "created_at"			TIMESTAMPTZ NOT NULL DEFAULT 'now()',
+  "updated_at"			TIMESTAMPTZ NOT NULL DEFAULT 'now()'
+);
+
+CREATE TABLE IF NOT EXISTS "permissions" (
+  "name"			TEXT PRIMARY KEY NOT NULL,
+  "description"		TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS "role_permissions" (
+  "role"		TEXT NOT NULL,
+  "permission"	TEXT NOT NULL,
+  PRIMARY KEY ("role", "permission")
+);
+
+ALTER TABLE IF EXISTS "role_permissions"
+	ADD FOREIGN KEY ("role")
+	REFERENCES "roles" ("name")
+	ON UPDATE CASCADE ON DELETE CASCADE;
+
+ALTER TABLE IF EXISTS "role_permissions"
+	ADD FOREIGN KEY ("permission")
+	REFERENCES "permissions" ("name")
+	ON UPDATE CASCADE ON DELETE CASCADE;
+
+INSERT INTO "permissions" ("name", "description") VALUES
+	('users:read:any', 'View the full details of any user.'),
+	('users:role:change', 'Assign roles to users.'),
+	('roles:manage', 'Create, update and delete roles.'),
+	('booking:read:any', 'View any booking and its history.'),
+	('booking:manage', 'Act on any booking as an admin.'),
+	('pricing:write', 'Create, update and delete service pricing.'),
+	('commission:write', 'Create and update service commission.'),
+	('ledger:read', 'View ledger accounts and entries.')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO "roles" ("name", "description", "requires_two_factor", "builtin") VALUES
+	('regular', 'Service requesters and providers.', false, true),
+	('admin', 'Platform administrators.', true, true),
+	('admin_super', 'Platform administrators with full access.', true, true)
+ON CONFLICT DO NOTHING;
+
+-- admins get what they had before roles were configurable.
+INSERT INTO "role_permissions" ("role", "permission") VALUES
+	('admin', 'users:read:any'),
+	('admin', 'booking:read:any'),
+	('admin', 'booking:manage'),
+	('admin', 'ledger:read'),
+	('admin_super', 'users:read:any'),
+	('admin_super', 'users:role:change'),
+	('admin_super', 'roles:manage'),
+	('admin_super', 'booking:read:any'),
+	('admin_super', 'booking:manage'),
+	('admin_super', 'pricing:write'),
+	('admin_super', 'commission:write'),
+	('admin_super', 'ledger:read')
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE IF EXISTS "users"
+	ADD FOREIGN KEY ("role")
+	REFERENCES "roles" ("name")
+	ON UPDATE CASCADE;
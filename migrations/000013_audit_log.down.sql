@@ -0,0 +1,3 @@
+DELETE FROM "permissions" WHERE "name" = 'audit:read';
+
+DROP TABLE IF EXISTS "audit_logs";
@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS "audit_logs" (
+  "id"			UUID PRIMARY KEY NOT NULL,
+  "actor_id"	UUID,
+  "action"		TEXT NOT NULL, -- e.g. user.role.change
+  "entity_type"	TEXT NOT NULL,
+  "entity_id"	TEXT NOT NULL DEFAULT '',
+  "before"		JSONB, -- changed fields only.
+  "after"		JSONB,
+  "ip_address"	TEXT NOT NULL DEFAULT '',
+  "user_agent"	TEXT NOT NULL DEFAULT '',
+  "created_at"	TIMESTAMPTZ NOT NULL DEFAULT 'now()'
+);
+
+CREATE INDEX IF NOT EXISTS "audit_logs_actor_idx"
+	ON "audit_logs" ("actor_id", "created_at");
+
+CREATE INDEX IF NOT EXISTS "audit_logs_entity_idx"
+	ON "audit_logs" ("entity_type", "entity_id", "created_at");
+
+CREATE INDEX IF NOT EXISTS "audit_logs_created_at_idx"
+	ON "audit_logs" ("created_at");
+
+-- entries outlive the users who made them.
+ALTER TABLE IF EXISTS "audit_logs"
+	ADD FOREIGN KEY ("actor_id")
+	REFERENCES "users" ("id")
+	ON DELETE SET NULL;
+
+INSERT INTO "permissions" ("name", "description") VALUES
+	('audit:read', 'View and export the audit log.')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO "role_permissions" ("role", "permission") VALUES
+	('admin_super', 'audit:read')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS "user_identities" (
+  "id"			UUID PRIMARY KEY NOT NULL,
+  "user_id"		UUID NOT NULL,
+  "provider"	TEXT NOT NULL, -- google, facebook or apple.
+  "subject"		TEXT NOT NULL, -- the provider's ID for the user.
+  "email"		TEXT NOT NULL DEFAULT '',
+  "created_at"	TIMESTAMPTZ NOT NULL DEFAULT 'now()'
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "unique_identity_subject"
+	ON "user_identities" ("provider", "subject");
+
+-- one identity per provider for each user.
+CREATE UNIQUE INDEX IF NOT EXISTS "unique_identity_user_provider"
+	ON "user_identities" ("user_id", "provider");
+
+ALTER TABLE IF EXISTS "user_identities"
+	ADD FOREIGN KEY ("user_id")
+	REFERENCES "users" ("id")
+	ON DELETE CASCADE;
@@ -0,0 +1,17 @@
+-- self-service account deletions. Once the grace period ends the user's
+-- personal data is erased, while the bookings, payments and ledger
+-- entries they took part in are kept against the anonymized user.
+CREATE TABLE IF NOT EXISTS "account_deletions" (
+  "user_id"			UUID PRIMARY KEY NOT NULL,
+  "requested_at"	TIMESTAMPTZ NOT NULL DEFAULT 'now()',
+  "scheduled_for"	TIMESTAMPTZ NOT NULL,
+  "completed_at"	TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_account_deletions_pending
+	ON "account_deletions" ("scheduled_for")
+	WHERE "completed_at" IS NULL;
+
+ALTER TABLE IF EXISTS "account_deletions"
+	ADD FOREIGN KEY ("user_id")
+	REFERENCES "users" ("id");
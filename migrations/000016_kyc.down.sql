@@ -0,0 +1,3 @@
+DELETE FROM "permissions" WHERE "name" = 'kyc:review';
+
+DROP TABLE IF EXISTS "kyc_submissions";
@@ -0,0 +1,46 @@
+-- identity verification of service providers. Documents are stored
+-- privately, and each submission is reviewed by an admin.
+CREATE TABLE IF NOT EXISTS "kyc_submissions" (
+  "id"				UUID PRIMARY KEY NOT NULL,
+  "user_id"			UUID NOT NULL,
+  "document_type"	TEXT NOT NULL,
+  "document_key"	TEXT NOT NULL,
+  "selfie_key"		TEXT NOT NULL,
+  -- pending, approved, rejected or needs_resubmission.
+  "status"			TEXT NOT NULL DEFAULT 'pending',
+  "reason"			TEXT NOT NULL DEFAULT '',
+  "reviewer_id"		UUID,
+  "reviewed_at"		TIMESTAMPTZ,
+  "created_at"		TIMESTAMPTZ NOT NULL DEFAULT 'now()',
+  "updated_at"		TIMESTAMPTZ NOT NULL DEFAULT 'now()'
+);
+
+-- a user has at most one submission awaiting review.
+CREATE UNIQUE INDEX IF NOT EXISTS "unique_kyc_pending_user"
+	ON "kyc_submissions" ("user_id")
+	WHERE "status" = 'pending';
+
+CREATE INDEX IF NOT EXISTS idx_kyc_submissions_user_id
+	ON "kyc_submissions" ("user_id", "created_at");
+
+CREATE INDEX IF NOT EXISTS idx_kyc_submissions_status
+	ON "kyc_submissions" ("status", "created_at");
+
+ALTER TABLE IF EXISTS "kyc_submissions"
+	ADD FOREIGN KEY ("user_id")
+	REFERENCES "users" ("id")
+	ON DELETE CASCADE;
+
+ALTER TABLE IF EXISTS "kyc_submissions"
+	ADD FOREIGN KEY ("reviewer_id")
+	REFERENCES "users" ("id")
+	ON DELETE SET NULL;
+
+INSERT INTO "permissions" ("name", "description") VALUES
+	('kyc:review', 'Review identity verifications of service providers.')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO "role_permissions" ("role", "permission") VALUES
+	('admin_super', 'kyc:review'),
+	('admin', 'kyc:review')
+ON CONFLICT DO NOTHING;
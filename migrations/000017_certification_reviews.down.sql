@@ -0,0 +1,16 @@
+DELETE FROM "permissions" WHERE "name" = 'certifications:review';
+
+DROP INDEX IF EXISTS idx_users_certifications_status;
+DROP INDEX IF EXISTS idx_users_certifications_user_id;
+
+ALTER TABLE IF EXISTS "users_certifications"
+	DROP COLUMN IF EXISTS "title",
+	DROP COLUMN IF EXISTS "issuer",
+	DROP COLUMN IF EXISTS "issued_on",
+	DROP COLUMN IF EXISTS "expires_on",
+	DROP COLUMN IF EXISTS "status",
+	DROP COLUMN IF EXISTS "review_notes",
+	DROP COLUMN IF EXISTS "reviewer_id",
+	DROP COLUMN IF EXISTS "reviewed_at",
+	DROP COLUMN IF EXISTS "created_at",
+	DROP COLUMN IF EXISTS "updated_at";
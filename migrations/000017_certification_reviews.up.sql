@@ -0,0 +1,34 @@
+-- certification details and their review. Only approved certifications
+-- that have not expired are shown on public profiles.
+ALTER TABLE IF EXISTS "users_certifications"
+	ADD COLUMN IF NOT EXISTS "title" TEXT NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS "issuer" TEXT NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS "issued_on" DATE,
+	ADD COLUMN IF NOT EXISTS "expires_on" DATE, -- NULL if it never expires.
+	-- pending, approved, rejected or expired.
+	ADD COLUMN IF NOT EXISTS "status" TEXT NOT NULL DEFAULT 'pending',
+	ADD COLUMN IF NOT EXISTS "review_notes" TEXT NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS "reviewer_id" UUID,
+	ADD COLUMN IF NOT EXISTS "reviewed_at" TIMESTAMPTZ,
+	ADD COLUMN IF NOT EXISTS "created_at" TIMESTAMPTZ NOT NULL DEFAULT 'now()',
+	ADD COLUMN IF NOT EXISTS "updated_at" TIMESTAMPTZ NOT NULL DEFAULT 'now()';
+
+CREATE INDEX IF NOT EXISTS idx_users_certifications_user_id
+	ON "users_certifications" ("user_id");
+
+CREATE INDEX IF NOT EXISTS idx_users_certifications_status
+	ON "users_certifications" ("status", "created_at");
+
+ALTER TABLE IF EXISTS "users_certifications"
+	ADD FOREIGN KEY ("reviewer_id")
+	REFERENCES "users" ("id")
+	ON DELETE SET NULL;
+
+INSERT INTO "permissions" ("name", "description") VALUES
+	('certifications:review', 'Review certifications uploaded by users.')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO "role_permissions" ("role", "permission") VALUES
+	('admin_super', 'certifications:review'),
+	('admin', 'certifications:review')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,40 @@
+CREATE TABLE IF NOT EXISTS "users_education_info" (
+  "user_id"		UUID PRIMARY KEY NOT NULL,
+  "institute"	TEXT NOT NULL,
+  "degree"		TEXT NOT NULL,
+  "discipline"	TEXT NOT NULL,
+  "start"		DATE NOT NULL DEFAULT '0001-01-01',
+  "finish"		DATE NOT NULL DEFAULT 'now()',
+  "created_at"	TIMESTAMPTZ NOT NULL DEFAULT 'now()',
+  "updated_at"	TIMESTAMPTZ NOT NULL DEFAULT 'now()'
+);
+
+ALTER TABLE IF EXISTS "users_education_info"
+	ADD FOREIGN KEY ("user_id")
+	REFERENCES "users" ("id");
+
+-- only the latest entry of each user is kept.
+INSERT INTO "users_education_info" (
+	"user_id",
+	"institute",
+	"degree",
+	"discipline",
+	"start",
+	"finish",
+	"created_at",
+	"updated_at"
+)
+SELECT DISTINCT ON ("user_id")
+	"user_id",
+	"institute",
+	"degree",
+	"discipline",
+	"start",
+	COALESCE("finish", CURRENT_DATE),
+	"created_at",
+	"updated_at"
+FROM "users_education"
+ORDER BY "user_id", "start" DESC;
+
+DROP TABLE IF EXISTS "users_experience";
+DROP TABLE IF EXISTS "users_education";
@@ -0,0 +1,65 @@
+-- users record any number of education and work experience entries.
+CREATE TABLE IF NOT EXISTS "users_education" (
+  "id"			UUID PRIMARY KEY NOT NULL,
+  "user_id"		UUID NOT NULL,
+  "institute"	TEXT NOT NULL,
+  "degree"		TEXT NOT NULL,
+  "discipline"	TEXT NOT NULL,
+  "start"		DATE NOT NULL,
+  "finish"		DATE, -- NULL while still studying.
+  "created_at"	TIMESTAMPTZ NOT NULL DEFAULT 'now()',
+  "updated_at"	TIMESTAMPTZ NOT NULL DEFAULT 'now()'
+);
+
+CREATE TABLE IF NOT EXISTS "users_experience" (
+  "id"			UUID PRIMARY KEY NOT NULL,
+  "user_id"		UUID NOT NULL,
+  "employer"	TEXT NOT NULL,
+  "role"		TEXT NOT NULL,
+  "start"		DATE NOT NULL,
+  "finish"		DATE, -- NULL while still employed.
+  "description"	TEXT NOT NULL DEFAULT '',
+  "created_at"	TIMESTAMPTZ NOT NULL DEFAULT 'now()',
+  "updated_at"	TIMESTAMPTZ NOT NULL DEFAULT 'now()'
+);
+
+CREATE INDEX IF NOT EXISTS idx_users_education_user_id
+	ON "users_education" ("user_id", "start");
+
+CREATE INDEX IF NOT EXISTS idx_users_experience_user_id
+	ON "users_experience" ("user_id", "start");
+
+ALTER TABLE IF EXISTS "users_education"
+	ADD FOREIGN KEY ("user_id")
+	REFERENCES "users" ("id")
+	ON DELETE CASCADE;
+
+ALTER TABLE IF EXISTS "users_experience"
+	ADD FOREIGN KEY ("user_id")
+	REFERENCES "users" ("id")
+	ON DELETE CASCADE;
+
+INSERT INTO "users_education" (
+	"id",
+	"user_id",
+	"institute",
+	"degree",
+	"discipline",
+	"start",
+	"finish",
+	"created_at",
+	"updated_at"
+)
+SELECT
+	gen_random_uuid(),
+	"user_id",
+	"institute",
+	"degree",
+	"discipline",
+	"start",
+	"finish",
+	"created_at",
+	"updated_at"
+FROM "users_education_info";
+
+DROP TABLE IF EXISTS "users_education_info";
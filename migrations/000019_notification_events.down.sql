@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS notifications_seen ON "notifications";
+DROP TRIGGER IF EXISTS notifications_created ON "notifications";
+DROP FUNCTION IF EXISTS notify_notification_change();
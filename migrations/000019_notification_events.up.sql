@@ -0,0 +1,49 @@
+-- announce new notifications, and changes to a user's unread count, to
+-- the API instances listening on the "notifications" channel. Events are
+-- sent when the writing transaction commits.
+CREATE OR REPLACE FUNCTION notify_notification_change()
+RETURNS TRIGGER AS $$
+DECLARE
+    unread BIGINT;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        PERFORM pg_notify('notifications', json_build_object(
+            'user_id', NEW.user_id,
+            'type', 'notification',
+            'data', json_build_object(
+                'id', NEW.id,
+                'type', NEW.type,
+                'user_id', NEW.user_id,
+                'booking_id', COALESCE(NEW.booking_id::TEXT, ''),
+                'seen', NEW.seen,
+                -- payloads are limited to 8000 bytes.
+                'message', LEFT(NEW.message, 2000),
+                'created_at', NEW.created_at
+            )
+        )::TEXT);
+    END IF;
+
+    SELECT count(*) INTO unread
+    FROM notifications
+    WHERE user_id = NEW.user_id AND seen = false;
+
+    -- identical events of a transaction are delivered once, so marking
+    -- many notifications as read sends a single count.
+    PERFORM pg_notify('notifications', json_build_object(
+        'user_id', NEW.user_id,
+        'type', 'unread_count',
+        'data', json_build_object('count', unread)
+    )::TEXT);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER notifications_created
+	AFTER INSERT ON "notifications"
+	FOR EACH ROW EXECUTE FUNCTION notify_notification_change();
+
+CREATE TRIGGER notifications_seen
+	AFTER UPDATE OF "seen" ON "notifications"
+	FOR EACH ROW
+	WHEN (OLD.seen IS DISTINCT FROM NEW.seen)
+	EXECUTE FUNCTION notify_notification_change();
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "push_devices";
+DROP TABLE IF EXISTS "notification_quiet_hours";
+DROP TABLE IF EXISTS "notification_preferences";
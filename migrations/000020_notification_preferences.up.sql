@@ -0,0 +1,43 @@
+-- channels each type of notification is sent on, for users who changed
+-- them from the defaults.
+CREATE TABLE IF NOT EXISTS "notification_preferences" (
+  "user_id"		UUID NOT NULL,
+  "type"		TEXT NOT NULL,
+  -- in_app, email, sms or push.
+  "channels"	TEXT[] NOT NULL DEFAULT '{}',
+  "updated_at"	TIMESTAMPTZ NOT NULL DEFAULT 'now()',
+  PRIMARY KEY ("user_id", "type")
+);
+
+CREATE TABLE IF NOT EXISTS "notification_quiet_hours" (
+  "user_id"		UUID PRIMARY KEY NOT NULL,
+  "start"		TEXT NOT NULL, -- HH:MM
+  "end"			TEXT NOT NULL, -- HH:MM
+  "timezone"	TEXT NOT NULL,
+  "updated_at"	TIMESTAMPTZ NOT NULL DEFAULT 'now()'
+);
+
+CREATE TABLE IF NOT EXISTS "push_devices" (
+  "token"		TEXT PRIMARY KEY NOT NULL,
+  "user_id"		UUID NOT NULL,
+  "platform"	TEXT NOT NULL, -- android or ios.
+  "created_at"	TIMESTAMPTZ NOT NULL DEFAULT 'now()'
+);
+
+CREATE INDEX IF NOT EXISTS idx_push_devices_user_id
+	ON "push_devices" ("user_id");
+
+ALTER TABLE IF EXISTS "notification_preferences"
+	ADD FOREIGN KEY ("user_id")
+	REFERENCES "users" ("id")
+	ON DELETE CASCADE;
+
+ALTER TABLE IF EXISTS "notification_quiet_hours"
+	ADD FOREIGN KEY ("user_id")
+	REFERENCES "users" ("id")
+	ON DELETE CASCADE;
+
+ALTER TABLE IF EXISTS "push_devices"
+	ADD FOREIGN KEY ("user_id")
+	REFERENCES "users" ("id")
+	ON DELETE CASCADE;
@@ -0,0 +1,3 @@
+DELETE FROM "permissions" WHERE "name" = 'outbox:manage';
+
+DROP TABLE IF EXISTS "outbox";
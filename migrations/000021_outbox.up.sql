@@ -0,0 +1,33 @@
+-- side effects recorded in the same transaction as the change causing
+-- them, and delivered afterwards by the outbox relay.
+CREATE TABLE IF NOT EXISTS "outbox" (
+  "id"				UUID PRIMARY KEY NOT NULL,
+  -- notification, email, sms, push or transfer.
+  "kind"			TEXT NOT NULL,
+  "payload"			JSONB NOT NULL,
+  -- pending, delivered or dead.
+  "status"			TEXT NOT NULL DEFAULT 'pending',
+  "attempts"		INT NOT NULL DEFAULT 0,
+  "last_error"		TEXT NOT NULL DEFAULT '',
+  -- pending messages are not delivered before this time, while waiting
+  -- to be retried or being delivered.
+  "available_at"	TIMESTAMPTZ NOT NULL DEFAULT 'now()',
+  "created_at"		TIMESTAMPTZ NOT NULL DEFAULT 'now()',
+  "delivered_at"	TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_pending
+	ON "outbox" ("available_at")
+	WHERE "status" = 'pending';
+
+CREATE INDEX IF NOT EXISTS idx_outbox_status
+	ON "outbox" ("status", "created_at");
+
+INSERT INTO "permissions" ("name", "description") VALUES
+	('outbox:manage', 'View and retry undelivered outbox messages.')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO "role_permissions" ("role", "permission") VALUES
+	('admin_super', 'outbox:manage'),
+	('admin', 'outbox:manage')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,4 @@
+DELETE FROM "permissions" WHERE "name" = 'jobs:manage';
+
+DROP TABLE IF EXISTS "job_schedules";
+DROP TABLE IF EXISTS "jobs";
@@ -0,0 +1,41 @@
+-- background jobs, claimed by the workers of any API instance.
+CREATE TABLE IF NOT EXISTS "jobs" (
+  "id"				UUID PRIMARY KEY NOT NULL,
+  "kind"			TEXT NOT NULL,
+  "payload"			JSONB NOT NULL DEFAULT '{}',
+  -- pending, running, completed or failed.
+  "status"			TEXT NOT NULL DEFAULT 'pending',
+  "attempts"		INT NOT NULL DEFAULT 0,
+  "max_attempts"	INT NOT NULL DEFAULT 1,
+  "last_error"		TEXT NOT NULL DEFAULT '',
+  "run_at"			TIMESTAMPTZ NOT NULL DEFAULT 'now()',
+  -- running jobs not finished by this time are claimed again, as their
+  -- worker is assumed to have stopped.
+  "locked_until"	TIMESTAMPTZ,
+  "created_at"		TIMESTAMPTZ NOT NULL DEFAULT 'now()',
+  "updated_at"		TIMESTAMPTZ NOT NULL DEFAULT 'now()',
+  "finished_at"		TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_jobs_pending
+	ON "jobs" ("run_at")
+	WHERE "status" = 'pending';
+
+CREATE INDEX IF NOT EXISTS idx_jobs_status
+	ON "jobs" ("status", "created_at");
+
+-- next run of each scheduled job, shared by all instances so that each
+-- run is enqueued once.
+CREATE TABLE IF NOT EXISTS "job_schedules" (
+  "name"			TEXT PRIMARY KEY NOT NULL,
+  "next_run_at"		TIMESTAMPTZ NOT NULL
+);
+
+INSERT INTO "permissions" ("name", "description") VALUES
+	('jobs:manage', 'View and retry failed background jobs.')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO "role_permissions" ("role", "permission") VALUES
+	('admin_super', 'jobs:manage'),
+	('admin', 'jobs:manage')
+ON CONFLICT DO NOTHING;